@@ -1,10 +1,21 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	jirapb "github.com/conallob/jira-beads-sync/gen/jira"
 	"github.com/conallob/jira-beads-sync/internal/beads"
 	"github.com/conallob/jira-beads-sync/internal/config"
 	"github.com/conallob/jira-beads-sync/internal/converter"
@@ -18,10 +29,58 @@ var (
 	date    = "unknown"
 )
 
+// profile is the config profile selected with --profile; JIRA_PROFILE or the
+// default profile is used if empty
+var profile string
+
+// noCache is set by the global --no-cache flag to bypass the response cache
+var noCache bool
+
+// recordFile is the fixture file the Jira requests are recorded to, set by
+// the global --record flag
+var recordFile string
+
+// recorder records the Jira requests to recordFile once a client is created
+var recorder *jira.Recorder
+
+// exitWarnings is the exit status of a lenient conversion that completed but
+// skipped or repaired issues
+const exitWarnings = 2
+
+// errConversionWarnings is returned by a lenient conversion that completed
+// but skipped or repaired issues
+var errConversionWarnings = errors.New("conversion completed with warnings")
+
+// oauth2AuthorizeTimeout is how long configure waits for the user to
+// authorize the OAuth app in the browser
+const oauth2AuthorizeTimeout = 5 * time.Minute
+
 func main() {
+	os.Exit(run())
+}
+
+// run runs the command line and returns the exit status. Deferred cleanup,
+// such as closing a recording, runs before main exits.
+func run() int {
+	var err error
+	profile, os.Args, err = extractProfileFlag(os.Args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		printUsage()
+		return 1
+	}
+	noCache, os.Args = extractNoCacheFlag(os.Args)
+	recordFile, os.Args, err = extractRecordFlag(os.Args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		printUsage()
+		return 1
+	}
+	defer closeRecorder()
+
 	if len(os.Args) < 2 {
 		printUsage()
-		os.Exit(1)
+		return 1
 	}
 
 	command := os.Args[1]
@@ -31,63 +90,88 @@ func main() {
 		if len(os.Args) < 3 {
 			fmt.Fprintf(os.Stderr, "Error: quickstart requires a Jira URL or issue key\n\n")
 			printUsage()
-			os.Exit(1)
+			return 1
 		}
 		if err := runQuickstart(os.Args[2]); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return 1
 		}
 	case "fetch-by-label", "label":
 		if len(os.Args) < 3 {
 			fmt.Fprintf(os.Stderr, "Error: fetch-by-label requires a label argument\n\n")
 			printUsage()
-			os.Exit(1)
+			return 1
 		}
 		if err := runFetchByLabel(os.Args[2]); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return 1
 		}
 	case "fetch-jql", "jql":
 		if len(os.Args) < 3 {
 			fmt.Fprintf(os.Stderr, "Error: fetch-jql requires a JQL query argument\n\n")
 			printUsage()
-			os.Exit(1)
+			return 1
 		}
 		// Join all remaining args as the JQL query
 		jqlQuery := strings.Join(os.Args[2:], " ")
 		if err := runFetchByJQL(jqlQuery); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return 1
 		}
-	case "annotate":
-		if len(os.Args) < 4 {
-			fmt.Fprintf(os.Stderr, "Error: annotate requires <issue-id> and <repository> arguments\n\n")
+	case "fetch-sprint", "sprint":
+		if err := runFetchSprint(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	case "fetch-board", "board":
+		if len(os.Args) < 3 {
+			fmt.Fprintf(os.Stderr, "Error: fetch-board requires a board ID argument\n\n")
 			printUsage()
-			os.Exit(1)
+			return 1
 		}
-		if err := runAnnotate(os.Args[2], os.Args[3]); err != nil {
+		if err := runFetchBoard(os.Args[2]); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return 1
+		}
+	case "annotate":
+		if err := runAnnotate(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
 		}
 	case "convert":
 		if len(os.Args) < 3 {
 			fmt.Fprintf(os.Stderr, "Error: convert requires a file argument\n\n")
 			printUsage()
-			os.Exit(1)
+			return 1
 		}
-		if err := runConvert(os.Args[2]); err != nil {
+		if err := runConvert(os.Args[2:]); err != nil {
+			if errors.Is(err, errConversionWarnings) {
+				return exitWarnings
+			}
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return 1
+		}
+	case "reconvert":
+		if err := runReconvert(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
 		}
 	case "configure", "config":
-		if err := runConfigure(); err != nil {
+		if len(os.Args) > 2 && os.Args[2] == "migrate-secret" {
+			if err := runMigrateSecret(os.Args[3:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			break
+		}
+		if err := runConfigure(os.Args[2:]); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return 1
 		}
 	case "whoami":
 		if err := runWhoami(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return 1
 		}
 	case "version":
 		fmt.Printf("jira-beads-sync %s\n", version)
@@ -98,8 +182,66 @@ func main() {
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", command)
 		printUsage()
-		os.Exit(1)
+		return 1
 	}
+
+	return 0
+}
+
+// extractProfileFlag removes the global --profile flag from the command
+// line, returning the selected profile and the remaining arguments
+func extractProfileFlag(args []string) (string, []string, error) {
+	return extractValueFlag(args, "profile", "a profile name")
+}
+
+// extractRecordFlag removes the global --record flag from the command line,
+// returning the fixture file to record to and the remaining arguments
+func extractRecordFlag(args []string) (string, []string, error) {
+	return extractValueFlag(args, "record", "a fixture file")
+}
+
+// extractValueFlag removes a global flag taking a value from the command
+// line, wherever it appears, returning its value and the remaining arguments
+func extractValueFlag(args []string, name, valueName string) (string, []string, error) {
+	selected := ""
+	rest := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--"+name || arg == "-"+name:
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("--%s requires %s", name, valueName)
+			}
+			selected = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--"+name+"="):
+			selected = strings.TrimPrefix(arg, "--"+name+"=")
+		case strings.HasPrefix(arg, "-"+name+"="):
+			selected = strings.TrimPrefix(arg, "-"+name+"=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+
+	return selected, rest, nil
+}
+
+// extractNoCacheFlag removes the global --no-cache flag from the command
+// line, returning whether it was given and the remaining arguments
+func extractNoCacheFlag(args []string) (bool, []string) {
+	found := false
+	rest := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		if arg == "--no-cache" || arg == "-no-cache" {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+
+	return found, rest
 }
 
 func runQuickstart(urlOrKey string) error {
@@ -107,25 +249,17 @@ func runQuickstart(urlOrKey string) error {
 	fmt.Println("========================")
 	fmt.Println()
 
-	// Load configuration
-	cfg, err := config.Load()
+	// For a URL, use the profile of the Jira instance it points to
+	issueURL := ""
+	if isURL(urlOrKey) {
+		issueURL = urlOrKey
+	}
+	cfg, err := loadConfigForURLOrPrompt(issueURL)
 	if err != nil {
-		fmt.Println("⚠ No configuration found. Let's set it up!")
-		fmt.Println()
-		cfg, err = config.PromptForConfig()
-		if err != nil {
-			return fmt.Errorf("failed to configure: %w", err)
-		}
-		if err := cfg.Save(); err != nil {
-			fmt.Printf("⚠ Warning: failed to save config: %v\n", err)
-		} else {
-			fmt.Println("✓ Configuration saved")
-			fmt.Println()
-		}
+		return err
 	}
-
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w. Run 'jira-beads-sync configure' to set up", err)
+	if cfg.Profile != "" {
+		fmt.Printf("Using profile: %s\n", cfg.Profile)
 	}
 
 	// Parse issue key from URL if needed
@@ -153,7 +287,12 @@ func runQuickstart(urlOrKey string) error {
 	fmt.Println()
 
 	// Create Jira client
-	client := jira.NewClient(baseURL, cfg.Jira.Username, cfg.Jira.APIToken, cfg.Jira.AuthMethod)
+	client, err := newJiraClient(cfg, baseURL)
+	if err != nil {
+		return err
+	}
+	snapshot := jira.NewSnapshot("quickstart")
+	client.SetSnapshot(snapshot)
 
 	// Fetch issue and dependencies
 	fmt.Printf("Fetching %s and its dependencies...\n", issueKey)
@@ -163,15 +302,217 @@ func runQuickstart(urlOrKey string) error {
 	}
 
 	fmt.Printf("\n✓ Fetched %d issue(s)\n\n", len(jiraExport.Issues))
+	saveSnapshot(snapshot, jiraExport)
+
+	return writeBeads(converterOptions(cfg), jiraExport)
+}
+
+// loadConfigOrPrompt loads and validates the configuration, prompting for it if none is found
+func loadConfigOrPrompt() (*config.Config, error) {
+	return loadConfigForURLOrPrompt("")
+}
+
+// loadConfigForURLOrPrompt loads and validates the configuration, selecting
+// the profile matching issueURL's host unless one is given with --profile or
+// JIRA_PROFILE, and prompts for it if none is found
+func loadConfigForURLOrPrompt(issueURL string) (*config.Config, error) {
+	cfg, err := config.LoadProfileForURL(profile, issueURL)
+	if errors.Is(err, config.ErrProfileNotFound) || errors.Is(err, config.ErrHostMismatch) {
+		return nil, err
+	}
+	if err != nil {
+		fmt.Println("⚠ No configuration found. Let's set it up!")
+		fmt.Println()
+		cfg, err = config.PromptForConfig(false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure: %w", err)
+		}
+		if cfg.Jira.AuthMethod == "oauth2" {
+			if err := authorizeOAuth2(cfg); err != nil {
+				return nil, err
+			}
+		}
+		if err := config.SaveProfile(profile, cfg.Jira); err != nil {
+			fmt.Printf("⚠ Warning: failed to save config: %v\n", err)
+		} else {
+			fmt.Println("✓ Configuration saved")
+			fmt.Println()
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w. Run 'jira-beads-sync configure' to set up", err)
+	}
+
+	return cfg, nil
+}
+
+// newJiraClient creates a Jira client for baseURL using the configured
+// credentials and connection settings
+func newJiraClient(cfg *config.Config, baseURL string) (*jira.Client, error) {
+	if cfg.Jira.AuthMethod == "oauth2" && cfg.Jira.OAuth2 != nil && cfg.Jira.OAuth2.CloudID != "" {
+		// OAuth 2.0 requests to Jira Cloud go through api.atlassian.com
+		baseURL = jira.OAuth2APIURL(cfg.Jira.OAuth2.CloudID)
+	}
+
+	apiToken := ""
+	if cfg.Jira.AuthMethod != "oauth2" {
+		var err error
+		if apiToken, err = cfg.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	client := jira.NewClient(baseURL, cfg.Jira.Username, apiToken, cfg.Jira.AuthMethod)
+	if cfg.Jira.AuthMethod == "oauth2" && cfg.Jira.OAuth2 != nil {
+		token := jira.OAuth2Token{
+			AccessToken:  cfg.Jira.OAuth2.AccessToken,
+			RefreshToken: cfg.Jira.OAuth2.RefreshToken,
+			Expiry:       cfg.Jira.OAuth2.Expiry,
+		}
+		client.SetOAuth2(oauth2Config(cfg.Jira.OAuth2), token, func(token *jira.OAuth2Token) {
+			if err := cfg.SaveOAuth2Token(token.AccessToken, token.RefreshToken, token.Expiry); err != nil {
+				fmt.Printf("⚠ Warning: failed to save refreshed OAuth token: %v\n", err)
+			}
+		})
+	}
+	if cfg.Jira.RankField != "" {
+		client.SetRankField(cfg.Jira.RankField)
+	}
+	client.SetFetchDevelopment(!cfg.Jira.SkipDevelopment)
+	if len(cfg.Jira.SearchFields) > 0 {
+		client.SetSearchFields(cfg.Jira.SearchFields)
+	}
+	deployment := cfg.Jira.Deployment
+	if deployment == "" {
+		deployment = jira.DeploymentAuto
+	}
+	client.SetDeployment(deployment)
+
+	err := client.SetTransport(jira.TransportConfig{
+		CAFile:             cfg.Jira.CAFile,
+		ClientCert:         cfg.Jira.ClientCert,
+		ClientKey:          cfg.Jira.ClientKey,
+		ProxyURL:           cfg.Jira.ProxyURL,
+		InsecureSkipVerify: cfg.Jira.InsecureSkipVerify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection settings: %w", err)
+	}
+
+	// A recording has to see every response, so it bypasses the cache
+	if recordFile != "" {
+		if err := setRecorder(client, recordFile); err != nil {
+			return nil, err
+		}
+	} else if !noCache && !cfg.Cache.Disabled {
+		if err := setCache(client, &cfg.Cache); err != nil {
+			fmt.Printf("⚠ Warning: continuing without the response cache: %v\n", err)
+		}
+	}
+
+	return client, nil
+}
+
+// setCache makes the client cache Jira responses as configured
+func setCache(client *jira.Client, cache *config.CacheConfig) error {
+	dir, err := cache.Directory()
+	if err != nil {
+		return err
+	}
+	// The configuration has been validated, so the TTL parses
+	ttl, _ := cache.TTLDuration()
+
+	return client.SetCache(jira.CacheConfig{
+		Dir:     dir,
+		TTL:     ttl,
+		MaxSize: int64(cache.MaxSizeMB) << 20,
+	})
+}
+
+// setRecorder makes the client record its Jira requests and responses to a
+// fixture file. Interactions are written as they happen, so the file is
+// complete when the process exits, even after an error.
+func setRecorder(client *jira.Client, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create fixture file: %w", err)
+	}
+	recorder = jira.NewRecorder(file)
+	client.SetRecorder(recorder)
+	fmt.Printf("Recording Jira requests to %s\n", filename)
+	return nil
+}
+
+// closeRecorder flushes and closes the fixture file, if requests were recorded
+func closeRecorder() {
+	if recorder == nil {
+		return
+	}
+	if err := recorder.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	recorder = nil
+}
+
+// oauth2Config returns the Jira client OAuth 2.0 settings for the configured app
+func oauth2Config(o *config.OAuth2Config) jira.OAuth2Config {
+	return jira.OAuth2Config{
+		ClientID:     o.ClientID,
+		ClientSecret: o.ClientSecret,
+		AuthURL:      o.AuthURL,
+		TokenURL:     o.TokenURL,
+		Scopes:       o.Scopes,
+		RedirectURL:  o.RedirectURL(),
+	}
+}
+
+// authorizeOAuth2 runs the OAuth 2.0 authorization flow in the browser and
+// stores the tokens, and for Atlassian Cloud the site's cloud ID, in cfg
+func authorizeOAuth2(cfg *config.Config) error {
+	oauth2 := cfg.Jira.OAuth2
+	appConfig := oauth2Config(oauth2)
+
+	fmt.Println()
+	fmt.Printf("Waiting for authorization on %s\n", appConfig.RedirectURL)
+	token, err := jira.AuthorizeOAuth2(appConfig, func(authURL string) error {
+		fmt.Println("Open this URL in your browser to authorize jira-beads-sync:")
+		fmt.Println()
+		fmt.Printf("  %s\n", authURL)
+		fmt.Println()
+		return nil
+	}, oauth2AuthorizeTimeout)
+	if err != nil {
+		return fmt.Errorf("OAuth authorization failed: %w", err)
+	}
+
+	oauth2.AccessToken = token.AccessToken
+	oauth2.RefreshToken = token.RefreshToken
+	oauth2.Expiry = token.Expiry
+
+	// Only Atlassian Cloud routes OAuth requests through a cloud ID; Jira
+	// Data Center accepts the token on its own base URL
+	if oauth2.AuthURL == "" || oauth2.AuthURL == jira.DefaultOAuth2AuthURL {
+		cloudID, err := jira.FindCloudID(appConfig, token, cfg.Jira.BaseURL)
+		if err != nil {
+			return err
+		}
+		oauth2.CloudID = cloudID
+	}
 
-	// Convert to beads format
+	fmt.Println("✓ Authorized")
+	return nil
+}
+
+// writeBeads converts a Jira export to beads format and renders it to the current directory
+func writeBeads(options converter.Options, jiraExport *jirapb.Export) error {
 	outputDir, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
 	fmt.Println("Converting to beads format...")
-	protoConverter := converter.NewProtoConverter()
+	protoConverter := converter.NewProtoConverterWithOptions(options)
 	beadsExport, err := protoConverter.Convert(jiraExport)
 	if err != nil {
 		return fmt.Errorf("failed to convert: %w", err)
@@ -179,6 +520,7 @@ func runQuickstart(urlOrKey string) error {
 
 	// Render to JSONL
 	jsonlRenderer := beads.NewJSONLRenderer(outputDir)
+	jsonlRenderer.SetLocation(options.Location)
 	if err := jsonlRenderer.RenderExport(beadsExport); err != nil {
 		return fmt.Errorf("failed to render: %w", err)
 	}
@@ -188,35 +530,228 @@ func runQuickstart(urlOrKey string) error {
 		fmt.Printf("  %d epic(s) written to %s/.beads/epics.jsonl\n", len(beadsExport.Epics), outputDir)
 	}
 	fmt.Printf("  %d issue(s) written to %s/.beads/issues.jsonl\n", len(beadsExport.Issues), outputDir)
+	if beadsExport.Sprint != nil {
+		fmt.Printf("  Sprint summary written to %s/.beads/sprint.json\n", outputDir)
+	}
 
 	return nil
 }
 
-func runConfigure() error {
-	fmt.Println("jira-beads-sync configuration")
-	fmt.Println("===========================")
-	fmt.Println()
+// snapshotDir returns the directory holding the raw Jira data of the last
+// fetch into outputDir
+func snapshotDir(outputDir string) string {
+	return filepath.Join(outputDir, ".beads", jira.SnapshotDir)
+}
 
-	cfg, err := config.PromptForConfig()
+// saveSnapshot saves the raw Jira data of a fetch to the current directory,
+// so reconvert can convert it again without access to Jira. Failing to save
+// it only warns, as the fetch itself succeeded.
+func saveSnapshot(snapshot *jira.Snapshot, jiraExport *jirapb.Export) {
+	outputDir, err := os.Getwd()
 	if err != nil {
+		fmt.Printf("⚠ Warning: failed to save Jira data for reconvert: %v\n", err)
+		return
+	}
+
+	if err := snapshot.Save(snapshotDir(outputDir), jiraExport); err != nil {
+		fmt.Printf("⚠ Warning: failed to save Jira data for reconvert: %v\n", err)
+	}
+}
+
+// runConfigure sets up the Jira credentials of the selected profile,
+// interactively or, when flags are given, from the flags and a token read
+// from stdin. Credentials are checked against Jira before saving.
+func runConfigure(args []string) error {
+	flags := flag.NewFlagSet("configure", flag.ContinueOnError)
+	baseURL := flags.String("base-url", "", "Jira base URL, e.g. https://acme.atlassian.net")
+	authMethod := flags.String("auth-method", "basic", "authentication method: basic, bearer or oauth2 (oauth2 is set up interactively)")
+	username := flags.String("username", "", "Jira username or email (required for basic auth)")
+	tokenStdin := flags.Bool("token-stdin", false, "read the API token from stdin")
+	encrypt := flags.Bool("encrypt", false, "store the API token in the passphrase-encrypted credential store")
+	noVerify := flags.Bool("no-verify", false, "save without checking the credentials against Jira")
+	if err := flags.Parse(args); err != nil {
 		return err
 	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("configure takes no arguments")
+	}
 
-	if err := cfg.Save(); err != nil {
+	var cfg *config.Config
+	if interactiveConfigure(flags) {
+		fmt.Println("jira-beads-sync configuration")
+		fmt.Println("===========================")
+		fmt.Println()
+
+		var err error
+		cfg, err = config.PromptForConfig(*encrypt)
+		if err != nil {
+			return err
+		}
+
+		if cfg.Jira.AuthMethod == "oauth2" {
+			if err := authorizeOAuth2(cfg); err != nil {
+				return err
+			}
+		}
+	} else {
+		if *baseURL == "" {
+			return fmt.Errorf("configure requires --base-url")
+		}
+		if *authMethod == "oauth2" {
+			return fmt.Errorf("OAuth 2.0 needs a browser; run 'jira-beads-sync configure' without flags")
+		}
+		if !*tokenStdin {
+			return fmt.Errorf("configure requires --token-stdin to read the API token")
+		}
+
+		token, err := readToken(stdin)
+		if err != nil {
+			return err
+		}
+
+		cfg = &config.Config{Jira: config.JiraConfig{
+			BaseURL:           strings.TrimSuffix(*baseURL, "/"),
+			AuthMethod:        *authMethod,
+			Username:          *username,
+			APIToken:          token,
+			APITokenEncrypted: *encrypt,
+		}}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+
+	if !*noVerify {
+		if err := verifyConfig(cfg); err != nil {
+			return err
+		}
+	}
+
+	// Keep the config file's other profiles and settings
+	if err := config.SaveProfile(profile, cfg.Jira); err != nil {
 		return fmt.Errorf("failed to save configuration: %w", err)
 	}
 
 	fmt.Println()
-	fmt.Println("✓ Configuration saved successfully")
+	if profile != "" {
+		fmt.Printf("✓ Configuration saved to profile %q\n", profile)
+	} else {
+		fmt.Println("✓ Configuration saved successfully")
+	}
 
 	return nil
 }
 
+// interactiveConfigure reports whether configure prompts for the settings:
+// it does unless settings are given as flags. --no-verify and --encrypt only
+// change how the settings are saved, so they work in either mode.
+func interactiveConfigure(flags *flag.FlagSet) bool {
+	interactive := true
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name != "no-verify" && f.Name != "encrypt" {
+			interactive = false
+		}
+	})
+	return interactive
+}
+
+// stdin is where configure --token-stdin reads the token; replaced in tests
+var stdin io.Reader = os.Stdin
+
+// readToken reads an API token from the first line of r. Spaces within the
+// token are kept.
+func readToken(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read token from stdin: %w", err)
+	}
+
+	token := strings.TrimSpace(line)
+	if token == "" {
+		return "", fmt.Errorf("no token on stdin")
+	}
+	return token, nil
+}
+
+// verifyConfig checks the credentials by fetching the current user, and
+// reports the deployment type and version of the Jira instance
+func verifyConfig(cfg *config.Config) error {
+	client, err := newJiraClient(cfg, cfg.Jira.BaseURL)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("Checking credentials...")
+	userInfo, err := client.GetCurrentUser()
+	if err != nil {
+		if diagnosis := jira.DiagnoseTLSError(err); diagnosis != "" {
+			fmt.Printf("  %s\n", diagnosis)
+		}
+		return fmt.Errorf("failed to authenticate, configuration not saved: %w", err)
+	}
+	fmt.Printf("✓ Authenticated as %s\n", userInfo.DisplayName)
+
+	info, err := client.GetServerInfo()
+	if err != nil {
+		// serverInfo may be restricted; the credentials are still good
+		fmt.Printf("⚠ Warning: could not detect Jira deployment type: %v\n", err)
+		return nil
+	}
+
+	apiVersion := "3"
+	if info.Deployment() == jira.DeploymentServer {
+		apiVersion = "2"
+	}
+	fmt.Printf("  Deployment:    %s\n", info.DeploymentType)
+	fmt.Printf("  Version:       %s\n", info.Version)
+	fmt.Printf("  REST API:      v%s\n", apiVersion)
+
+	return nil
+}
+
+// runMigrateSecret moves the inline API token out of the config file, into a
+// token file or behind a credential helper command
+func runMigrateSecret(args []string) error {
+	flags := flag.NewFlagSet("migrate-secret", flag.ContinueOnError)
+	tokenFile := flags.String("file", "", "file to move the API token to (default: next to the config file)")
+	tokenCommand := flags.String("command", "", "credential helper command that prints the API token, e.g. 'pass show jira'")
+	encrypt := flags.Bool("encrypt", false, "move the API token to the passphrase-encrypted credential store")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("migrate-secret takes no arguments")
+	}
+	targets := 0
+	for _, set := range []bool{*tokenFile != "", *tokenCommand != "", *encrypt} {
+		if set {
+			targets++
+		}
+	}
+	if targets > 1 {
+		return fmt.Errorf("migrate-secret accepts only one of --file, --command and --encrypt")
+	}
+
+	source, err := config.MigrateSecret(profile, config.SecretTarget{
+		Command: *tokenCommand,
+		Encrypt: *encrypt,
+		File:    *tokenFile,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate API token: %w", err)
+	}
+
+	fmt.Println("✓ Removed the API token from the config file")
+	fmt.Printf("  Token is now read from %s\n", source)
+	return nil
+}
+
 func runWhoami() error {
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.LoadProfile(profile)
 	if err != nil {
-		return fmt.Errorf("no configuration found. Run 'jira-beads-sync configure' to set up")
+		return fmt.Errorf("failed to load configuration: %w. Run 'jira-beads-sync configure' to set up", err)
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -228,12 +763,22 @@ func runWhoami() error {
 	fmt.Println()
 
 	// Create Jira client
-	client := jira.NewClient(cfg.Jira.BaseURL, cfg.Jira.Username, cfg.Jira.APIToken, cfg.Jira.AuthMethod)
+	client, err := newJiraClient(cfg, cfg.Jira.BaseURL)
+	if err != nil {
+		return err
+	}
 
 	// Test authentication by fetching current user
 	fmt.Println("Testing Jira connection...")
 	userInfo, err := client.GetCurrentUser()
 	if err != nil {
+		if diagnosis := jira.DiagnoseTLSError(err); diagnosis != "" {
+			fmt.Println()
+			fmt.Println("✗ TLS handshake failed")
+			fmt.Printf("  %s\n", diagnosis)
+			printConnectionSettings(cfg)
+			fmt.Println()
+		}
 		return fmt.Errorf("failed to authenticate: %w", err)
 	}
 
@@ -247,28 +792,183 @@ func runWhoami() error {
 	fmt.Printf("  Active:        %t\n", userInfo.Active)
 	fmt.Println()
 	fmt.Println("Jira Instance:")
+	if cfg.Profile != "" {
+		fmt.Printf("  Profile:       %s\n", cfg.Profile)
+	}
 	fmt.Printf("  Base URL:      %s\n", cfg.Jira.BaseURL)
 	fmt.Printf("  Username:      %s\n", cfg.Jira.Username)
+	printConnectionSettings(cfg)
 
 	return nil
 }
 
-func runConvert(jiraFile string) error {
+// printConnectionSettings prints the configured TLS and proxy settings, if any
+func printConnectionSettings(cfg *config.Config) {
+	proxy := cfg.Jira.ProxyURL
+	if proxy == "" {
+		proxy = os.Getenv("HTTPS_PROXY")
+		if proxy == "" {
+			proxy = os.Getenv("https_proxy")
+		}
+	}
+
+	if cfg.Jira.CAFile != "" {
+		fmt.Printf("  CA File:       %s\n", cfg.Jira.CAFile)
+	}
+	if cfg.Jira.ClientCert != "" {
+		fmt.Printf("  Client Cert:   %s\n", cfg.Jira.ClientCert)
+	}
+	if proxy != "" {
+		// Don't print proxy credentials
+		if proxyURL, err := url.Parse(proxy); err == nil {
+			proxy = proxyURL.Redacted()
+		}
+		fmt.Printf("  Proxy:         %s\n", proxy)
+	}
+	if cfg.Jira.InsecureSkipVerify {
+		fmt.Println("  ⚠ TLS certificate verification is disabled (insecure_skip_verify)")
+	}
+}
+
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	lenient := flags.Bool("lenient", false, "skip or repair bad issues instead of failing")
+	report := flags.String("report", "", "write the problems found by --lenient to this JSON file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("convert requires a file argument")
+	}
+	if *report != "" && !*lenient {
+		return fmt.Errorf("--report requires --lenient")
+	}
+
+	jiraFiles, err := converter.ExpandInputs(flags.Args())
+	if err != nil {
+		return err
+	}
+
 	// Get current directory as output directory
 	outputDir, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	pipeline := converter.NewPipeline(outputDir)
+	// Configuration is optional for offline conversion; only the field mapping is used
+	cfg, err := config.LoadMapping(profile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := cfg.Mapping.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	pipeline := converter.NewPipelineWithOptions(outputDir, converterOptions(cfg))
+	pipeline.SetLenient(*lenient)
 
-	fmt.Printf("Converting %s to beads format...\n", jiraFile)
-	if err := pipeline.ConvertFile(jiraFile); err != nil {
+	fmt.Printf("Converting %s to beads format...\n", strings.Join(jiraFiles, ", "))
+	if err := pipeline.ConvertFiles(jiraFiles); err != nil {
 		return err
 	}
 
 	fmt.Println("✓ Conversion complete!")
 	fmt.Printf("  Issues and epics written to %s/.beads/\n", outputDir)
+
+	diagnostics := pipeline.Diagnostics()
+	if *report != "" {
+		if err := writeDiagnostics(*report, diagnostics); err != nil {
+			return err
+		}
+	}
+	if len(diagnostics) == 0 {
+		return nil
+	}
+
+	fmt.Printf("\n⚠ %d problem(s) found in the export:\n", len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		fmt.Printf("  %s\n", diagnostic)
+	}
+	return errConversionWarnings
+}
+
+// runReconvert rebuilds .beads from the Jira data saved by the last fetch,
+// applying the current mapping without access to Jira
+func runReconvert(args []string) error {
+	flags := flag.NewFlagSet("reconvert", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("reconvert takes no arguments")
+	}
+
+	outputDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	dir := snapshotDir(outputDir)
+	snapshot, err := jira.LoadSnapshot(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("no saved Jira data in %s; fetch from Jira first", dir)
+	}
+	if err != nil {
+		return err
+	}
+	sprint, err := snapshot.Sprint()
+	if err != nil {
+		return err
+	}
+
+	// Configuration is optional, as for convert; only the field mapping is used
+	cfg, err := config.LoadMapping(profile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := cfg.Mapping.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	options := converterOptions(cfg)
+	// Keep the board's rank order, as fetch-board does
+	options.SortByRank = snapshot.Command == "fetch-board"
+
+	pipeline := converter.NewPipelineWithOptions(outputDir, options)
+	pipeline.SetRankField(snapshot.RankField)
+	pipeline.SetSprint(sprint)
+	pipeline.SetIssueHook(func(issue *jirapb.Issue) error {
+		return snapshot.Restore(issue, sprint)
+	})
+
+	fmt.Printf("Reconverting %s of %s, fetched %s...\n",
+		snapshot.Command, snapshot.BaseURL, snapshot.FetchedAt.Local().Format(time.RFC1123))
+	if err := pipeline.ConvertFile(snapshot.IssuesFile()); err != nil {
+		return err
+	}
+
+	fmt.Println("✓ Conversion complete!")
+	fmt.Printf("  Issues and epics written to %s/.beads/\n", outputDir)
+	if sprint != nil {
+		fmt.Printf("  Sprint summary written to %s/.beads/sprint.json\n", outputDir)
+	}
+
+	return nil
+}
+
+// writeDiagnostics writes the problems found by a lenient conversion to a
+// JSON report
+func writeDiagnostics(path string, diagnostics []jira.Diagnostic) error {
+	if diagnostics == nil {
+		diagnostics = []jira.Diagnostic{}
+	}
+	data, err := json.MarshalIndent(diagnostics, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode diagnostics: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write diagnostics report: %w", err)
+	}
 	return nil
 }
 
@@ -277,29 +977,18 @@ func runFetchByLabel(label string) error {
 	fmt.Println("==============================")
 	fmt.Println()
 
-	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := loadConfigOrPrompt()
 	if err != nil {
-		fmt.Println("⚠ No configuration found. Let's set it up!")
-		fmt.Println()
-		cfg, err = config.PromptForConfig()
-		if err != nil {
-			return fmt.Errorf("failed to configure: %w", err)
-		}
-		if err := cfg.Save(); err != nil {
-			fmt.Printf("⚠ Warning: failed to save config: %v\n", err)
-		} else {
-			fmt.Println("✓ Configuration saved")
-			fmt.Println()
-		}
-	}
-
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w. Run 'jira-beads-sync configure' to set up", err)
+		return err
 	}
 
 	// Create Jira client
-	client := jira.NewClient(cfg.Jira.BaseURL, cfg.Jira.Username, cfg.Jira.APIToken, cfg.Jira.AuthMethod)
+	client, err := newJiraClient(cfg, cfg.Jira.BaseURL)
+	if err != nil {
+		return err
+	}
+	snapshot := jira.NewSnapshot("fetch-by-label")
+	client.SetSnapshot(snapshot)
 
 	// Fetch issues by label
 	jiraExport, err := client.FetchIssuesByLabel(label)
@@ -308,101 +997,154 @@ func runFetchByLabel(label string) error {
 	}
 
 	fmt.Printf("\n✓ Fetched %d issue(s) total (including dependencies)\n\n", len(jiraExport.Issues))
+	saveSnapshot(snapshot, jiraExport)
 
-	// Convert to beads format
-	outputDir, err := os.Getwd()
+	return writeBeads(converterOptions(cfg), jiraExport)
+}
+
+func runFetchByJQL(jqlQuery string) error {
+	fmt.Println("jira-beads-sync fetch-jql")
+	fmt.Println("=========================")
+	fmt.Println()
+
+	cfg, err := loadConfigOrPrompt()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 
-	fmt.Println("Converting to beads format...")
-	protoConverter := converter.NewProtoConverter()
-	beadsExport, err := protoConverter.Convert(jiraExport)
+	// Create Jira client
+	client, err := newJiraClient(cfg, cfg.Jira.BaseURL)
 	if err != nil {
-		return fmt.Errorf("failed to convert: %w", err)
+		return err
 	}
+	snapshot := jira.NewSnapshot("fetch-jql")
+	client.SetSnapshot(snapshot)
 
-	// Render to JSONL
-	jsonlRenderer := beads.NewJSONLRenderer(outputDir)
-	if err := jsonlRenderer.RenderExport(beadsExport); err != nil {
-		return fmt.Errorf("failed to render: %w", err)
+	// Fetch issues by JQL
+	jiraExport, err := client.FetchIssuesByJQL(jqlQuery)
+	if err != nil {
+		return fmt.Errorf("failed to fetch issues by JQL: %w", err)
 	}
 
-	fmt.Println("\n✓ Conversion complete!")
-	if len(beadsExport.Epics) > 0 {
-		fmt.Printf("  %d epic(s) written to %s/.beads/epics.jsonl\n", len(beadsExport.Epics), outputDir)
-	}
-	fmt.Printf("  %d issue(s) written to %s/.beads/issues.jsonl\n", len(beadsExport.Issues), outputDir)
+	fmt.Printf("\n✓ Fetched %d issue(s) total (including dependencies)\n\n", len(jiraExport.Issues))
+	saveSnapshot(snapshot, jiraExport)
 
-	return nil
+	return writeBeads(converterOptions(cfg), jiraExport)
 }
 
-func runFetchByJQL(jqlQuery string) error {
-	fmt.Println("jira-beads-sync fetch-jql")
-	fmt.Println("=========================")
-	fmt.Println()
+func runFetchSprint(args []string) error {
+	flags := flag.NewFlagSet("fetch-sprint", flag.ContinueOnError)
+	boardID := flags.Int64("board", 0, "fetch the active sprint of this board")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
 
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		fmt.Println("⚠ No configuration found. Let's set it up!")
-		fmt.Println()
-		cfg, err = config.PromptForConfig()
+	var sprintID int64
+	switch {
+	case *boardID != 0 && flags.NArg() == 0:
+	case *boardID == 0 && flags.NArg() == 1:
+		id, err := strconv.ParseInt(flags.Arg(0), 10, 64)
 		if err != nil {
-			return fmt.Errorf("failed to configure: %w", err)
-		}
-		if err := cfg.Save(); err != nil {
-			fmt.Printf("⚠ Warning: failed to save config: %v\n", err)
-		} else {
-			fmt.Println("✓ Configuration saved")
-			fmt.Println()
+			return fmt.Errorf("invalid sprint ID: %s", flags.Arg(0))
 		}
+		sprintID = id
+	default:
+		return fmt.Errorf("fetch-sprint requires either a sprint ID or --board <board-id>")
 	}
 
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w. Run 'jira-beads-sync configure' to set up", err)
+	fmt.Println("jira-beads-sync fetch-sprint")
+	fmt.Println("============================")
+	fmt.Println()
+
+	cfg, err := loadConfigOrPrompt()
+	if err != nil {
+		return err
 	}
 
 	// Create Jira client
-	client := jira.NewClient(cfg.Jira.BaseURL, cfg.Jira.Username, cfg.Jira.APIToken, cfg.Jira.AuthMethod)
+	client, err := newJiraClient(cfg, cfg.Jira.BaseURL)
+	if err != nil {
+		return err
+	}
+	snapshot := jira.NewSnapshot("fetch-sprint")
+	client.SetSnapshot(snapshot)
 
-	// Fetch issues by JQL
-	jiraExport, err := client.FetchIssuesByJQL(jqlQuery)
+	// Resolve the active sprint of the board if no sprint was given
+	if sprintID == 0 {
+		fmt.Printf("Resolving active sprint for board %d...\n", *boardID)
+		sprint, err := client.GetActiveSprint(*boardID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve active sprint: %w", err)
+		}
+		sprintID = sprint.Id
+	}
+
+	// Fetch sprint issues
+	jiraExport, err := client.FetchSprint(sprintID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch issues by JQL: %w", err)
+		return fmt.Errorf("failed to fetch sprint: %w", err)
 	}
 
 	fmt.Printf("\n✓ Fetched %d issue(s) total (including dependencies)\n\n", len(jiraExport.Issues))
+	saveSnapshot(snapshot, jiraExport)
 
-	// Convert to beads format
-	outputDir, err := os.Getwd()
+	return writeBeads(converterOptions(cfg), jiraExport)
+}
+
+func runFetchBoard(boardArg string) error {
+	boardID, err := strconv.ParseInt(boardArg, 10, 64)
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return fmt.Errorf("invalid board ID: %s", boardArg)
 	}
 
-	fmt.Println("Converting to beads format...")
-	protoConverter := converter.NewProtoConverter()
-	beadsExport, err := protoConverter.Convert(jiraExport)
+	fmt.Println("jira-beads-sync fetch-board")
+	fmt.Println("===========================")
+	fmt.Println()
+
+	cfg, err := loadConfigOrPrompt()
 	if err != nil {
-		return fmt.Errorf("failed to convert: %w", err)
+		return err
 	}
 
-	// Render to JSONL
-	jsonlRenderer := beads.NewJSONLRenderer(outputDir)
-	if err := jsonlRenderer.RenderExport(beadsExport); err != nil {
-		return fmt.Errorf("failed to render: %w", err)
+	// Create Jira client
+	client, err := newJiraClient(cfg, cfg.Jira.BaseURL)
+	if err != nil {
+		return err
 	}
+	snapshot := jira.NewSnapshot("fetch-board")
+	client.SetSnapshot(snapshot)
 
-	fmt.Println("\n✓ Conversion complete!")
-	if len(beadsExport.Epics) > 0 {
-		fmt.Printf("  %d epic(s) written to %s/.beads/epics.jsonl\n", len(beadsExport.Epics), outputDir)
+	// Fetch backlog in rank order
+	jiraExport, err := client.FetchBoardBacklog(boardID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch board backlog: %w", err)
 	}
-	fmt.Printf("  %d issue(s) written to %s/.beads/issues.jsonl\n", len(beadsExport.Issues), outputDir)
 
-	return nil
+	fmt.Printf("\n✓ Fetched %d issue(s) total (including dependencies)\n\n", len(jiraExport.Issues))
+	saveSnapshot(snapshot, jiraExport)
+
+	// Keep the board's rank order in issues.jsonl
+	options := converterOptions(cfg)
+	options.SortByRank = true
+
+	return writeBeads(options, jiraExport)
 }
 
-func runAnnotate(issueID, repository string) error {
+func runAnnotate(args []string) error {
+	flags := flag.NewFlagSet("annotate", flag.ContinueOnError)
+	remove := flags.Bool("remove", false, "remove the repository instead of adding it")
+	migrate := flags.Bool("migrate", false, "convert comma-separated repository metadata to JSON arrays")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	switch {
+	case *migrate && flags.NArg() == 0 && !*remove:
+	case !*migrate && flags.NArg() == 2:
+	default:
+		return fmt.Errorf("annotate requires <issue-id> and <repository> arguments, or --migrate")
+	}
+
 	fmt.Println("jira-beads-sync annotate")
 	fmt.Println("========================")
 	fmt.Println()
@@ -414,17 +1156,51 @@ func runAnnotate(issueID, repository string) error {
 
 	jsonlRenderer := beads.NewJSONLRenderer(outputDir)
 
-	// Add repository annotation
+	if *migrate {
+		if err := jsonlRenderer.MigrateMetadata(); err != nil {
+			return fmt.Errorf("failed to migrate metadata: %w", err)
+		}
+		fmt.Printf("✓ Migrated repository metadata in %s/.beads\n", outputDir)
+		return nil
+	}
+
+	issueID, repository := flags.Arg(0), flags.Arg(1)
+	if *remove {
+		if err := jsonlRenderer.RemoveRepositoryAnnotation(issueID, repository); err != nil {
+			return fmt.Errorf("failed to annotate issue: %w", err)
+		}
+		fmt.Printf("✓ Removed repository '%s' from %s\n", repository, issueID)
+		return nil
+	}
+
 	if err := jsonlRenderer.AddRepositoryAnnotation(issueID, repository); err != nil {
 		return fmt.Errorf("failed to annotate issue: %w", err)
 	}
 
-	fmt.Printf("✓ Added repository '%s' to issue %s\n", repository, issueID)
-	fmt.Printf("  Updated: %s/.beads/issues.jsonl\n", outputDir)
+	fmt.Printf("✓ Added repository '%s' to %s\n", repository, issueID)
 
 	return nil
 }
 
+// converterOptions builds converter options from the mapping configuration
+func converterOptions(cfg *config.Config) converter.Options {
+	options := converter.DefaultOptions()
+	if cfg.Mapping.FixVersions != "" {
+		options.FixVersions = converter.FieldTarget(cfg.Mapping.FixVersions)
+	}
+	if cfg.Mapping.AffectsVersions != "" {
+		options.AffectsVersions = converter.FieldTarget(cfg.Mapping.AffectsVersions)
+	}
+	if cfg.Mapping.Components != "" {
+		options.Components = converter.FieldTarget(cfg.Mapping.Components)
+	}
+	// The mapping has been validated, so an unknown zone can't occur here
+	if location, err := cfg.Mapping.Location(); err == nil {
+		options.Location = location
+	}
+	return options
+}
+
 func printUsage() {
 	fmt.Println("jira-beads-sync - Convert Jira task trees to beads issues")
 	fmt.Println()
@@ -432,22 +1208,47 @@ func printUsage() {
 	fmt.Println("  jira-beads-sync quickstart <jira-url>         Fetch issue from Jira and convert to beads")
 	fmt.Println("  jira-beads-sync fetch-by-label <label>        Fetch all issues with label from Jira")
 	fmt.Println("  jira-beads-sync fetch-jql <jql-query>         Fetch issues matching JQL query from Jira")
-	fmt.Println("  jira-beads-sync annotate <issue-id> <repo>    Annotate issue with repository info")
-	fmt.Println("  jira-beads-sync convert <jira-export-file>    Convert Jira export to beads format")
+	fmt.Println("  jira-beads-sync fetch-sprint <sprint-id>      Fetch all issues in a sprint from Jira")
+	fmt.Println("  jira-beads-sync fetch-sprint --board <id>     Fetch the active sprint of a board from Jira")
+	fmt.Println("  jira-beads-sync fetch-board <board-id>        Fetch a board's backlog in rank order from Jira")
+	fmt.Println("  jira-beads-sync annotate <issue-id> <repo>    Annotate issue or epic with repository info")
+	fmt.Println("  jira-beads-sync annotate --remove <id> <repo> Remove a repository annotation")
+	fmt.Println("  jira-beads-sync annotate --migrate            Convert comma-separated repositories to arrays")
+	fmt.Println("  jira-beads-sync convert <file>...             Convert Jira JSON, CSV or XML exports to beads format")
+	fmt.Println("  jira-beads-sync convert --lenient <file>...   Skip or repair bad issues instead of failing (exit status 2 if any)")
+	fmt.Println("  jira-beads-sync reconvert                     Convert the Jira data of the last fetch again, offline")
 	fmt.Println("  jira-beads-sync configure                     Configure Jira credentials")
+	fmt.Println("  jira-beads-sync configure --base-url <url> --username <user> --token-stdin")
+	fmt.Println("                                                Configure Jira credentials non-interactively")
+	fmt.Println("  jira-beads-sync config migrate-secret         Move the API token out of the config file")
 	fmt.Println("  jira-beads-sync whoami                        Test Jira authentication and show user info")
 	fmt.Println("  jira-beads-sync version                       Show version information")
 	fmt.Println("  jira-beads-sync help                          Show this help message")
 	fmt.Println()
+	fmt.Println("Global options:")
+	fmt.Println("  --profile <name>                              Use a named config profile (or set JIRA_PROFILE)")
+	fmt.Println("  --no-cache                                    Fetch everything from Jira, bypassing the response cache")
+	fmt.Println("  --record <file>                               Record redacted Jira requests and responses to a fixture file")
+	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  jira-beads-sync quickstart https://jira.example.com/browse/PROJ-123")
 	fmt.Println("  jira-beads-sync quickstart PROJ-123")
 	fmt.Println("  jira-beads-sync fetch-by-label sprint-23")
 	fmt.Println("  jira-beads-sync fetch-jql 'project = MYPROJ AND assignee = currentUser() AND status IN (\"READY TO START\", \"In Progress\")'")
 	fmt.Println("  jira-beads-sync fetch-jql 'project = MYPROJ AND sprint = 42'")
+	fmt.Println("  jira-beads-sync fetch-sprint 42")
+	fmt.Println("  jira-beads-sync fetch-sprint --board 7")
+	fmt.Println("  jira-beads-sync fetch-board 7")
 	fmt.Println("  jira-beads-sync annotate proj-123 https://github.com/org/repo")
+	fmt.Println("  jira-beads-sync annotate --remove proj-123 https://github.com/org/repo")
 	fmt.Println("  jira-beads-sync convert jira-export.json")
+	fmt.Println("  jira-beads-sync convert jira-export.csv")
+	fmt.Println("  jira-beads-sync convert jira-export.xml")
+	fmt.Println("  jira-beads-sync convert 'export-*.json.gz'")
+	fmt.Println("  curl -s ... | jira-beads-sync convert -")
+	fmt.Println("  jira-beads-sync convert --lenient --report problems.json jira-export.json")
 	fmt.Println("  jira-beads-sync configure")
+	fmt.Println("  jira-beads-sync --profile onprem configure")
 }
 
 // isURL checks if a string is a URL (starts with http:// or https://)
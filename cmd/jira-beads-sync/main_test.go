@@ -1,7 +1,15 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -393,3 +401,458 @@ func TestRunQuickstartWithMockConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestRunFetchByLabelRequestCount(t *testing.T) {
+	// 200 labelled issues without dependencies
+	issues := make([]map[string]interface{}, 0, 200)
+	for i := 1; i <= 200; i++ {
+		issues = append(issues, map[string]interface{}{
+			"id":  fmt.Sprintf("%d", 10000+i),
+			"key": fmt.Sprintf("PROJ-%d", i),
+			"fields": map[string]interface{}{
+				"summary":   "Labelled",
+				"issuetype": map[string]interface{}{"name": "Task"},
+				"status":    map[string]interface{}{"name": "Open", "statusCategory": map[string]interface{}{"key": "new"}},
+				"created":   "2024-01-01T10:00:00.000+0000",
+				"updated":   "2024-01-01T10:00:00.000+0000",
+			},
+		})
+	}
+
+	tests := []struct {
+		name        string
+		config      string
+		remoteLinks int // requests for remote links
+		devStatus   int // requests to the dev-status API
+	}{
+		// Remote links are fetched per issue; the dev-status API answers 404
+		// without a development integration, and isn't asked again
+		{name: "default", remoteLinks: 200, devStatus: 1},
+		{name: "skip_development", config: "  skip_development: true\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", tmpDir)
+			t.Setenv("XDG_CACHE_HOME", tmpDir)
+			t.Chdir(tmpDir)
+
+			requests := make(map[string]int)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path := r.URL.Path
+				switch {
+				case strings.HasSuffix(path, "/remotelink"):
+					path = "remotelink"
+				case strings.HasPrefix(path, "/rest/dev-status/"):
+					path = "dev-status"
+				}
+				requests[path]++
+
+				var response interface{}
+				switch path {
+				case "/rest/api/2/serverInfo":
+					response = map[string]interface{}{"deploymentType": "Cloud", "version": "1001.0.0"}
+				case "/rest/api/2/field":
+					response = []interface{}{}
+				case "/rest/api/3/search/jql":
+					start := 0
+					if token := r.URL.Query().Get("nextPageToken"); token != "" {
+						start, _ = strconv.Atoi(token)
+					}
+					end := min(start+100, len(issues))
+					page := map[string]interface{}{"issues": issues[start:end], "isLast": end == len(issues)}
+					if end < len(issues) {
+						page["nextPageToken"] = strconv.Itoa(end)
+					}
+					response = page
+				case "remotelink":
+					response = []interface{}{}
+				case "dev-status":
+					w.WriteHeader(http.StatusNotFound)
+					return
+				default:
+					t.Errorf("Unexpected request: %s", r.URL)
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					t.Errorf("Failed to encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			configContent := "jira:\n  base_url: " + server.URL + "\n  username: test@example.com\n  api_token: test-token\n" + tt.config
+			if err := os.MkdirAll(filepath.Join(tmpDir, "jira-beads-sync"), 0755); err != nil {
+				t.Fatalf("Failed to create config dir: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(tmpDir, "jira-beads-sync", "config.yml"), []byte(configContent), 0600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			if err := runFetchByLabel("big-release"); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			// The issues themselves come from the search pages, without a
+			// request per issue
+			if requests["/rest/api/3/search/jql"] != 2 {
+				t.Errorf("Expected 2 search pages, got %v", requests)
+			}
+			if requests["remotelink"] != tt.remoteLinks || requests["dev-status"] != tt.devStatus {
+				t.Errorf("Expected %d remote link and %d dev-status requests, got %v", tt.remoteLinks, tt.devStatus, requests)
+			}
+		})
+	}
+}
+
+func TestRunFetchSprintArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no arguments", args: []string{}},
+		{name: "invalid sprint ID", args: []string{"sprint-42"}},
+		{name: "sprint ID and board", args: []string{"--board", "7", "42"}},
+		{name: "too many arguments", args: []string{"42", "43"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runFetchSprint(tt.args)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if strings.Contains(err.Error(), "failed to configure") {
+				t.Errorf("Expected argument error before loading config, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunAnnotateArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no arguments", args: []string{}},
+		{name: "missing repository", args: []string{"proj-123"}},
+		{name: "remove without repository", args: []string{"--remove", "proj-123"}},
+		{name: "migrate with arguments", args: []string{"--migrate", "proj-123", "https://github.com/org/repo"}},
+		{name: "migrate and remove", args: []string{"--migrate", "--remove"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAnnotate(tt.args)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), "annotate requires") {
+				t.Errorf("Expected argument error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunConvertArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		errorMsg string
+	}{
+		{name: "no files", args: []string{"--lenient"}, errorMsg: "convert requires a file argument"},
+		{name: "report without lenient", args: []string{"--report", "problems.json", "export.json"}, errorMsg: "--report requires --lenient"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runConvert(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestExtractProfileFlag(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		expectedProfile string
+		expectedArgs    []string
+		expectError     bool
+	}{
+		{
+			name:         "no profile",
+			args:         []string{"jira-beads-sync", "whoami"},
+			expectedArgs: []string{"jira-beads-sync", "whoami"},
+		},
+		{
+			name:            "profile before command",
+			args:            []string{"jira-beads-sync", "--profile", "onprem", "quickstart", "OPS-7"},
+			expectedProfile: "onprem",
+			expectedArgs:    []string{"jira-beads-sync", "quickstart", "OPS-7"},
+		},
+		{
+			name:            "profile after command",
+			args:            []string{"jira-beads-sync", "fetch-sprint", "--board", "12", "--profile=cloud"},
+			expectedProfile: "cloud",
+			expectedArgs:    []string{"jira-beads-sync", "fetch-sprint", "--board", "12"},
+		},
+		{
+			name:        "missing profile name",
+			args:        []string{"jira-beads-sync", "whoami", "--profile"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, args, err := extractProfileFlag(tt.args)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if selected != tt.expectedProfile {
+				t.Errorf("Expected profile '%s', got '%s'", tt.expectedProfile, selected)
+			}
+			if strings.Join(args, " ") != strings.Join(tt.expectedArgs, " ") {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestExtractRecordFlag(t *testing.T) {
+	file, args, err := extractRecordFlag([]string{"jira-beads-sync", "--record=bug.jsonl", "quickstart", "PROJ-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if file != "bug.jsonl" || strings.Join(args, " ") != "jira-beads-sync quickstart PROJ-1" {
+		t.Errorf("Expected bug.jsonl and the command, got %q %v", file, args)
+	}
+
+	if _, _, err := extractRecordFlag([]string{"jira-beads-sync", "quickstart", "--record"}); err == nil || err.Error() != "--record requires a fixture file" {
+		t.Errorf("Expected a missing file error, got %v", err)
+	}
+}
+
+func TestExtractNoCacheFlag(t *testing.T) {
+	found, args := extractNoCacheFlag([]string{"jira-beads-sync", "quickstart", "--no-cache", "OPS-7"})
+	if !found {
+		t.Error("Expected --no-cache to be found")
+	}
+	if strings.Join(args, " ") != "jira-beads-sync quickstart OPS-7" {
+		t.Errorf("Expected --no-cache removed, got %v", args)
+	}
+
+	found, args = extractNoCacheFlag([]string{"jira-beads-sync", "whoami"})
+	if found || len(args) != 2 {
+		t.Errorf("Expected no --no-cache, got %v %v", found, args)
+	}
+}
+
+func TestRunMigrateSecretArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		errorMsg string
+	}{
+		{name: "file and command", args: []string{"--file", "token", "--command", "pass show jira"}, errorMsg: "only one of"},
+		{name: "command and encrypt", args: []string{"--command", "pass show jira", "--encrypt"}, errorMsg: "only one of"},
+		{name: "unexpected argument", args: []string{"token"}, errorMsg: "takes no arguments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runMigrateSecret(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+// newConfigureServer starts a fake Jira accepting only the given bearer token
+func newConfigureServer(t *testing.T, token string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var response interface{}
+		switch r.URL.Path {
+		case "/rest/api/2/myself":
+			response = map[string]interface{}{"accountId": "abc", "displayName": "CI Bot", "active": true}
+		case "/rest/api/2/serverInfo":
+			response = map[string]interface{}{"deploymentType": "DataCenter", "version": "9.12.0"}
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+}
+
+func TestRunConfigureNonInteractive(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("XDG_CACHE_HOME", tmpDir)
+
+	server := newConfigureServer(t, "token with spaces")
+	defer server.Close()
+
+	defer func(r io.Reader) { stdin = r }(stdin)
+	stdin = strings.NewReader("token with spaces\n")
+
+	args := []string{"--base-url", server.URL + "/", "--auth-method", "bearer", "--token-stdin"}
+	if err := runConfigure(args); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "jira-beads-sync", "config.yml"))
+	if err != nil {
+		t.Fatalf("Failed to read saved config: %v", err)
+	}
+	for _, expected := range []string{"base_url: " + server.URL + "\n", "auth_method: bearer", "api_token: token with spaces"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected saved config to contain %q, got:\n%s", expected, data)
+		}
+	}
+}
+
+func TestRunConfigureRejectsBadCredentials(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("XDG_CACHE_HOME", tmpDir)
+
+	server := newConfigureServer(t, "right-token")
+	defer server.Close()
+
+	defer func(r io.Reader) { stdin = r }(stdin)
+	stdin = strings.NewReader("wrong-token\n")
+
+	args := []string{"--base-url", server.URL, "--username", "ci@example.com", "--token-stdin"}
+	err := runConfigure(args)
+	if err == nil || !strings.Contains(err.Error(), "configuration not saved") {
+		t.Errorf("Expected authentication error, got: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "jira-beads-sync", "config.yml")); !os.IsNotExist(err) {
+		t.Error("Expected no config file to be saved")
+	}
+
+	// --no-verify saves without checking
+	stdin = strings.NewReader("wrong-token\n")
+	if err := runConfigure(append(args, "--no-verify")); err != nil {
+		t.Errorf("Expected no error with --no-verify, got: %v", err)
+	}
+}
+
+func TestRunConfigureArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		errorMsg string
+	}{
+		{name: "missing base URL", args: []string{"--token-stdin"}, errorMsg: "requires --base-url"},
+		{name: "missing token", args: []string{"--base-url", "https://jira.example.com"}, errorMsg: "requires --token-stdin"},
+		{name: "oauth2", args: []string{"--base-url", "https://jira.example.com", "--auth-method", "oauth2"}, errorMsg: "needs a browser"},
+		{name: "missing username", args: []string{"--base-url", "https://jira.example.com", "--token-stdin"}, errorMsg: "username is required"},
+	}
+
+	defer func(r io.Reader) { stdin = r }(stdin)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdin = strings.NewReader("token\n")
+			err := runConfigure(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestInteractiveConfigure(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "no flags", args: nil, want: true},
+		{name: "no-verify", args: []string{"--no-verify"}, want: true},
+		{name: "encrypt", args: []string{"--encrypt"}, want: true},
+		{name: "encrypt and no-verify", args: []string{"--encrypt", "--no-verify"}, want: true},
+		{name: "base URL", args: []string{"--base-url", "https://jira.example.com", "--encrypt"}, want: false},
+		{name: "auth method", args: []string{"--auth-method", "bearer"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("configure", flag.ContinueOnError)
+			flags.String("base-url", "", "")
+			flags.String("auth-method", "basic", "")
+			flags.Bool("encrypt", false, "")
+			flags.Bool("no-verify", false, "")
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if got := interactiveConfigure(flags); got != tt.want {
+				t.Errorf("interactiveConfigure(%v) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunReconvert(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("HOME", home)
+	t.Chdir(t.TempDir())
+
+	if err := runReconvert(nil); err == nil || !strings.Contains(err.Error(), "no saved Jira data") {
+		t.Fatalf("Expected an error without saved Jira data, got: %v", err)
+	}
+
+	dir := filepath.Join(".beads", ".jira-cache")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"snapshot.json": `{"command": "fetch-jql", "baseUrl": "https://jira.example.com", "fetchedAt": "2024-01-02T10:00:00Z"}`,
+		"issues.json":   `{"issues": [{"id": "10001", "key": "PROJ-1", "fields": {"summary": "Cached", "issuetype": {"name": "Task"}}}]}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := runReconvert(nil); err != nil {
+		t.Fatalf("Expected reconvert to succeed, got: %v", err)
+	}
+	issues, err := os.ReadFile(filepath.Join(".beads", "issues.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read issues.jsonl: %v", err)
+	}
+	if !strings.Contains(string(issues), `"title":"Cached"`) {
+		t.Errorf("Expected the cached issue in issues.jsonl, got:\n%s", issues)
+	}
+
+	if err := runReconvert([]string{"extra"}); err == nil || !strings.Contains(err.Error(), "takes no arguments") {
+		t.Errorf("Expected an argument error, got: %v", err)
+	}
+}
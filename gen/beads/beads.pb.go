@@ -139,21 +139,29 @@ func (Priority) EnumDescriptor() ([]byte, []int) {
 
 // Issue represents a beads issue stored as YAML in .beads/issues/
 type Issue struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Status        Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=beads.Status" json:"status,omitempty"`
-	Priority      Priority               `protobuf:"varint,5,opt,name=priority,proto3,enum=beads.Priority" json:"priority,omitempty"`
-	Epic          string                 `protobuf:"bytes,6,opt,name=epic,proto3" json:"epic,omitempty"`
-	Assignee      string                 `protobuf:"bytes,7,opt,name=assignee,proto3" json:"assignee,omitempty"`
-	Labels        []string               `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty"`
-	DependsOn     []string               `protobuf:"bytes,9,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
-	Created       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created,proto3" json:"created,omitempty"`
-	Updated       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated,proto3" json:"updated,omitempty"`
-	Metadata      *Metadata              `protobuf:"bytes,12,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title           string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description     string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status          Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=beads.Status" json:"status,omitempty"`
+	Priority        Priority               `protobuf:"varint,5,opt,name=priority,proto3,enum=beads.Priority" json:"priority,omitempty"`
+	Epic            string                 `protobuf:"bytes,6,opt,name=epic,proto3" json:"epic,omitempty"`
+	Assignee        string                 `protobuf:"bytes,7,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	Labels          []string               `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty"`
+	DependsOn       []string               `protobuf:"bytes,9,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	Created         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created,proto3" json:"created,omitempty"`
+	Updated         *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated,proto3" json:"updated,omitempty"`
+	Metadata        *Metadata              `protobuf:"bytes,12,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	FixVersions     []string               `protobuf:"bytes,13,rep,name=fix_versions,json=fixVersions,proto3" json:"fix_versions,omitempty"`
+	AffectsVersions []string               `protobuf:"bytes,14,rep,name=affects_versions,json=affectsVersions,proto3" json:"affects_versions,omitempty"`
+	Components      []string               `protobuf:"bytes,15,rep,name=components,proto3" json:"components,omitempty"`
+	Due             *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=due,proto3" json:"due,omitempty"`
+	Resolution      string                 `protobuf:"bytes,17,opt,name=resolution,proto3" json:"resolution,omitempty"` // Normalised resolution, e.g. "fixed", "wontfix", "duplicate"
+	Resolved        *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=resolved,proto3" json:"resolved,omitempty"`
+	Sprint          *Sprint                `protobuf:"bytes,19,opt,name=sprint,proto3" json:"sprint,omitempty"`
+	Rank            string                 `protobuf:"bytes,20,opt,name=rank,proto3" json:"rank,omitempty"` // Jira board rank; sorts lexicographically in board order
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Issue) Reset() {
@@ -270,6 +278,62 @@ func (x *Issue) GetMetadata() *Metadata {
 	return nil
 }
 
+func (x *Issue) GetFixVersions() []string {
+	if x != nil {
+		return x.FixVersions
+	}
+	return nil
+}
+
+func (x *Issue) GetAffectsVersions() []string {
+	if x != nil {
+		return x.AffectsVersions
+	}
+	return nil
+}
+
+func (x *Issue) GetComponents() []string {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *Issue) GetDue() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Due
+	}
+	return nil
+}
+
+func (x *Issue) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+func (x *Issue) GetResolved() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Resolved
+	}
+	return nil
+}
+
+func (x *Issue) GetSprint() *Sprint {
+	if x != nil {
+		return x.Sprint
+	}
+	return nil
+}
+
+func (x *Issue) GetRank() string {
+	if x != nil {
+		return x.Rank
+	}
+	return ""
+}
+
 // Metadata stores additional information about the issue
 type Metadata struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -278,6 +342,7 @@ type Metadata struct {
 	JiraIssueType string                 `protobuf:"bytes,3,opt,name=jira_issue_type,json=jiraIssueType,proto3" json:"jira_issue_type,omitempty"`
 	Custom        map[string]string      `protobuf:"bytes,4,rep,name=custom,proto3" json:"custom,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Repositories  []string               `protobuf:"bytes,5,rep,name=repositories,proto3" json:"repositories,omitempty"` // Git repository URLs or names for polyrepo support
+	PullRequests  []*PullRequest         `protobuf:"bytes,6,rep,name=pull_requests,json=pullRequests,proto3" json:"pull_requests,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -347,23 +412,106 @@ func (x *Metadata) GetRepositories() []string {
 	return nil
 }
 
-// Epic represents a beads epic
-type Epic struct {
+func (x *Metadata) GetPullRequests() []*PullRequest {
+	if x != nil {
+		return x.PullRequests
+	}
+	return nil
+}
+
+// PullRequest is a pull or merge request related to an issue
+type PullRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Status        Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=beads.Status" json:"status,omitempty"`
-	Created       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created,proto3" json:"created,omitempty"`
-	Updated       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated,proto3" json:"updated,omitempty"`
-	Metadata      *Metadata              `protobuf:"bytes,7,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"` // open, merged or declined
+	Repository    string                 `protobuf:"bytes,4,opt,name=repository,proto3" json:"repository,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *PullRequest) Reset() {
+	*x = PullRequest{}
+	mi := &file_beads_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullRequest) ProtoMessage() {}
+
+func (x *PullRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_beads_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullRequest.ProtoReflect.Descriptor instead.
+func (*PullRequest) Descriptor() ([]byte, []int) {
+	return file_beads_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PullRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *PullRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *PullRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *PullRequest) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+// Epic represents a beads epic
+type Epic struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description     string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status          Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=beads.Status" json:"status,omitempty"`
+	Created         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created,proto3" json:"created,omitempty"`
+	Updated         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated,proto3" json:"updated,omitempty"`
+	Metadata        *Metadata              `protobuf:"bytes,7,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Due             *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=due,proto3" json:"due,omitempty"`
+	Resolution      string                 `protobuf:"bytes,9,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	Resolved        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=resolved,proto3" json:"resolved,omitempty"`
+	Labels          []string               `protobuf:"bytes,11,rep,name=labels,proto3" json:"labels,omitempty"`
+	FixVersions     []string               `protobuf:"bytes,12,rep,name=fix_versions,json=fixVersions,proto3" json:"fix_versions,omitempty"`
+	AffectsVersions []string               `protobuf:"bytes,13,rep,name=affects_versions,json=affectsVersions,proto3" json:"affects_versions,omitempty"`
+	Components      []string               `protobuf:"bytes,14,rep,name=components,proto3" json:"components,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *Epic) Reset() {
 	*x = Epic{}
-	mi := &file_beads_proto_msgTypes[2]
+	mi := &file_beads_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -375,7 +523,7 @@ func (x *Epic) String() string {
 func (*Epic) ProtoMessage() {}
 
 func (x *Epic) ProtoReflect() protoreflect.Message {
-	mi := &file_beads_proto_msgTypes[2]
+	mi := &file_beads_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -388,7 +536,7 @@ func (x *Epic) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Epic.ProtoReflect.Descriptor instead.
 func (*Epic) Descriptor() ([]byte, []int) {
-	return file_beads_proto_rawDescGZIP(), []int{2}
+	return file_beads_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Epic) GetId() string {
@@ -440,18 +588,68 @@ func (x *Epic) GetMetadata() *Metadata {
 	return nil
 }
 
+func (x *Epic) GetDue() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Due
+	}
+	return nil
+}
+
+func (x *Epic) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+func (x *Epic) GetResolved() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Resolved
+	}
+	return nil
+}
+
+func (x *Epic) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Epic) GetFixVersions() []string {
+	if x != nil {
+		return x.FixVersions
+	}
+	return nil
+}
+
+func (x *Epic) GetAffectsVersions() []string {
+	if x != nil {
+		return x.AffectsVersions
+	}
+	return nil
+}
+
+func (x *Epic) GetComponents() []string {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
 // Export represents a collection of beads issues and epics for export
 type Export struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Issues        []*Issue               `protobuf:"bytes,1,rep,name=issues,proto3" json:"issues,omitempty"`
 	Epics         []*Epic                `protobuf:"bytes,2,rep,name=epics,proto3" json:"epics,omitempty"`
+	Sprint        *Sprint                `protobuf:"bytes,3,opt,name=sprint,proto3" json:"sprint,omitempty"` // Set when the export was fetched from a single sprint
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Export) Reset() {
 	*x = Export{}
-	mi := &file_beads_proto_msgTypes[3]
+	mi := &file_beads_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -463,7 +661,7 @@ func (x *Export) String() string {
 func (*Export) ProtoMessage() {}
 
 func (x *Export) ProtoReflect() protoreflect.Message {
-	mi := &file_beads_proto_msgTypes[3]
+	mi := &file_beads_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -476,7 +674,7 @@ func (x *Export) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Export.ProtoReflect.Descriptor instead.
 func (*Export) Descriptor() ([]byte, []int) {
-	return file_beads_proto_rawDescGZIP(), []int{3}
+	return file_beads_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Export) GetIssues() []*Issue {
@@ -493,11 +691,119 @@ func (x *Export) GetEpics() []*Epic {
 	return nil
 }
 
+func (x *Export) GetSprint() *Sprint {
+	if x != nil {
+		return x.Sprint
+	}
+	return nil
+}
+
+// Sprint represents the sprint an issue is planned in
+type Sprint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Goal          string                 `protobuf:"bytes,4,opt,name=goal,proto3" json:"goal,omitempty"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end,proto3" json:"end,omitempty"`
+	Completed     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=completed,proto3" json:"completed,omitempty"`
+	BoardId       int64                  `protobuf:"varint,8,opt,name=board_id,json=boardId,proto3" json:"board_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sprint) Reset() {
+	*x = Sprint{}
+	mi := &file_beads_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sprint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sprint) ProtoMessage() {}
+
+func (x *Sprint) ProtoReflect() protoreflect.Message {
+	mi := &file_beads_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sprint.ProtoReflect.Descriptor instead.
+func (*Sprint) Descriptor() ([]byte, []int) {
+	return file_beads_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Sprint) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Sprint) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Sprint) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Sprint) GetGoal() string {
+	if x != nil {
+		return x.Goal
+	}
+	return ""
+}
+
+func (x *Sprint) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Sprint) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Sprint) GetCompleted() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Completed
+	}
+	return nil
+}
+
+func (x *Sprint) GetBoardId() int64 {
+	if x != nil {
+		return x.BoardId
+	}
+	return 0
+}
+
 var File_beads_proto protoreflect.FileDescriptor
 
 const file_beads_proto_rawDesc = "" +
 	"\n" +
-	"\vbeads.proto\x12\x05beads\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa3\x03\n" +
+	"\vbeads.proto\x12\x05beads\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd2\x05\n" +
 	"\x05Issue\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
@@ -512,16 +818,36 @@ const file_beads_proto_rawDesc = "" +
 	"\acreated\x18\n" +
 	" \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x124\n" +
 	"\aupdated\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\aupdated\x12+\n" +
-	"\bmetadata\x18\f \x01(\v2\x0f.beads.MetadataR\bmetadata\"\xfa\x01\n" +
+	"\bmetadata\x18\f \x01(\v2\x0f.beads.MetadataR\bmetadata\x12!\n" +
+	"\ffix_versions\x18\r \x03(\tR\vfixVersions\x12)\n" +
+	"\x10affects_versions\x18\x0e \x03(\tR\x0faffectsVersions\x12\x1e\n" +
+	"\n" +
+	"components\x18\x0f \x03(\tR\n" +
+	"components\x12,\n" +
+	"\x03due\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\x03due\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\x11 \x01(\tR\n" +
+	"resolution\x126\n" +
+	"\bresolved\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\bresolved\x12%\n" +
+	"\x06sprint\x18\x13 \x01(\v2\r.beads.SprintR\x06sprint\x12\x12\n" +
+	"\x04rank\x18\x14 \x01(\tR\x04rank\"\xb3\x02\n" +
 	"\bMetadata\x12\x19\n" +
 	"\bjira_key\x18\x01 \x01(\tR\ajiraKey\x12\x17\n" +
 	"\ajira_id\x18\x02 \x01(\tR\x06jiraId\x12&\n" +
 	"\x0fjira_issue_type\x18\x03 \x01(\tR\rjiraIssueType\x123\n" +
 	"\x06custom\x18\x04 \x03(\v2\x1b.beads.Metadata.CustomEntryR\x06custom\x12\"\n" +
-	"\frepositories\x18\x05 \x03(\tR\frepositories\x1a9\n" +
+	"\frepositories\x18\x05 \x03(\tR\frepositories\x127\n" +
+	"\rpull_requests\x18\x06 \x03(\v2\x12.beads.PullRequestR\fpullRequests\x1a9\n" +
 	"\vCustomEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8c\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"k\n" +
+	"\vPullRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x04 \x01(\tR\n" +
+	"repository\"\x98\x04\n" +
 	"\x04Epic\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -529,10 +855,32 @@ const file_beads_proto_rawDesc = "" +
 	"\x06status\x18\x04 \x01(\x0e2\r.beads.StatusR\x06status\x124\n" +
 	"\acreated\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x124\n" +
 	"\aupdated\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aupdated\x12+\n" +
-	"\bmetadata\x18\a \x01(\v2\x0f.beads.MetadataR\bmetadata\"Q\n" +
+	"\bmetadata\x18\a \x01(\v2\x0f.beads.MetadataR\bmetadata\x12,\n" +
+	"\x03due\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x03due\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\t \x01(\tR\n" +
+	"resolution\x126\n" +
+	"\bresolved\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\bresolved\x12\x16\n" +
+	"\x06labels\x18\v \x03(\tR\x06labels\x12!\n" +
+	"\ffix_versions\x18\f \x03(\tR\vfixVersions\x12)\n" +
+	"\x10affects_versions\x18\r \x03(\tR\x0faffectsVersions\x12\x1e\n" +
+	"\n" +
+	"components\x18\x0e \x03(\tR\n" +
+	"components\"x\n" +
 	"\x06Export\x12$\n" +
 	"\x06issues\x18\x01 \x03(\v2\f.beads.IssueR\x06issues\x12!\n" +
-	"\x05epics\x18\x02 \x03(\v2\v.beads.EpicR\x05epics*p\n" +
+	"\x05epics\x18\x02 \x03(\v2\v.beads.EpicR\x05epics\x12%\n" +
+	"\x06sprint\x18\x03 \x01(\v2\r.beads.SprintR\x06sprint\"\x8b\x02\n" +
+	"\x06Sprint\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x12\n" +
+	"\x04goal\x18\x04 \x01(\tR\x04goal\x120\n" +
+	"\x05start\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x128\n" +
+	"\tcompleted\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcompleted\x12\x19\n" +
+	"\bboard_id\x18\b \x01(\x03R\aboardId*p\n" +
 	"\x06Status\x12\x16\n" +
 	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x0f\n" +
 	"\vSTATUS_OPEN\x10\x01\x12\x16\n" +
@@ -560,35 +908,47 @@ func file_beads_proto_rawDescGZIP() []byte {
 }
 
 var file_beads_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_beads_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_beads_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_beads_proto_goTypes = []any{
 	(Status)(0),                   // 0: beads.Status
 	(Priority)(0),                 // 1: beads.Priority
 	(*Issue)(nil),                 // 2: beads.Issue
 	(*Metadata)(nil),              // 3: beads.Metadata
-	(*Epic)(nil),                  // 4: beads.Epic
-	(*Export)(nil),                // 5: beads.Export
-	nil,                           // 6: beads.Metadata.CustomEntry
-	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+	(*PullRequest)(nil),           // 4: beads.PullRequest
+	(*Epic)(nil),                  // 5: beads.Epic
+	(*Export)(nil),                // 6: beads.Export
+	(*Sprint)(nil),                // 7: beads.Sprint
+	nil,                           // 8: beads.Metadata.CustomEntry
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
 }
 var file_beads_proto_depIdxs = []int32{
 	0,  // 0: beads.Issue.status:type_name -> beads.Status
 	1,  // 1: beads.Issue.priority:type_name -> beads.Priority
-	7,  // 2: beads.Issue.created:type_name -> google.protobuf.Timestamp
-	7,  // 3: beads.Issue.updated:type_name -> google.protobuf.Timestamp
+	9,  // 2: beads.Issue.created:type_name -> google.protobuf.Timestamp
+	9,  // 3: beads.Issue.updated:type_name -> google.protobuf.Timestamp
 	3,  // 4: beads.Issue.metadata:type_name -> beads.Metadata
-	6,  // 5: beads.Metadata.custom:type_name -> beads.Metadata.CustomEntry
-	0,  // 6: beads.Epic.status:type_name -> beads.Status
-	7,  // 7: beads.Epic.created:type_name -> google.protobuf.Timestamp
-	7,  // 8: beads.Epic.updated:type_name -> google.protobuf.Timestamp
-	3,  // 9: beads.Epic.metadata:type_name -> beads.Metadata
-	2,  // 10: beads.Export.issues:type_name -> beads.Issue
-	4,  // 11: beads.Export.epics:type_name -> beads.Epic
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	9,  // 5: beads.Issue.due:type_name -> google.protobuf.Timestamp
+	9,  // 6: beads.Issue.resolved:type_name -> google.protobuf.Timestamp
+	7,  // 7: beads.Issue.sprint:type_name -> beads.Sprint
+	8,  // 8: beads.Metadata.custom:type_name -> beads.Metadata.CustomEntry
+	4,  // 9: beads.Metadata.pull_requests:type_name -> beads.PullRequest
+	0,  // 10: beads.Epic.status:type_name -> beads.Status
+	9,  // 11: beads.Epic.created:type_name -> google.protobuf.Timestamp
+	9,  // 12: beads.Epic.updated:type_name -> google.protobuf.Timestamp
+	3,  // 13: beads.Epic.metadata:type_name -> beads.Metadata
+	9,  // 14: beads.Epic.due:type_name -> google.protobuf.Timestamp
+	9,  // 15: beads.Epic.resolved:type_name -> google.protobuf.Timestamp
+	2,  // 16: beads.Export.issues:type_name -> beads.Issue
+	5,  // 17: beads.Export.epics:type_name -> beads.Epic
+	7,  // 18: beads.Export.sprint:type_name -> beads.Sprint
+	9,  // 19: beads.Sprint.start:type_name -> google.protobuf.Timestamp
+	9,  // 20: beads.Sprint.end:type_name -> google.protobuf.Timestamp
+	9,  // 21: beads.Sprint.completed:type_name -> google.protobuf.Timestamp
+	22, // [22:22] is the sub-list for method output_type
+	22, // [22:22] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_beads_proto_init() }
@@ -602,7 +962,7 @@ func file_beads_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_beads_proto_rawDesc), len(file_beads_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   5,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
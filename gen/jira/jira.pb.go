@@ -26,6 +26,7 @@ const (
 type Export struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Issues        []*Issue               `protobuf:"bytes,1,rep,name=issues,proto3" json:"issues,omitempty"`
+	Sprint        *Sprint                `protobuf:"bytes,2,opt,name=sprint,proto3" json:"sprint,omitempty"` // Set when the export was fetched from a single sprint
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -67,6 +68,13 @@ func (x *Export) GetIssues() []*Issue {
 	return nil
 }
 
+func (x *Export) GetSprint() *Sprint {
+	if x != nil {
+		return x.Sprint
+	}
+	return nil
+}
+
 // Issue represents a Jira issue (story, epic, subtask, etc.)
 type Issue struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -74,6 +82,9 @@ type Issue struct {
 	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
 	Self          string                 `protobuf:"bytes,3,opt,name=self,proto3" json:"self,omitempty"`
 	Fields        *Fields                `protobuf:"bytes,4,opt,name=fields,proto3" json:"fields,omitempty"`
+	RemoteLinks   []*RemoteLink          `protobuf:"bytes,5,rep,name=remote_links,json=remoteLinks,proto3" json:"remote_links,omitempty"`    // From /issue/{key}/remotelink
+	PullRequests  []*PullRequest         `protobuf:"bytes,6,rep,name=pull_requests,json=pullRequests,proto3" json:"pull_requests,omitempty"` // From the development information panel
+	Repositories  []string               `protobuf:"bytes,7,rep,name=repositories,proto3" json:"repositories,omitempty"`                     // Repository URLs from the development information panel
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -136,25 +147,57 @@ func (x *Issue) GetFields() *Fields {
 	return nil
 }
 
+func (x *Issue) GetRemoteLinks() []*RemoteLink {
+	if x != nil {
+		return x.RemoteLinks
+	}
+	return nil
+}
+
+func (x *Issue) GetPullRequests() []*PullRequest {
+	if x != nil {
+		return x.PullRequests
+	}
+	return nil
+}
+
+func (x *Issue) GetRepositories() []string {
+	if x != nil {
+		return x.Repositories
+	}
+	return nil
+}
+
 // Fields contains the detailed information about a Jira issue
 type Fields struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Summary       string                 `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	IssueType     *IssueType             `protobuf:"bytes,3,opt,name=issue_type,json=issueType,proto3" json:"issue_type,omitempty"`
-	Status        *Status                `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
-	Priority      *Priority              `protobuf:"bytes,5,opt,name=priority,proto3" json:"priority,omitempty"`
-	Assignee      *User                  `protobuf:"bytes,6,opt,name=assignee,proto3" json:"assignee,omitempty"`
-	Reporter      *User                  `protobuf:"bytes,7,opt,name=reporter,proto3" json:"reporter,omitempty"`
-	Created       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created,proto3" json:"created,omitempty"`
-	Updated       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated,proto3" json:"updated,omitempty"`
-	Labels        []string               `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty"`
-	IssueLinks    []*IssueLink           `protobuf:"bytes,11,rep,name=issue_links,json=issueLinks,proto3" json:"issue_links,omitempty"`
-	Parent        *Parent                `protobuf:"bytes,12,opt,name=parent,proto3" json:"parent,omitempty"`
-	Epic          *Epic                  `protobuf:"bytes,13,opt,name=epic,proto3" json:"epic,omitempty"`
-	Subtasks      []*Subtask             `protobuf:"bytes,14,rep,name=subtasks,proto3" json:"subtasks,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Summary        string                 `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	Description    string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	IssueType      *IssueType             `protobuf:"bytes,3,opt,name=issue_type,json=issueType,proto3" json:"issue_type,omitempty"`
+	Status         *Status                `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Priority       *Priority              `protobuf:"bytes,5,opt,name=priority,proto3" json:"priority,omitempty"`
+	Assignee       *User                  `protobuf:"bytes,6,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	Reporter       *User                  `protobuf:"bytes,7,opt,name=reporter,proto3" json:"reporter,omitempty"`
+	Created        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created,proto3" json:"created,omitempty"`
+	Updated        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated,proto3" json:"updated,omitempty"`
+	Labels         []string               `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty"`
+	IssueLinks     []*IssueLink           `protobuf:"bytes,11,rep,name=issue_links,json=issueLinks,proto3" json:"issue_links,omitempty"`
+	Parent         *Parent                `protobuf:"bytes,12,opt,name=parent,proto3" json:"parent,omitempty"`
+	Epic           *Epic                  `protobuf:"bytes,13,opt,name=epic,proto3" json:"epic,omitempty"`
+	Subtasks       []*Subtask             `protobuf:"bytes,14,rep,name=subtasks,proto3" json:"subtasks,omitempty"`
+	FixVersions    []*Version             `protobuf:"bytes,15,rep,name=fix_versions,json=fixVersions,proto3" json:"fix_versions,omitempty"`
+	Versions       []*Version             `protobuf:"bytes,16,rep,name=versions,proto3" json:"versions,omitempty"` // "Affects versions"
+	Components     []*Component           `protobuf:"bytes,17,rep,name=components,proto3" json:"components,omitempty"`
+	DueDate        *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	Resolution     *Resolution            `protobuf:"bytes,19,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	ResolutionDate *timestamppb.Timestamp `protobuf:"bytes,20,opt,name=resolution_date,json=resolutionDate,proto3" json:"resolution_date,omitempty"`
+	Sprint         *Sprint                `protobuf:"bytes,21,opt,name=sprint,proto3" json:"sprint,omitempty"` // Current sprint (Agile API only)
+	ClosedSprints  []*Sprint              `protobuf:"bytes,22,rep,name=closed_sprints,json=closedSprints,proto3" json:"closed_sprints,omitempty"`
+	Rank           string                 `protobuf:"bytes,23,opt,name=rank,proto3" json:"rank,omitempty"` // Lexorank value of the board "Rank" field, e.g. "0|i0000f:"
+	Comments       []*Comment             `protobuf:"bytes,24,rep,name=comments,proto3" json:"comments,omitempty"`
+	CustomFields   []*CustomField         `protobuf:"bytes,25,rep,name=custom_fields,json=customFields,proto3" json:"custom_fields,omitempty"` // From XML exports, which name each field
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Fields) Reset() {
@@ -285,6 +328,83 @@ func (x *Fields) GetSubtasks() []*Subtask {
 	return nil
 }
 
+func (x *Fields) GetFixVersions() []*Version {
+	if x != nil {
+		return x.FixVersions
+	}
+	return nil
+}
+
+func (x *Fields) GetVersions() []*Version {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *Fields) GetComponents() []*Component {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *Fields) GetDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDate
+	}
+	return nil
+}
+
+func (x *Fields) GetResolution() *Resolution {
+	if x != nil {
+		return x.Resolution
+	}
+	return nil
+}
+
+func (x *Fields) GetResolutionDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResolutionDate
+	}
+	return nil
+}
+
+func (x *Fields) GetSprint() *Sprint {
+	if x != nil {
+		return x.Sprint
+	}
+	return nil
+}
+
+func (x *Fields) GetClosedSprints() []*Sprint {
+	if x != nil {
+		return x.ClosedSprints
+	}
+	return nil
+}
+
+func (x *Fields) GetRank() string {
+	if x != nil {
+		return x.Rank
+	}
+	return ""
+}
+
+func (x *Fields) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *Fields) GetCustomFields() []*CustomField {
+	if x != nil {
+		return x.CustomFields
+	}
+	return nil
+}
+
 // IssueType represents the type of a Jira issue
 type IssueType struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -452,6 +572,67 @@ func (x *StatusCategory) GetName() string {
 	return ""
 }
 
+// Resolution represents how a Jira issue was resolved (e.g., "Done", "Won't Do")
+type Resolution struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resolution) Reset() {
+	*x = Resolution{}
+	mi := &file_jira_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resolution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resolution) ProtoMessage() {}
+
+func (x *Resolution) ProtoReflect() protoreflect.Message {
+	mi := &file_jira_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resolution.ProtoReflect.Descriptor instead.
+func (*Resolution) Descriptor() ([]byte, []int) {
+	return file_jira_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Resolution) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Resolution) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Resolution) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
 // Priority represents the priority of a Jira issue
 type Priority struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -463,7 +644,7 @@ type Priority struct {
 
 func (x *Priority) Reset() {
 	*x = Priority{}
-	mi := &file_jira_proto_msgTypes[6]
+	mi := &file_jira_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -475,7 +656,7 @@ func (x *Priority) String() string {
 func (*Priority) ProtoMessage() {}
 
 func (x *Priority) ProtoReflect() protoreflect.Message {
-	mi := &file_jira_proto_msgTypes[6]
+	mi := &file_jira_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -488,7 +669,7 @@ func (x *Priority) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Priority.ProtoReflect.Descriptor instead.
 func (*Priority) Descriptor() ([]byte, []int) {
-	return file_jira_proto_rawDescGZIP(), []int{6}
+	return file_jira_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Priority) GetName() string {
@@ -517,7 +698,7 @@ type User struct {
 
 func (x *User) Reset() {
 	*x = User{}
-	mi := &file_jira_proto_msgTypes[7]
+	mi := &file_jira_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -529,7 +710,7 @@ func (x *User) String() string {
 func (*User) ProtoMessage() {}
 
 func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_jira_proto_msgTypes[7]
+	mi := &file_jira_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -542,7 +723,7 @@ func (x *User) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use User.ProtoReflect.Descriptor instead.
 func (*User) Descriptor() ([]byte, []int) {
-	return file_jira_proto_rawDescGZIP(), []int{7}
+	return file_jira_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *User) GetAccountId() string {
@@ -579,7 +760,7 @@ type IssueLink struct {
 
 func (x *IssueLink) Reset() {
 	*x = IssueLink{}
-	mi := &file_jira_proto_msgTypes[8]
+	mi := &file_jira_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -591,7 +772,7 @@ func (x *IssueLink) String() string {
 func (*IssueLink) ProtoMessage() {}
 
 func (x *IssueLink) ProtoReflect() protoreflect.Message {
-	mi := &file_jira_proto_msgTypes[8]
+	mi := &file_jira_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -604,7 +785,7 @@ func (x *IssueLink) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IssueLink.ProtoReflect.Descriptor instead.
 func (*IssueLink) Descriptor() ([]byte, []int) {
-	return file_jira_proto_rawDescGZIP(), []int{8}
+	return file_jira_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *IssueLink) GetId() string {
@@ -647,7 +828,7 @@ type IssueLinkType struct {
 
 func (x *IssueLinkType) Reset() {
 	*x = IssueLinkType{}
-	mi := &file_jira_proto_msgTypes[9]
+	mi := &file_jira_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -659,7 +840,7 @@ func (x *IssueLinkType) String() string {
 func (*IssueLinkType) ProtoMessage() {}
 
 func (x *IssueLinkType) ProtoReflect() protoreflect.Message {
-	mi := &file_jira_proto_msgTypes[9]
+	mi := &file_jira_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -672,7 +853,7 @@ func (x *IssueLinkType) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IssueLinkType.ProtoReflect.Descriptor instead.
 func (*IssueLinkType) Descriptor() ([]byte, []int) {
-	return file_jira_proto_rawDescGZIP(), []int{9}
+	return file_jira_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *IssueLinkType) GetName() string {
@@ -709,7 +890,7 @@ type LinkedIssue struct {
 
 func (x *LinkedIssue) Reset() {
 	*x = LinkedIssue{}
-	mi := &file_jira_proto_msgTypes[10]
+	mi := &file_jira_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -721,7 +902,7 @@ func (x *LinkedIssue) String() string {
 func (*LinkedIssue) ProtoMessage() {}
 
 func (x *LinkedIssue) ProtoReflect() protoreflect.Message {
-	mi := &file_jira_proto_msgTypes[10]
+	mi := &file_jira_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -734,7 +915,7 @@ func (x *LinkedIssue) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LinkedIssue.ProtoReflect.Descriptor instead.
 func (*LinkedIssue) Descriptor() ([]byte, []int) {
-	return file_jira_proto_rawDescGZIP(), []int{10}
+	return file_jira_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *LinkedIssue) GetId() string {
@@ -777,7 +958,7 @@ type LinkedFields struct {
 
 func (x *LinkedFields) Reset() {
 	*x = LinkedFields{}
-	mi := &file_jira_proto_msgTypes[11]
+	mi := &file_jira_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -789,7 +970,7 @@ func (x *LinkedFields) String() string {
 func (*LinkedFields) ProtoMessage() {}
 
 func (x *LinkedFields) ProtoReflect() protoreflect.Message {
-	mi := &file_jira_proto_msgTypes[11]
+	mi := &file_jira_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -802,7 +983,7 @@ func (x *LinkedFields) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LinkedFields.ProtoReflect.Descriptor instead.
 func (*LinkedFields) Descriptor() ([]byte, []int) {
-	return file_jira_proto_rawDescGZIP(), []int{11}
+	return file_jira_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *LinkedFields) GetSummary() string {
@@ -839,7 +1020,7 @@ type Parent struct {
 
 func (x *Parent) Reset() {
 	*x = Parent{}
-	mi := &file_jira_proto_msgTypes[12]
+	mi := &file_jira_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -851,7 +1032,7 @@ func (x *Parent) String() string {
 func (*Parent) ProtoMessage() {}
 
 func (x *Parent) ProtoReflect() protoreflect.Message {
-	mi := &file_jira_proto_msgTypes[12]
+	mi := &file_jira_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -864,7 +1045,7 @@ func (x *Parent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Parent.ProtoReflect.Descriptor instead.
 func (*Parent) Descriptor() ([]byte, []int) {
-	return file_jira_proto_rawDescGZIP(), []int{12}
+	return file_jira_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *Parent) GetId() string {
@@ -910,7 +1091,7 @@ type Epic struct {
 
 func (x *Epic) Reset() {
 	*x = Epic{}
-	mi := &file_jira_proto_msgTypes[13]
+	mi := &file_jira_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -922,7 +1103,7 @@ func (x *Epic) String() string {
 func (*Epic) ProtoMessage() {}
 
 func (x *Epic) ProtoReflect() protoreflect.Message {
-	mi := &file_jira_proto_msgTypes[13]
+	mi := &file_jira_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -935,7 +1116,7 @@ func (x *Epic) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Epic.ProtoReflect.Descriptor instead.
 func (*Epic) Descriptor() ([]byte, []int) {
-	return file_jira_proto_rawDescGZIP(), []int{13}
+	return file_jira_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *Epic) GetId() string {
@@ -993,7 +1174,7 @@ type Subtask struct {
 
 func (x *Subtask) Reset() {
 	*x = Subtask{}
-	mi := &file_jira_proto_msgTypes[14]
+	mi := &file_jira_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1005,7 +1186,7 @@ func (x *Subtask) String() string {
 func (*Subtask) ProtoMessage() {}
 
 func (x *Subtask) ProtoReflect() protoreflect.Message {
-	mi := &file_jira_proto_msgTypes[14]
+	mi := &file_jira_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1018,7 +1199,7 @@ func (x *Subtask) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Subtask.ProtoReflect.Descriptor instead.
 func (*Subtask) Descriptor() ([]byte, []int) {
-	return file_jira_proto_rawDescGZIP(), []int{14}
+	return file_jira_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *Subtask) GetId() string {
@@ -1049,79 +1230,658 @@ func (x *Subtask) GetFields() *LinkedFields {
 	return nil
 }
 
-var File_jira_proto protoreflect.FileDescriptor
+// Version represents a project version (release) referenced by an issue
+type Version struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Released      bool                   `protobuf:"varint,3,opt,name=released,proto3" json:"released,omitempty"`
+	Archived      bool                   `protobuf:"varint,4,opt,name=archived,proto3" json:"archived,omitempty"`
+	ReleaseDate   string                 `protobuf:"bytes,5,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"` // e.g., "2024-03-01"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_jira_proto_rawDesc = "" +
-	"\n" +
-	"\n" +
-	"jira.proto\x12\x04jira\x1a\x1fgoogle/protobuf/timestamp.proto\"-\n" +
-	"\x06Export\x12#\n" +
-	"\x06issues\x18\x01 \x03(\v2\v.jira.IssueR\x06issues\"c\n" +
-	"\x05Issue\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
-	"\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n" +
-	"\x04self\x18\x03 \x01(\tR\x04self\x12$\n" +
-	"\x06fields\x18\x04 \x01(\v2\f.jira.FieldsR\x06fields\"\xbd\x04\n" +
-	"\x06Fields\x12\x18\n" +
-	"\asummary\x18\x01 \x01(\tR\asummary\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12.\n" +
-	"\n" +
-	"issue_type\x18\x03 \x01(\v2\x0f.jira.IssueTypeR\tissueType\x12$\n" +
-	"\x06status\x18\x04 \x01(\v2\f.jira.StatusR\x06status\x12*\n" +
-	"\bpriority\x18\x05 \x01(\v2\x0e.jira.PriorityR\bpriority\x12&\n" +
-	"\bassignee\x18\x06 \x01(\v2\n" +
-	".jira.UserR\bassignee\x12&\n" +
-	"\breporter\x18\a \x01(\v2\n" +
-	".jira.UserR\breporter\x124\n" +
-	"\acreated\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x124\n" +
-	"\aupdated\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\aupdated\x12\x16\n" +
-	"\x06labels\x18\n" +
-	" \x03(\tR\x06labels\x120\n" +
-	"\vissue_links\x18\v \x03(\v2\x0f.jira.IssueLinkR\n" +
-	"issueLinks\x12$\n" +
-	"\x06parent\x18\f \x01(\v2\f.jira.ParentR\x06parent\x12\x1e\n" +
-	"\x04epic\x18\r \x01(\v2\n" +
-	".jira.EpicR\x04epic\x12)\n" +
-	"\bsubtasks\x18\x0e \x03(\v2\r.jira.SubtaskR\bsubtasks\"[\n" +
-	"\tIssueType\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x18\n" +
-	"\asubtask\x18\x03 \x01(\bR\asubtask\"[\n" +
-	"\x06Status\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12=\n" +
-	"\x0fstatus_category\x18\x02 \x01(\v2\x14.jira.StatusCategoryR\x0estatusCategory\"6\n" +
-	"\x0eStatusCategory\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\".\n" +
-	"\bPriority\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
-	"\x02id\x18\x02 \x01(\tR\x02id\"m\n" +
-	"\x04User\x12\x1d\n" +
-	"\n" +
-	"account_id\x18\x01 \x01(\tR\taccountId\x12!\n" +
-	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12#\n" +
-	"\remail_address\x18\x03 \x01(\tR\femailAddress\"\xb2\x01\n" +
-	"\tIssueLink\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
-	"\x04type\x18\x02 \x01(\v2\x13.jira.IssueLinkTypeR\x04type\x124\n" +
-	"\finward_issue\x18\x03 \x01(\v2\x11.jira.LinkedIssueR\vinwardIssue\x126\n" +
-	"\routward_issue\x18\x04 \x01(\v2\x11.jira.LinkedIssueR\foutwardIssue\"U\n" +
-	"\rIssueLinkType\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
-	"\x06inward\x18\x02 \x01(\tR\x06inward\x12\x18\n" +
-	"\aoutward\x18\x03 \x01(\tR\aoutward\"o\n" +
-	"\vLinkedIssue\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
-	"\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n" +
-	"\x04self\x18\x03 \x01(\tR\x04self\x12*\n" +
-	"\x06fields\x18\x04 \x01(\v2\x12.jira.LinkedFieldsR\x06fields\"~\n" +
-	"\fLinkedFields\x12\x18\n" +
-	"\asummary\x18\x01 \x01(\tR\asummary\x12$\n" +
-	"\x06status\x18\x02 \x01(\v2\f.jira.StatusR\x06status\x12.\n" +
-	"\n" +
-	"issue_type\x18\x03 \x01(\v2\x0f.jira.IssueTypeR\tissueType\"j\n" +
-	"\x06Parent\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+func (x *Version) Reset() {
+	*x = Version{}
+	mi := &file_jira_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_jira_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_jira_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Version) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Version) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Version) GetReleased() bool {
+	if x != nil {
+		return x.Released
+	}
+	return false
+}
+
+func (x *Version) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *Version) GetReleaseDate() string {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return ""
+}
+
+// Component represents a project component referenced by an issue
+type Component struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Component) Reset() {
+	*x = Component{}
+	mi := &file_jira_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Component) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Component) ProtoMessage() {}
+
+func (x *Component) ProtoReflect() protoreflect.Message {
+	mi := &file_jira_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Component.ProtoReflect.Descriptor instead.
+func (*Component) Descriptor() ([]byte, []int) {
+	return file_jira_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Component) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Component) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Sprint represents a Jira Agile sprint
+type Sprint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"` // "future", "active", "closed"
+	Goal          string                 `protobuf:"bytes,4,opt,name=goal,proto3" json:"goal,omitempty"`
+	StartDate     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	CompleteDate  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=complete_date,json=completeDate,proto3" json:"complete_date,omitempty"`
+	OriginBoardId int64                  `protobuf:"varint,8,opt,name=origin_board_id,json=originBoardId,proto3" json:"origin_board_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sprint) Reset() {
+	*x = Sprint{}
+	mi := &file_jira_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sprint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sprint) ProtoMessage() {}
+
+func (x *Sprint) ProtoReflect() protoreflect.Message {
+	mi := &file_jira_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sprint.ProtoReflect.Descriptor instead.
+func (*Sprint) Descriptor() ([]byte, []int) {
+	return file_jira_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Sprint) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Sprint) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Sprint) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Sprint) GetGoal() string {
+	if x != nil {
+		return x.Goal
+	}
+	return ""
+}
+
+func (x *Sprint) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *Sprint) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *Sprint) GetCompleteDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompleteDate
+	}
+	return nil
+}
+
+func (x *Sprint) GetOriginBoardId() int64 {
+	if x != nil {
+		return x.OriginBoardId
+	}
+	return 0
+}
+
+// Comment represents a comment on a Jira issue
+type Comment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Author        *User                  `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Created       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_jira_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_jira_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_jira_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Comment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Comment) GetAuthor() *User {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+func (x *Comment) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Comment) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+// CustomField holds the values of a custom field, e.g. "Epic Link"
+type CustomField struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`   // e.g., "customfield_10014"
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"` // Field type, e.g., "com.pyxis.greenhopper.jira:gh-epic-link"
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Values        []string               `protobuf:"bytes,4,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomField) Reset() {
+	*x = CustomField{}
+	mi := &file_jira_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomField) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomField) ProtoMessage() {}
+
+func (x *CustomField) ProtoReflect() protoreflect.Message {
+	mi := &file_jira_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomField.ProtoReflect.Descriptor instead.
+func (*CustomField) Descriptor() ([]byte, []int) {
+	return file_jira_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CustomField) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CustomField) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CustomField) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CustomField) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// RemoteLink represents a link from a Jira issue to an external resource
+type RemoteLink struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url             string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Title           string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Relationship    string                 `protobuf:"bytes,4,opt,name=relationship,proto3" json:"relationship,omitempty"`
+	ApplicationType string                 `protobuf:"bytes,5,opt,name=application_type,json=applicationType,proto3" json:"application_type,omitempty"`
+	ApplicationName string                 `protobuf:"bytes,6,opt,name=application_name,json=applicationName,proto3" json:"application_name,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RemoteLink) Reset() {
+	*x = RemoteLink{}
+	mi := &file_jira_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoteLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteLink) ProtoMessage() {}
+
+func (x *RemoteLink) ProtoReflect() protoreflect.Message {
+	mi := &file_jira_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteLink.ProtoReflect.Descriptor instead.
+func (*RemoteLink) Descriptor() ([]byte, []int) {
+	return file_jira_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RemoteLink) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RemoteLink) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *RemoteLink) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *RemoteLink) GetRelationship() string {
+	if x != nil {
+		return x.Relationship
+	}
+	return ""
+}
+
+func (x *RemoteLink) GetApplicationType() string {
+	if x != nil {
+		return x.ApplicationType
+	}
+	return ""
+}
+
+func (x *RemoteLink) GetApplicationName() string {
+	if x != nil {
+		return x.ApplicationName
+	}
+	return ""
+}
+
+// PullRequest represents a pull or merge request from Jira's development information
+type PullRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Url               string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Status            string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // OPEN, MERGED or DECLINED
+	RepositoryName    string                 `protobuf:"bytes,5,opt,name=repository_name,json=repositoryName,proto3" json:"repository_name,omitempty"`
+	RepositoryUrl     string                 `protobuf:"bytes,6,opt,name=repository_url,json=repositoryUrl,proto3" json:"repository_url,omitempty"`
+	SourceBranch      string                 `protobuf:"bytes,7,opt,name=source_branch,json=sourceBranch,proto3" json:"source_branch,omitempty"`
+	DestinationBranch string                 `protobuf:"bytes,8,opt,name=destination_branch,json=destinationBranch,proto3" json:"destination_branch,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PullRequest) Reset() {
+	*x = PullRequest{}
+	mi := &file_jira_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullRequest) ProtoMessage() {}
+
+func (x *PullRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jira_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullRequest.ProtoReflect.Descriptor instead.
+func (*PullRequest) Descriptor() ([]byte, []int) {
+	return file_jira_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PullRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PullRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PullRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *PullRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PullRequest) GetRepositoryName() string {
+	if x != nil {
+		return x.RepositoryName
+	}
+	return ""
+}
+
+func (x *PullRequest) GetRepositoryUrl() string {
+	if x != nil {
+		return x.RepositoryUrl
+	}
+	return ""
+}
+
+func (x *PullRequest) GetSourceBranch() string {
+	if x != nil {
+		return x.SourceBranch
+	}
+	return ""
+}
+
+func (x *PullRequest) GetDestinationBranch() string {
+	if x != nil {
+		return x.DestinationBranch
+	}
+	return ""
+}
+
+var File_jira_proto protoreflect.FileDescriptor
+
+const file_jira_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"jira.proto\x12\x04jira\x1a\x1fgoogle/protobuf/timestamp.proto\"S\n" +
+	"\x06Export\x12#\n" +
+	"\x06issues\x18\x01 \x03(\v2\v.jira.IssueR\x06issues\x12$\n" +
+	"\x06sprint\x18\x02 \x01(\v2\f.jira.SprintR\x06sprint\"\xf4\x01\n" +
+	"\x05Issue\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n" +
+	"\x04self\x18\x03 \x01(\tR\x04self\x12$\n" +
+	"\x06fields\x18\x04 \x01(\v2\f.jira.FieldsR\x06fields\x123\n" +
+	"\fremote_links\x18\x05 \x03(\v2\x10.jira.RemoteLinkR\vremoteLinks\x126\n" +
+	"\rpull_requests\x18\x06 \x03(\v2\x11.jira.PullRequestR\fpullRequests\x12\"\n" +
+	"\frepositories\x18\a \x03(\tR\frepositories\"\xcb\b\n" +
+	"\x06Fields\x12\x18\n" +
+	"\asummary\x18\x01 \x01(\tR\asummary\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12.\n" +
+	"\n" +
+	"issue_type\x18\x03 \x01(\v2\x0f.jira.IssueTypeR\tissueType\x12$\n" +
+	"\x06status\x18\x04 \x01(\v2\f.jira.StatusR\x06status\x12*\n" +
+	"\bpriority\x18\x05 \x01(\v2\x0e.jira.PriorityR\bpriority\x12&\n" +
+	"\bassignee\x18\x06 \x01(\v2\n" +
+	".jira.UserR\bassignee\x12&\n" +
+	"\breporter\x18\a \x01(\v2\n" +
+	".jira.UserR\breporter\x124\n" +
+	"\acreated\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x124\n" +
+	"\aupdated\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\aupdated\x12\x16\n" +
+	"\x06labels\x18\n" +
+	" \x03(\tR\x06labels\x120\n" +
+	"\vissue_links\x18\v \x03(\v2\x0f.jira.IssueLinkR\n" +
+	"issueLinks\x12$\n" +
+	"\x06parent\x18\f \x01(\v2\f.jira.ParentR\x06parent\x12\x1e\n" +
+	"\x04epic\x18\r \x01(\v2\n" +
+	".jira.EpicR\x04epic\x12)\n" +
+	"\bsubtasks\x18\x0e \x03(\v2\r.jira.SubtaskR\bsubtasks\x120\n" +
+	"\ffix_versions\x18\x0f \x03(\v2\r.jira.VersionR\vfixVersions\x12)\n" +
+	"\bversions\x18\x10 \x03(\v2\r.jira.VersionR\bversions\x12/\n" +
+	"\n" +
+	"components\x18\x11 \x03(\v2\x0f.jira.ComponentR\n" +
+	"components\x125\n" +
+	"\bdue_date\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\x120\n" +
+	"\n" +
+	"resolution\x18\x13 \x01(\v2\x10.jira.ResolutionR\n" +
+	"resolution\x12C\n" +
+	"\x0fresolution_date\x18\x14 \x01(\v2\x1a.google.protobuf.TimestampR\x0eresolutionDate\x12$\n" +
+	"\x06sprint\x18\x15 \x01(\v2\f.jira.SprintR\x06sprint\x123\n" +
+	"\x0eclosed_sprints\x18\x16 \x03(\v2\f.jira.SprintR\rclosedSprints\x12\x12\n" +
+	"\x04rank\x18\x17 \x01(\tR\x04rank\x12)\n" +
+	"\bcomments\x18\x18 \x03(\v2\r.jira.CommentR\bcomments\x126\n" +
+	"\rcustom_fields\x18\x19 \x03(\v2\x11.jira.CustomFieldR\fcustomFields\"[\n" +
+	"\tIssueType\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x18\n" +
+	"\asubtask\x18\x03 \x01(\bR\asubtask\"[\n" +
+	"\x06Status\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12=\n" +
+	"\x0fstatus_category\x18\x02 \x01(\v2\x14.jira.StatusCategoryR\x0estatusCategory\"6\n" +
+	"\x0eStatusCategory\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"R\n" +
+	"\n" +
+	"Resolution\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\".\n" +
+	"\bPriority\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"m\n" +
+	"\x04User\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12#\n" +
+	"\remail_address\x18\x03 \x01(\tR\femailAddress\"\xb2\x01\n" +
+	"\tIssueLink\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x04type\x18\x02 \x01(\v2\x13.jira.IssueLinkTypeR\x04type\x124\n" +
+	"\finward_issue\x18\x03 \x01(\v2\x11.jira.LinkedIssueR\vinwardIssue\x126\n" +
+	"\routward_issue\x18\x04 \x01(\v2\x11.jira.LinkedIssueR\foutwardIssue\"U\n" +
+	"\rIssueLinkType\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06inward\x18\x02 \x01(\tR\x06inward\x12\x18\n" +
+	"\aoutward\x18\x03 \x01(\tR\aoutward\"o\n" +
+	"\vLinkedIssue\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n" +
+	"\x04self\x18\x03 \x01(\tR\x04self\x12*\n" +
+	"\x06fields\x18\x04 \x01(\v2\x12.jira.LinkedFieldsR\x06fields\"~\n" +
+	"\fLinkedFields\x12\x18\n" +
+	"\asummary\x18\x01 \x01(\tR\asummary\x12$\n" +
+	"\x06status\x18\x02 \x01(\v2\f.jira.StatusR\x06status\x12.\n" +
+	"\n" +
+	"issue_type\x18\x03 \x01(\v2\x0f.jira.IssueTypeR\tissueType\"j\n" +
+	"\x06Parent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n" +
 	"\x04self\x18\x03 \x01(\tR\x04self\x12*\n" +
 	"\x06fields\x18\x04 \x01(\v2\x12.jira.LinkedFieldsR\x06fields\"~\n" +
@@ -1136,7 +1896,54 @@ const file_jira_proto_rawDesc = "" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n" +
 	"\x04self\x18\x03 \x01(\tR\x04self\x12*\n" +
-	"\x06fields\x18\x04 \x01(\v2\x12.jira.LinkedFieldsR\x06fieldsB.Z,github.com/conallob/jira-beads-sync/gen/jirab\x06proto3"
+	"\x06fields\x18\x04 \x01(\v2\x12.jira.LinkedFieldsR\x06fields\"\x88\x01\n" +
+	"\aVersion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\breleased\x18\x03 \x01(\bR\breleased\x12\x1a\n" +
+	"\barchived\x18\x04 \x01(\bR\barchived\x12!\n" +
+	"\frelease_date\x18\x05 \x01(\tR\vreleaseDate\"/\n" +
+	"\tComponent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\xb1\x02\n" +
+	"\x06Sprint\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x12\n" +
+	"\x04goal\x18\x04 \x01(\tR\x04goal\x129\n" +
+	"\n" +
+	"start_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12?\n" +
+	"\rcomplete_date\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\fcompleteDate\x12&\n" +
+	"\x0forigin_board_id\x18\b \x01(\x03R\roriginBoardId\"\x87\x01\n" +
+	"\aComment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
+	"\x06author\x18\x02 \x01(\v2\n" +
+	".jira.UserR\x06author\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x124\n" +
+	"\acreated\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\"[\n" +
+	"\vCustomField\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06values\x18\x04 \x03(\tR\x06values\"\xbe\x01\n" +
+	"\n" +
+	"RemoteLink\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\"\n" +
+	"\frelationship\x18\x04 \x01(\tR\frelationship\x12)\n" +
+	"\x10application_type\x18\x05 \x01(\tR\x0fapplicationType\x12)\n" +
+	"\x10application_name\x18\x06 \x01(\tR\x0fapplicationName\"\xff\x01\n" +
+	"\vPullRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12'\n" +
+	"\x0frepository_name\x18\x05 \x01(\tR\x0erepositoryName\x12%\n" +
+	"\x0erepository_url\x18\x06 \x01(\tR\rrepositoryUrl\x12#\n" +
+	"\rsource_branch\x18\a \x01(\tR\fsourceBranch\x12-\n" +
+	"\x12destination_branch\x18\b \x01(\tR\x11destinationBranchB.Z,github.com/conallob/jira-beads-sync/gen/jirab\x06proto3"
 
 var (
 	file_jira_proto_rawDescOnce sync.Once
@@ -1150,7 +1957,7 @@ func file_jira_proto_rawDescGZIP() []byte {
 	return file_jira_proto_rawDescData
 }
 
-var file_jira_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_jira_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_jira_proto_goTypes = []any{
 	(*Export)(nil),                // 0: jira.Export
 	(*Issue)(nil),                 // 1: jira.Issue
@@ -1158,45 +1965,71 @@ var file_jira_proto_goTypes = []any{
 	(*IssueType)(nil),             // 3: jira.IssueType
 	(*Status)(nil),                // 4: jira.Status
 	(*StatusCategory)(nil),        // 5: jira.StatusCategory
-	(*Priority)(nil),              // 6: jira.Priority
-	(*User)(nil),                  // 7: jira.User
-	(*IssueLink)(nil),             // 8: jira.IssueLink
-	(*IssueLinkType)(nil),         // 9: jira.IssueLinkType
-	(*LinkedIssue)(nil),           // 10: jira.LinkedIssue
-	(*LinkedFields)(nil),          // 11: jira.LinkedFields
-	(*Parent)(nil),                // 12: jira.Parent
-	(*Epic)(nil),                  // 13: jira.Epic
-	(*Subtask)(nil),               // 14: jira.Subtask
-	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+	(*Resolution)(nil),            // 6: jira.Resolution
+	(*Priority)(nil),              // 7: jira.Priority
+	(*User)(nil),                  // 8: jira.User
+	(*IssueLink)(nil),             // 9: jira.IssueLink
+	(*IssueLinkType)(nil),         // 10: jira.IssueLinkType
+	(*LinkedIssue)(nil),           // 11: jira.LinkedIssue
+	(*LinkedFields)(nil),          // 12: jira.LinkedFields
+	(*Parent)(nil),                // 13: jira.Parent
+	(*Epic)(nil),                  // 14: jira.Epic
+	(*Subtask)(nil),               // 15: jira.Subtask
+	(*Version)(nil),               // 16: jira.Version
+	(*Component)(nil),             // 17: jira.Component
+	(*Sprint)(nil),                // 18: jira.Sprint
+	(*Comment)(nil),               // 19: jira.Comment
+	(*CustomField)(nil),           // 20: jira.CustomField
+	(*RemoteLink)(nil),            // 21: jira.RemoteLink
+	(*PullRequest)(nil),           // 22: jira.PullRequest
+	(*timestamppb.Timestamp)(nil), // 23: google.protobuf.Timestamp
 }
 var file_jira_proto_depIdxs = []int32{
 	1,  // 0: jira.Export.issues:type_name -> jira.Issue
-	2,  // 1: jira.Issue.fields:type_name -> jira.Fields
-	3,  // 2: jira.Fields.issue_type:type_name -> jira.IssueType
-	4,  // 3: jira.Fields.status:type_name -> jira.Status
-	6,  // 4: jira.Fields.priority:type_name -> jira.Priority
-	7,  // 5: jira.Fields.assignee:type_name -> jira.User
-	7,  // 6: jira.Fields.reporter:type_name -> jira.User
-	15, // 7: jira.Fields.created:type_name -> google.protobuf.Timestamp
-	15, // 8: jira.Fields.updated:type_name -> google.protobuf.Timestamp
-	8,  // 9: jira.Fields.issue_links:type_name -> jira.IssueLink
-	12, // 10: jira.Fields.parent:type_name -> jira.Parent
-	13, // 11: jira.Fields.epic:type_name -> jira.Epic
-	14, // 12: jira.Fields.subtasks:type_name -> jira.Subtask
-	5,  // 13: jira.Status.status_category:type_name -> jira.StatusCategory
-	9,  // 14: jira.IssueLink.type:type_name -> jira.IssueLinkType
-	10, // 15: jira.IssueLink.inward_issue:type_name -> jira.LinkedIssue
-	10, // 16: jira.IssueLink.outward_issue:type_name -> jira.LinkedIssue
-	11, // 17: jira.LinkedIssue.fields:type_name -> jira.LinkedFields
-	4,  // 18: jira.LinkedFields.status:type_name -> jira.Status
-	3,  // 19: jira.LinkedFields.issue_type:type_name -> jira.IssueType
-	11, // 20: jira.Parent.fields:type_name -> jira.LinkedFields
-	11, // 21: jira.Subtask.fields:type_name -> jira.LinkedFields
-	22, // [22:22] is the sub-list for method output_type
-	22, // [22:22] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	18, // 1: jira.Export.sprint:type_name -> jira.Sprint
+	2,  // 2: jira.Issue.fields:type_name -> jira.Fields
+	21, // 3: jira.Issue.remote_links:type_name -> jira.RemoteLink
+	22, // 4: jira.Issue.pull_requests:type_name -> jira.PullRequest
+	3,  // 5: jira.Fields.issue_type:type_name -> jira.IssueType
+	4,  // 6: jira.Fields.status:type_name -> jira.Status
+	7,  // 7: jira.Fields.priority:type_name -> jira.Priority
+	8,  // 8: jira.Fields.assignee:type_name -> jira.User
+	8,  // 9: jira.Fields.reporter:type_name -> jira.User
+	23, // 10: jira.Fields.created:type_name -> google.protobuf.Timestamp
+	23, // 11: jira.Fields.updated:type_name -> google.protobuf.Timestamp
+	9,  // 12: jira.Fields.issue_links:type_name -> jira.IssueLink
+	13, // 13: jira.Fields.parent:type_name -> jira.Parent
+	14, // 14: jira.Fields.epic:type_name -> jira.Epic
+	15, // 15: jira.Fields.subtasks:type_name -> jira.Subtask
+	16, // 16: jira.Fields.fix_versions:type_name -> jira.Version
+	16, // 17: jira.Fields.versions:type_name -> jira.Version
+	17, // 18: jira.Fields.components:type_name -> jira.Component
+	23, // 19: jira.Fields.due_date:type_name -> google.protobuf.Timestamp
+	6,  // 20: jira.Fields.resolution:type_name -> jira.Resolution
+	23, // 21: jira.Fields.resolution_date:type_name -> google.protobuf.Timestamp
+	18, // 22: jira.Fields.sprint:type_name -> jira.Sprint
+	18, // 23: jira.Fields.closed_sprints:type_name -> jira.Sprint
+	19, // 24: jira.Fields.comments:type_name -> jira.Comment
+	20, // 25: jira.Fields.custom_fields:type_name -> jira.CustomField
+	5,  // 26: jira.Status.status_category:type_name -> jira.StatusCategory
+	10, // 27: jira.IssueLink.type:type_name -> jira.IssueLinkType
+	11, // 28: jira.IssueLink.inward_issue:type_name -> jira.LinkedIssue
+	11, // 29: jira.IssueLink.outward_issue:type_name -> jira.LinkedIssue
+	12, // 30: jira.LinkedIssue.fields:type_name -> jira.LinkedFields
+	4,  // 31: jira.LinkedFields.status:type_name -> jira.Status
+	3,  // 32: jira.LinkedFields.issue_type:type_name -> jira.IssueType
+	12, // 33: jira.Parent.fields:type_name -> jira.LinkedFields
+	12, // 34: jira.Subtask.fields:type_name -> jira.LinkedFields
+	23, // 35: jira.Sprint.start_date:type_name -> google.protobuf.Timestamp
+	23, // 36: jira.Sprint.end_date:type_name -> google.protobuf.Timestamp
+	23, // 37: jira.Sprint.complete_date:type_name -> google.protobuf.Timestamp
+	8,  // 38: jira.Comment.author:type_name -> jira.User
+	23, // 39: jira.Comment.created:type_name -> google.protobuf.Timestamp
+	40, // [40:40] is the sub-list for method output_type
+	40, // [40:40] is the sub-list for method input_type
+	40, // [40:40] is the sub-list for extension type_name
+	40, // [40:40] is the sub-list for extension extendee
+	0,  // [0:40] is the sub-list for field type_name
 }
 
 func init() { file_jira_proto_init() }
@@ -1210,7 +2043,7 @@ func file_jira_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_jira_proto_rawDesc), len(file_jira_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   15,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
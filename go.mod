@@ -4,4 +4,11 @@ go 1.24
 
 require gopkg.in/yaml.v3 v3.0.1
 
-require google.golang.org/protobuf v1.36.11
+require (
+	github.com/klauspost/compress v1.18.0
+	golang.org/x/crypto v0.36.0
+	golang.org/x/term v0.30.0
+	google.golang.org/protobuf v1.36.11
+)
+
+require golang.org/x/sys v0.31.0 // indirect
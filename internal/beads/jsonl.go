@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	pb "github.com/conallob/jira-beads-sync/gen/beads"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -15,6 +16,7 @@ import (
 // JSONLRenderer handles rendering protobuf beads to JSONL files
 type JSONLRenderer struct {
 	outputDir string
+	location  *time.Location // of rendered timestamps; UTC if nil
 }
 
 // NewJSONLRenderer creates a new JSONL renderer
@@ -24,6 +26,13 @@ func NewJSONLRenderer(outputDir string) *JSONLRenderer {
 	}
 }
 
+// SetLocation sets the zone timestamps are rendered in, e.g. to show them in
+// the team's local time. Dates such as due dates are never shifted. A nil
+// location renders timestamps in UTC.
+func (r *JSONLRenderer) SetLocation(location *time.Location) {
+	r.location = location
+}
+
 // RenderExport renders a beads export to JSONL files
 func (r *JSONLRenderer) RenderExport(export *pb.Export) error {
 	if err := r.ensureDirectory(); err != nil {
@@ -44,9 +53,95 @@ func (r *JSONLRenderer) RenderExport(export *pb.Export) error {
 		}
 	}
 
+	// Render the sprint summary when the export came from a sprint
+	if export.Sprint != nil {
+		if err := r.RenderSprintSummary(export.Sprint, export.Issues); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenderSprintSummary writes the summary of a sprint and the issues planned
+// in it to sprint.json. Issues planned in other sprints are left out.
+func (r *JSONLRenderer) RenderSprintSummary(sprint *pb.Sprint, issues []*pb.Issue) error {
+	if err := r.ensureDirectory(); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	sprintFile := filepath.Join(r.outputDir, ".beads", "sprint.json")
+	if err := r.renderSprintSummary(sprintFile, sprint, issues); err != nil {
+		return fmt.Errorf("failed to render sprint summary: %w", err)
+	}
+	return nil
+}
+
+// JSONLStream writes issues and epics to the JSONL files one at a time, as
+// they are converted
+type JSONLStream struct {
+	renderer   *JSONLRenderer
+	issuesFile *os.File
+	epicsFile  *os.File
+	issues     *json.Encoder
+	epics      *json.Encoder
+}
+
+// NewStream starts writing the issues and epics files. The epics file is only
+// created once the first epic is written, like RenderExport does.
+func (r *JSONLRenderer) NewStream() (*JSONLStream, error) {
+	if err := r.ensureDirectory(); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.Create(filepath.Join(r.outputDir, ".beads", "issues.jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render issues: %w", err)
+	}
+
+	return &JSONLStream{
+		renderer:   r,
+		issuesFile: file,
+		issues:     json.NewEncoder(file),
+	}, nil
+}
+
+// WriteIssue appends an issue to the issues file
+func (s *JSONLStream) WriteIssue(issue *pb.Issue) error {
+	if err := s.issues.Encode(s.renderer.issueToJSON(issue)); err != nil {
+		return fmt.Errorf("failed to encode issue %s: %w", issue.Id, err)
+	}
+	return nil
+}
+
+// WriteEpic appends an epic to the epics file
+func (s *JSONLStream) WriteEpic(epic *pb.Epic) error {
+	if s.epics == nil {
+		file, err := os.Create(filepath.Join(s.renderer.outputDir, ".beads", "epics.jsonl"))
+		if err != nil {
+			return fmt.Errorf("failed to render epics: %w", err)
+		}
+		s.epicsFile = file
+		s.epics = json.NewEncoder(file)
+	}
+
+	if err := s.epics.Encode(s.renderer.epicToJSON(epic)); err != nil {
+		return fmt.Errorf("failed to encode epic %s: %w", epic.Id, err)
+	}
 	return nil
 }
 
+// Close closes the files written by the stream
+func (s *JSONLStream) Close() error {
+	err := s.issuesFile.Close()
+	if s.epicsFile != nil {
+		if cerr := s.epicsFile.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
 // ensureDirectory creates the necessary beads directory
 func (r *JSONLRenderer) ensureDirectory() error {
 	beadsDir := filepath.Join(r.outputDir, ".beads")
@@ -99,45 +194,114 @@ func (r *JSONLRenderer) renderEpicsToJSONL(filename string, epics []*pb.Epic) (e
 	return nil
 }
 
+// renderSprintSummary writes a JSON summary of a sprint and the issues planned in it
+func (r *JSONLRenderer) renderSprintSummary(filename string, sprint *pb.Sprint, issues []*pb.Issue) (err error) {
+	summary := &SprintSummary{
+		BeadsSprint:  *r.sprintToJSON(sprint),
+		Issues:       []string{},
+		StatusCounts: make(map[string]int),
+	}
+	for _, issue := range issues {
+		if issue.Sprint == nil || issue.Sprint.Id != sprint.Id {
+			continue
+		}
+		summary.Issues = append(summary.Issues, issue.Id)
+		summary.StatusCounts[r.statusToString(issue.Status)]++
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(summary)
+}
+
 // BeadsIssue represents a beads issue in JSON format
 type BeadsIssue struct {
-	ID          string            `json:"id"`
-	Title       string            `json:"title"`
-	Description string            `json:"description,omitempty"`
-	Status      string            `json:"status"`
-	Priority    int               `json:"priority,omitempty"`
-	Epic        string            `json:"epic,omitempty"`
-	Assignee    string            `json:"assignee,omitempty"`
-	Labels      []string          `json:"labels,omitempty"`
-	DependsOn   []string          `json:"dependsOn,omitempty"`
-	Created     string            `json:"created,omitempty"`
-	Updated     string            `json:"updated,omitempty"`
-	Metadata    map[string]string `json:"metadata,omitempty"`
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	Description     string         `json:"description,omitempty"`
+	Status          string         `json:"status"`
+	Priority        int            `json:"priority,omitempty"`
+	Epic            string         `json:"epic,omitempty"`
+	Assignee        string         `json:"assignee,omitempty"`
+	Labels          []string       `json:"labels,omitempty"`
+	DependsOn       []string       `json:"dependsOn,omitempty"`
+	FixVersions     []string       `json:"fixVersions,omitempty"`
+	AffectsVersions []string       `json:"affectsVersions,omitempty"`
+	Components      []string       `json:"components,omitempty"`
+	Due             string         `json:"due,omitempty"`
+	Resolution      string         `json:"resolution,omitempty"`
+	Created         string         `json:"created,omitempty"`
+	Updated         string         `json:"updated,omitempty"`
+	Resolved        string         `json:"resolved,omitempty"`
+	Sprint          *BeadsSprint   `json:"sprint,omitempty"`
+	Rank            string         `json:"rank,omitempty"`
+	Metadata        *BeadsMetadata `json:"metadata,omitempty"`
+}
+
+// BeadsSprint represents the sprint an issue is planned in, in JSON format
+type BeadsSprint struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	State     string `json:"state,omitempty"`
+	Goal      string `json:"goal,omitempty"`
+	Start     string `json:"start,omitempty"`
+	End       string `json:"end,omitempty"`
+	Completed string `json:"completed,omitempty"`
+	BoardID   int64  `json:"boardId,omitempty"`
+}
+
+// SprintSummary represents the sprint summary written to .beads/sprint.json
+type SprintSummary struct {
+	BeadsSprint
+	Issues       []string       `json:"issues"`
+	StatusCounts map[string]int `json:"statusCounts"`
 }
 
 // BeadsEpic represents a beads epic in JSON format
 type BeadsEpic struct {
-	ID          string            `json:"id"`
-	Name        string            `json:"name"`
-	Description string            `json:"description,omitempty"`
-	Status      string            `json:"status"`
-	Created     string            `json:"created,omitempty"`
-	Updated     string            `json:"updated,omitempty"`
-	Metadata    map[string]string `json:"metadata,omitempty"`
+	ID              string         `json:"id"`
+	Name            string         `json:"name"`
+	Description     string         `json:"description,omitempty"`
+	Status          string         `json:"status"`
+	Labels          []string       `json:"labels,omitempty"`
+	FixVersions     []string       `json:"fixVersions,omitempty"`
+	AffectsVersions []string       `json:"affectsVersions,omitempty"`
+	Components      []string       `json:"components,omitempty"`
+	Due             string         `json:"due,omitempty"`
+	Resolution      string         `json:"resolution,omitempty"`
+	Created         string         `json:"created,omitempty"`
+	Updated         string         `json:"updated,omitempty"`
+	Resolved        string         `json:"resolved,omitempty"`
+	Metadata        *BeadsMetadata `json:"metadata,omitempty"`
 }
 
 // issueToJSON converts a protobuf issue to JSON format
 func (r *JSONLRenderer) issueToJSON(issue *pb.Issue) *BeadsIssue {
 	jsonIssue := &BeadsIssue{
-		ID:          issue.Id,
-		Title:       issue.Title,
-		Description: issue.Description,
-		Status:      r.statusToString(issue.Status),
-		Priority:    r.priorityToInt(issue.Priority),
-		Epic:        issue.Epic,
-		Assignee:    issue.Assignee,
-		Labels:      issue.Labels,
-		DependsOn:   issue.DependsOn,
+		ID:              issue.Id,
+		Title:           issue.Title,
+		Description:     issue.Description,
+		Status:          r.statusToString(issue.Status),
+		Priority:        r.priorityToInt(issue.Priority),
+		Epic:            issue.Epic,
+		Assignee:        issue.Assignee,
+		Labels:          issue.Labels,
+		DependsOn:       issue.DependsOn,
+		FixVersions:     issue.FixVersions,
+		AffectsVersions: issue.AffectsVersions,
+		Components:      issue.Components,
+		Resolution:      issue.Resolution,
+		Rank:            issue.Rank,
 	}
 
 	if issue.Created != nil {
@@ -146,21 +310,18 @@ func (r *JSONLRenderer) issueToJSON(issue *pb.Issue) *BeadsIssue {
 	if issue.Updated != nil {
 		jsonIssue.Updated = r.timestampToString(issue.Updated)
 	}
+	if issue.Due != nil {
+		jsonIssue.Due = r.dateToString(issue.Due)
+	}
+	if issue.Resolved != nil {
+		jsonIssue.Resolved = r.timestampToString(issue.Resolved)
+	}
+	if issue.Sprint != nil {
+		jsonIssue.Sprint = r.sprintToJSON(issue.Sprint)
+	}
 
 	if issue.Metadata != nil {
-		jsonIssue.Metadata = make(map[string]string)
-		if issue.Metadata.JiraKey != "" {
-			jsonIssue.Metadata["jiraKey"] = issue.Metadata.JiraKey
-		}
-		if issue.Metadata.JiraId != "" {
-			jsonIssue.Metadata["jiraId"] = issue.Metadata.JiraId
-		}
-		if issue.Metadata.JiraIssueType != "" {
-			jsonIssue.Metadata["jiraIssueType"] = issue.Metadata.JiraIssueType
-		}
-		for k, v := range issue.Metadata.Custom {
-			jsonIssue.Metadata[k] = v
-		}
+		jsonIssue.Metadata = r.metadataToJSON(issue.Metadata)
 	}
 
 	return jsonIssue
@@ -169,10 +330,15 @@ func (r *JSONLRenderer) issueToJSON(issue *pb.Issue) *BeadsIssue {
 // epicToJSON converts a protobuf epic to JSON format
 func (r *JSONLRenderer) epicToJSON(epic *pb.Epic) *BeadsEpic {
 	jsonEpic := &BeadsEpic{
-		ID:          epic.Id,
-		Name:        epic.Name,
-		Description: epic.Description,
-		Status:      r.statusToString(epic.Status),
+		ID:              epic.Id,
+		Name:            epic.Name,
+		Description:     epic.Description,
+		Status:          r.statusToString(epic.Status),
+		Labels:          epic.Labels,
+		FixVersions:     epic.FixVersions,
+		AffectsVersions: epic.AffectsVersions,
+		Components:      epic.Components,
+		Resolution:      epic.Resolution,
 	}
 
 	if epic.Created != nil {
@@ -181,23 +347,59 @@ func (r *JSONLRenderer) epicToJSON(epic *pb.Epic) *BeadsEpic {
 	if epic.Updated != nil {
 		jsonEpic.Updated = r.timestampToString(epic.Updated)
 	}
+	if epic.Due != nil {
+		jsonEpic.Due = r.dateToString(epic.Due)
+	}
+	if epic.Resolved != nil {
+		jsonEpic.Resolved = r.timestampToString(epic.Resolved)
+	}
 
 	if epic.Metadata != nil {
-		jsonEpic.Metadata = make(map[string]string)
-		if epic.Metadata.JiraKey != "" {
-			jsonEpic.Metadata["jiraKey"] = epic.Metadata.JiraKey
-		}
-		if epic.Metadata.JiraId != "" {
-			jsonEpic.Metadata["jiraId"] = epic.Metadata.JiraId
-		}
-		if epic.Metadata.JiraIssueType != "" {
-			jsonEpic.Metadata["jiraIssueType"] = epic.Metadata.JiraIssueType
-		}
+		jsonEpic.Metadata = r.metadataToJSON(epic.Metadata)
 	}
 
 	return jsonEpic
 }
 
+// metadataToJSON converts protobuf metadata to JSON format
+func (r *JSONLRenderer) metadataToJSON(metadata *pb.Metadata) *BeadsMetadata {
+	jsonMetadata := &BeadsMetadata{
+		JiraKey:       metadata.JiraKey,
+		JiraID:        metadata.JiraId,
+		JiraIssueType: metadata.JiraIssueType,
+		Repositories:  metadata.Repositories,
+	}
+	if len(metadata.Custom) > 0 {
+		jsonMetadata.Custom = make(map[string]string, len(metadata.Custom))
+		for k, v := range metadata.Custom {
+			jsonMetadata.Custom[k] = v
+		}
+	}
+	for _, pr := range metadata.PullRequests {
+		jsonMetadata.PullRequests = append(jsonMetadata.PullRequests, BeadsPullRequest{
+			URL:        pr.Url,
+			Title:      pr.Title,
+			State:      pr.State,
+			Repository: pr.Repository,
+		})
+	}
+	return jsonMetadata
+}
+
+// sprintToJSON converts a protobuf sprint to JSON format
+func (r *JSONLRenderer) sprintToJSON(sprint *pb.Sprint) *BeadsSprint {
+	return &BeadsSprint{
+		ID:        sprint.Id,
+		Name:      sprint.Name,
+		State:     sprint.State,
+		Goal:      sprint.Goal,
+		Start:     r.timestampToString(sprint.Start),
+		End:       r.timestampToString(sprint.End),
+		Completed: r.timestampToString(sprint.Completed),
+		BoardID:   sprint.BoardId,
+	}
+}
+
 // statusToString converts status enum to string
 func (r *JSONLRenderer) statusToString(status pb.Status) string {
 	switch status {
@@ -232,76 +434,161 @@ func (r *JSONLRenderer) priorityToInt(priority pb.Priority) int {
 	}
 }
 
-// timestampToString converts protobuf timestamp to RFC3339 string
+// timestampToString converts protobuf timestamp to RFC3339 string in the
+// renderer's location
 func (r *JSONLRenderer) timestampToString(ts *timestamppb.Timestamp) string {
 	if ts == nil {
 		return ""
 	}
-	return ts.AsTime().Format("2006-01-02T15:04:05Z07:00")
+	t := ts.AsTime()
+	if r.location != nil {
+		t = t.In(r.location)
+	}
+	return t.Format("2006-01-02T15:04:05Z07:00")
 }
 
-// AddRepositoryAnnotation adds a repository to an issue's metadata in the JSONL file
-func (r *JSONLRenderer) AddRepositoryAnnotation(issueID, repository string) (err error) {
-	issuesFile := filepath.Join(r.outputDir, ".beads", "issues.jsonl")
+// dateToString converts protobuf timestamp to a plain date string (YYYY-MM-DD)
+func (r *JSONLRenderer) dateToString(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.AsTime().Format("2006-01-02")
+}
 
-	// Read all issues
-	file, err := os.Open(issuesFile)
-	if err != nil {
-		return fmt.Errorf("failed to open issues file: %w", err)
+// AddRepositoryAnnotation adds a repository to the metadata of an issue or epic
+func (r *JSONLRenderer) AddRepositoryAnnotation(id, repository string) error {
+	return r.updateMetadata(id, func(metadata *BeadsMetadata) error {
+		if metadata.hasRepository(repository) {
+			return fmt.Errorf("repository '%s' is already associated with %s", repository, id)
+		}
+		metadata.Repositories = append(metadata.Repositories, repository)
+		return nil
+	})
+}
+
+// RemoveRepositoryAnnotation removes a repository from the metadata of an issue or epic
+func (r *JSONLRenderer) RemoveRepositoryAnnotation(id, repository string) error {
+	return r.updateMetadata(id, func(metadata *BeadsMetadata) error {
+		if !metadata.hasRepository(repository) {
+			return fmt.Errorf("repository '%s' is not associated with %s", repository, id)
+		}
+		repos := make([]string, 0, len(metadata.Repositories)-1)
+		for _, repo := range metadata.Repositories {
+			if repo != repository {
+				repos = append(repos, repo)
+			}
+		}
+		metadata.Repositories = repos
+		return nil
+	})
+}
+
+// MigrateMetadata rewrites issues.jsonl and epics.jsonl so that repositories
+// stored as comma-separated strings become JSON arrays
+func (r *JSONLRenderer) MigrateMetadata() error {
+	beadsDir := filepath.Join(r.outputDir, ".beads")
+
+	if _, err := rewriteJSONL(filepath.Join(beadsDir, "issues.jsonl"), "", nil, newIssueRecord); err != nil {
+		return err
 	}
-	defer func() {
-		if cerr := file.Close(); cerr != nil && err == nil {
-			err = cerr
+	epicsFile := filepath.Join(beadsDir, "epics.jsonl")
+	if _, err := os.Stat(epicsFile); os.IsNotExist(err) {
+		return nil
+	}
+	if _, err := rewriteJSONL(epicsFile, "", nil, newEpicRecord); err != nil {
+		return err
+	}
+	return nil
+}
+
+// updateMetadata applies update to the metadata of the issue or epic with the
+// given ID, looking in issues.jsonl first and then epics.jsonl
+func (r *JSONLRenderer) updateMetadata(id string, update func(*BeadsMetadata) error) error {
+	beadsDir := filepath.Join(r.outputDir, ".beads")
+
+	found, err := rewriteJSONL(filepath.Join(beadsDir, "issues.jsonl"), id, update, newIssueRecord)
+	if err != nil || found {
+		return err
+	}
+
+	epicsFile := filepath.Join(beadsDir, "epics.jsonl")
+	if _, err := os.Stat(epicsFile); err == nil {
+		found, err = rewriteJSONL(epicsFile, id, update, newEpicRecord)
+		if err != nil || found {
+			return err
 		}
-	}()
+	}
+
+	return fmt.Errorf("issue or epic %s not found", id)
+}
+
+// jsonlRecord is a line of issues.jsonl or epics.jsonl whose metadata can be updated
+type jsonlRecord interface {
+	recordID() string
+	metadata() **BeadsMetadata
+}
+
+func (i *BeadsIssue) recordID() string          { return i.ID }
+func (i *BeadsIssue) metadata() **BeadsMetadata { return &i.Metadata }
+func (e *BeadsEpic) recordID() string           { return e.ID }
+func (e *BeadsEpic) metadata() **BeadsMetadata  { return &e.Metadata }
+
+func newIssueRecord() jsonlRecord { return &BeadsIssue{} }
+func newEpicRecord() jsonlRecord  { return &BeadsEpic{} }
+
+// rewriteJSONL reads every record of a JSONL file, applies update to the
+// metadata of the record with the given ID and writes all records back.
+// The file is not rewritten if update fails or the ID is not found, unless
+// update is nil, in which case every record is rewritten as-is.
+func rewriteJSONL(filename, id string, update func(*BeadsMetadata) error, newRecord func() jsonlRecord) (found bool, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return false, fmt.Errorf("failed to open %s: %w", filepath.Base(filename), err)
+	}
 
-	var issues []*BeadsIssue
+	var records []jsonlRecord
 	scanner := bufio.NewScanner(file)
-	found := false
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
 	for scanner.Scan() {
-		var issue BeadsIssue
-		if err := json.Unmarshal(scanner.Bytes(), &issue); err != nil {
-			return fmt.Errorf("failed to parse issue: %w", err)
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		record := newRecord()
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			_ = file.Close()
+			return false, fmt.Errorf("failed to parse %s: %w", filepath.Base(filename), err)
 		}
 
-		// If this is the target issue, add the repository
-		if issue.ID == issueID {
+		if update != nil && record.recordID() == id {
 			found = true
-			if issue.Metadata == nil {
-				issue.Metadata = make(map[string]string)
+			metadata := record.metadata()
+			if *metadata == nil {
+				*metadata = &BeadsMetadata{}
 			}
-
-			// Check for duplicate (storing as comma-separated in metadata)
-			reposKey := "repositories"
-			existingRepos := issue.Metadata[reposKey]
-			if existingRepos != "" {
-				repos := strings.Split(existingRepos, ",")
-				for _, r := range repos {
-					if strings.TrimSpace(r) == repository {
-						return fmt.Errorf("repository '%s' is already associated with issue %s", repository, issueID)
-					}
-				}
-				issue.Metadata[reposKey] = existingRepos + "," + repository
-			} else {
-				issue.Metadata[reposKey] = repository
+			if err := update(*metadata); err != nil {
+				_ = file.Close()
+				return true, err
 			}
 		}
 
-		issues = append(issues, &issue)
+		records = append(records, record)
 	}
-
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading issues file: %w", err)
+		_ = file.Close()
+		return false, fmt.Errorf("error reading %s: %w", filepath.Base(filename), err)
+	}
+	if err := file.Close(); err != nil {
+		return false, err
 	}
 
-	if !found {
-		return fmt.Errorf("issue %s not found in issues file", issueID)
+	if update != nil && !found {
+		return false, nil
 	}
 
-	// Write all issues back to the file
-	outFile, err := os.Create(issuesFile)
+	// Write all records back to the file
+	outFile, err := os.Create(filename)
 	if err != nil {
-		return fmt.Errorf("failed to create issues file: %w", err)
+		return found, fmt.Errorf("failed to create %s: %w", filepath.Base(filename), err)
 	}
 	defer func() {
 		if cerr := outFile.Close(); cerr != nil && err == nil {
@@ -310,11 +597,11 @@ func (r *JSONLRenderer) AddRepositoryAnnotation(issueID, repository string) (err
 	}()
 
 	encoder := json.NewEncoder(outFile)
-	for _, issue := range issues {
-		if err := encoder.Encode(issue); err != nil {
-			return fmt.Errorf("failed to write issue: %w", err)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return found, fmt.Errorf("failed to write %s: %w", record.recordID(), err)
 		}
 	}
 
-	return nil
+	return found, nil
 }
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	pb "github.com/conallob/jira-beads-sync/gen/beads"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -177,8 +178,261 @@ func TestIssueToJSON(t *testing.T) {
 	if jsonIssue.Metadata == nil {
 		t.Fatal("Metadata is nil")
 	}
-	if jsonIssue.Metadata["jiraKey"] != "PROJ-123" {
-		t.Errorf("Expected jiraKey 'PROJ-123', got '%s'", jsonIssue.Metadata["jiraKey"])
+	if jsonIssue.Metadata.JiraKey != "PROJ-123" {
+		t.Errorf("Expected jiraKey 'PROJ-123', got '%s'", jsonIssue.Metadata.JiraKey)
+	}
+}
+
+func TestIssueToJSONVersionsAndComponents(t *testing.T) {
+	renderer := NewJSONLRenderer("/tmp/test")
+
+	issue := &pb.Issue{
+		Id:              "test-124",
+		Title:           "Versioned Issue",
+		Status:          pb.Status_STATUS_OPEN,
+		FixVersions:     []string{"2.4"},
+		AffectsVersions: []string{"2.3"},
+		Components:      []string{"api", "web"},
+	}
+
+	data, err := json.Marshal(renderer.issueToJSON(issue))
+	if err != nil {
+		t.Fatalf("Failed to marshal issue: %v", err)
+	}
+
+	for _, want := range []string{`"fixVersions":["2.4"]`, `"affectsVersions":["2.3"]`, `"components":["api","web"]`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected JSON to contain %s, got %s", want, data)
+		}
+	}
+
+	// Empty fields are omitted
+	data, err = json.Marshal(renderer.issueToJSON(&pb.Issue{Id: "test-125", Title: "Plain"}))
+	if err != nil {
+		t.Fatalf("Failed to marshal issue: %v", err)
+	}
+	if strings.Contains(string(data), "fixVersions") || strings.Contains(string(data), "components") {
+		t.Errorf("Expected empty version and component fields to be omitted, got %s", data)
+	}
+
+	// Epics carry the same fields
+	epic := &pb.Epic{
+		Id:              "test-126",
+		Name:            "Versioned Epic",
+		Labels:          []string{"roadmap"},
+		FixVersions:     []string{"2.4"},
+		AffectsVersions: []string{"2.3"},
+		Components:      []string{"api"},
+	}
+	data, err = json.Marshal(renderer.epicToJSON(epic))
+	if err != nil {
+		t.Fatalf("Failed to marshal epic: %v", err)
+	}
+	for _, want := range []string{`"labels":["roadmap"]`, `"fixVersions":["2.4"]`, `"affectsVersions":["2.3"]`, `"components":["api"]`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected epic JSON to contain %s, got %s", want, data)
+		}
+	}
+}
+
+func TestIssueToJSONDevelopment(t *testing.T) {
+	renderer := NewJSONLRenderer("/tmp/test")
+
+	issue := &pb.Issue{
+		Id:    "test-129",
+		Title: "With pull requests",
+		Metadata: &pb.Metadata{
+			JiraKey:      "TEST-129",
+			Repositories: []string{"https://github.com/acme/api", "https://github.com/acme/web"},
+			PullRequests: []*pb.PullRequest{
+				{Url: "https://github.com/acme/api/pull/12", State: "merged"},
+				{Url: "https://github.com/acme/web/pull/3", State: "open"},
+			},
+		},
+	}
+
+	data, err := json.Marshal(renderer.issueToJSON(issue))
+	if err != nil {
+		t.Fatalf("Failed to marshal issue: %v", err)
+	}
+	expectedRepos := `"repositories":["https://github.com/acme/api","https://github.com/acme/web"]`
+	if !strings.Contains(string(data), expectedRepos) {
+		t.Errorf("Expected JSON to contain %s, got %s", expectedRepos, data)
+	}
+	expectedPRs := `"pullRequests":[{"url":"https://github.com/acme/api/pull/12","state":"merged"},{"url":"https://github.com/acme/web/pull/3","state":"open"}]`
+	if !strings.Contains(string(data), expectedPRs) {
+		t.Errorf("Expected JSON to contain %s, got %s", expectedPRs, data)
+	}
+
+	data, err = json.Marshal(renderer.epicToJSON(&pb.Epic{Id: "test-130", Name: "Epic", Metadata: issue.Metadata}))
+	if err != nil {
+		t.Fatalf("Failed to marshal epic: %v", err)
+	}
+	if !strings.Contains(string(data), expectedRepos) {
+		t.Errorf("Expected epic JSON to contain %s, got %s", expectedRepos, data)
+	}
+}
+
+func TestIssueToJSONRank(t *testing.T) {
+	renderer := NewJSONLRenderer("/tmp/test")
+
+	data, err := json.Marshal(renderer.issueToJSON(&pb.Issue{Id: "test-127", Title: "Ranked", Rank: "0|i0000f:"}))
+	if err != nil {
+		t.Fatalf("Failed to marshal issue: %v", err)
+	}
+	if !strings.Contains(string(data), `"rank":"0|i0000f:"`) {
+		t.Errorf("Expected JSON to contain rank, got %s", data)
+	}
+
+	data, err = json.Marshal(renderer.issueToJSON(&pb.Issue{Id: "test-128", Title: "Unranked"}))
+	if err != nil {
+		t.Fatalf("Failed to marshal issue: %v", err)
+	}
+	if strings.Contains(string(data), `"rank"`) {
+		t.Errorf("Expected empty rank to be omitted, got %s", data)
+	}
+}
+
+func TestIssueToJSONDueDateAndResolution(t *testing.T) {
+	renderer := NewJSONLRenderer("/tmp/test")
+
+	issue := &pb.Issue{
+		Id:         "test-126",
+		Title:      "Abandoned",
+		Status:     pb.Status_STATUS_CLOSED,
+		Due:        timestamppb.New(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+		Resolution: "wontfix",
+		Resolved:   timestamppb.New(time.Date(2024, 2, 20, 9, 15, 0, 0, time.UTC)),
+	}
+
+	jsonIssue := renderer.issueToJSON(issue)
+
+	if jsonIssue.Due != "2024-03-01" {
+		t.Errorf("Expected due '2024-03-01', got '%s'", jsonIssue.Due)
+	}
+	if jsonIssue.Resolution != "wontfix" {
+		t.Errorf("Expected resolution 'wontfix', got '%s'", jsonIssue.Resolution)
+	}
+	if jsonIssue.Resolved != "2024-02-20T09:15:00Z" {
+		t.Errorf("Expected resolved '2024-02-20T09:15:00Z', got '%s'", jsonIssue.Resolved)
+	}
+
+	epic := &pb.Epic{
+		Id:         "epic-2",
+		Name:       "Epic",
+		Status:     pb.Status_STATUS_CLOSED,
+		Due:        issue.Due,
+		Resolution: "fixed",
+		Resolved:   issue.Resolved,
+	}
+
+	jsonEpic := renderer.epicToJSON(epic)
+
+	if jsonEpic.Due != "2024-03-01" || jsonEpic.Resolution != "fixed" || jsonEpic.Resolved != "2024-02-20T09:15:00Z" {
+		t.Errorf("Unexpected epic due/resolution fields: %+v", jsonEpic)
+	}
+}
+
+func TestIssueToJSONLocation(t *testing.T) {
+	renderer := NewJSONLRenderer("/tmp/test")
+	renderer.SetLocation(time.FixedZone("AEST", 10*60*60))
+
+	issue := &pb.Issue{
+		Id:      "test-127",
+		Title:   "Late night",
+		Status:  pb.Status_STATUS_OPEN,
+		Created: timestamppb.New(time.Date(2024, 2, 29, 20, 30, 0, 0, time.UTC)),
+		Due:     timestamppb.New(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	jsonIssue := renderer.issueToJSON(issue)
+
+	if jsonIssue.Created != "2024-03-01T06:30:00+10:00" {
+		t.Errorf("Expected created in +10:00, got '%s'", jsonIssue.Created)
+	}
+	// Dates are the same everywhere
+	if jsonIssue.Due != "2024-03-01" {
+		t.Errorf("Expected due '2024-03-01', got '%s'", jsonIssue.Due)
+	}
+}
+
+func TestRenderExportSprintSummary(t *testing.T) {
+	tmpDir := t.TempDir()
+	renderer := NewJSONLRenderer(tmpDir)
+
+	sprint := &pb.Sprint{
+		Id:      42,
+		Name:    "Sprint 42",
+		State:   "active",
+		Goal:    "Ship the login flow",
+		Start:   timestamppb.New(time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)),
+		End:     timestamppb.New(time.Date(2024, 1, 22, 17, 0, 0, 0, time.UTC)),
+		BoardId: 7,
+	}
+
+	export := &pb.Export{
+		Sprint: sprint,
+		Issues: []*pb.Issue{
+			{Id: "proj-1", Title: "Planned", Status: pb.Status_STATUS_OPEN, Sprint: sprint},
+			{Id: "proj-2", Title: "Also planned", Status: pb.Status_STATUS_CLOSED, Sprint: sprint},
+			{Id: "proj-3", Title: "Dependency", Status: pb.Status_STATUS_OPEN},
+		},
+	}
+
+	if err := renderer.RenderExport(export); err != nil {
+		t.Fatalf("RenderExport failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".beads", "sprint.json"))
+	if err != nil {
+		t.Fatalf("Failed to read sprint.json: %v", err)
+	}
+
+	var summary SprintSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("Failed to parse sprint.json: %v", err)
+	}
+
+	if summary.ID != 42 || summary.Name != "Sprint 42" || summary.Goal != "Ship the login flow" {
+		t.Errorf("Unexpected sprint summary: %+v", summary)
+	}
+	if summary.Start != "2024-01-08T09:00:00Z" || summary.End != "2024-01-22T17:00:00Z" {
+		t.Errorf("Unexpected sprint dates: start=%s end=%s", summary.Start, summary.End)
+	}
+	if len(summary.Issues) != 2 || summary.Issues[0] != "proj-1" || summary.Issues[1] != "proj-2" {
+		t.Errorf("Expected sprint issues [proj-1 proj-2], got %v", summary.Issues)
+	}
+	if summary.StatusCounts["open"] != 1 || summary.StatusCounts["closed"] != 1 {
+		t.Errorf("Unexpected status counts: %v", summary.StatusCounts)
+	}
+
+	// Issues carry their sprint in issues.jsonl
+	issuesData, err := os.ReadFile(filepath.Join(tmpDir, ".beads", "issues.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read issues.jsonl: %v", err)
+	}
+	var first BeadsIssue
+	if err := json.Unmarshal([]byte(strings.Split(string(issuesData), "\n")[0]), &first); err != nil {
+		t.Fatalf("Failed to parse issue: %v", err)
+	}
+	if first.Sprint == nil || first.Sprint.Name != "Sprint 42" || first.Sprint.BoardID != 7 {
+		t.Errorf("Expected first issue to carry Sprint 42, got %+v", first.Sprint)
+	}
+}
+
+func TestRenderExportWithoutSprint(t *testing.T) {
+	tmpDir := t.TempDir()
+	renderer := NewJSONLRenderer(tmpDir)
+
+	export := &pb.Export{
+		Issues: []*pb.Issue{{Id: "proj-1", Title: "Issue", Status: pb.Status_STATUS_OPEN}},
+	}
+	if err := renderer.RenderExport(export); err != nil {
+		t.Fatalf("RenderExport failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, ".beads", "sprint.json")); !os.IsNotExist(err) {
+		t.Error("Expected no sprint.json without a sprint")
 	}
 }
 
@@ -332,14 +586,14 @@ func TestEpicToJSONWithMetadata(t *testing.T) {
 	if jsonEpic.Metadata == nil {
 		t.Fatal("Expected metadata to be non-nil")
 	}
-	if jsonEpic.Metadata["jiraKey"] != "PROJ-42" {
-		t.Errorf("Expected jiraKey 'PROJ-42', got '%s'", jsonEpic.Metadata["jiraKey"])
+	if jsonEpic.Metadata.JiraKey != "PROJ-42" {
+		t.Errorf("Expected jiraKey 'PROJ-42', got '%s'", jsonEpic.Metadata.JiraKey)
 	}
-	if jsonEpic.Metadata["jiraId"] != "10042" {
-		t.Errorf("Expected jiraId '10042', got '%s'", jsonEpic.Metadata["jiraId"])
+	if jsonEpic.Metadata.JiraID != "10042" {
+		t.Errorf("Expected jiraId '10042', got '%s'", jsonEpic.Metadata.JiraID)
 	}
-	if jsonEpic.Metadata["jiraIssueType"] != "Epic" {
-		t.Errorf("Expected jiraIssueType 'Epic', got '%s'", jsonEpic.Metadata["jiraIssueType"])
+	if jsonEpic.Metadata.JiraIssueType != "Epic" {
+		t.Errorf("Expected jiraIssueType 'Epic', got '%s'", jsonEpic.Metadata.JiraIssueType)
 	}
 }
 
@@ -379,7 +633,7 @@ func TestAddRepositoryAnnotation(t *testing.T) {
 	if err := json.Unmarshal([]byte(lines[0]), &annotated); err != nil {
 		t.Fatalf("Failed to parse first issue: %v", err)
 	}
-	if annotated.Metadata == nil || annotated.Metadata["repositories"] != "https://github.com/org/repo" {
+	if annotated.Metadata == nil || len(annotated.Metadata.Repositories) != 1 || annotated.Metadata.Repositories[0] != "https://github.com/org/repo" {
 		t.Errorf("Expected repository annotation, got metadata: %v", annotated.Metadata)
 	}
 
@@ -388,7 +642,7 @@ func TestAddRepositoryAnnotation(t *testing.T) {
 	if err := json.Unmarshal([]byte(lines[1]), &unannotated); err != nil {
 		t.Fatalf("Failed to parse second issue: %v", err)
 	}
-	if unannotated.Metadata != nil && unannotated.Metadata["repositories"] != "" {
+	if unannotated.Metadata != nil && len(unannotated.Metadata.Repositories) != 0 {
 		t.Errorf("Expected second issue to have no repository annotation, got: %v", unannotated.Metadata)
 	}
 }
@@ -449,9 +703,8 @@ func TestAddRepositoryAnnotationMultiple(t *testing.T) {
 		t.Fatalf("Failed to parse issue: %v", err)
 	}
 
-	repos := issue.Metadata["repositories"]
-	if repos != "https://github.com/org/repo1,https://github.com/org/repo2" {
-		t.Errorf("Expected both repos, got: %s", repos)
+	if !strings.Contains(string(data), `"repositories":["https://github.com/org/repo1","https://github.com/org/repo2"]`) {
+		t.Errorf("Expected both repos as a JSON array, got: %s", data)
 	}
 }
 
@@ -481,3 +734,188 @@ func TestAddRepositoryAnnotationFileNotFound(t *testing.T) {
 		t.Error("Expected error when issues file doesn't exist, got nil")
 	}
 }
+
+func TestRemoveRepositoryAnnotation(t *testing.T) {
+	tmpDir := t.TempDir()
+	renderer := NewJSONLRenderer(tmpDir)
+
+	export := &pb.Export{
+		Issues: []*pb.Issue{
+			{Id: "proj-500", Title: "Issue 500", Status: pb.Status_STATUS_OPEN},
+		},
+	}
+	if err := renderer.RenderExport(export); err != nil {
+		t.Fatalf("RenderExport failed: %v", err)
+	}
+
+	for _, repo := range []string{"https://github.com/org/repo1", "https://github.com/org/repo2"} {
+		if err := renderer.AddRepositoryAnnotation("proj-500", repo); err != nil {
+			t.Fatalf("AddRepositoryAnnotation failed: %v", err)
+		}
+	}
+
+	if err := renderer.RemoveRepositoryAnnotation("proj-500", "https://github.com/org/repo1"); err != nil {
+		t.Fatalf("RemoveRepositoryAnnotation failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".beads", "issues.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read issues.jsonl: %v", err)
+	}
+	if !strings.Contains(string(data), `"repositories":["https://github.com/org/repo2"]`) {
+		t.Errorf("Expected only repo2 to remain, got: %s", data)
+	}
+
+	// Removing a repository that isn't there is an error
+	if err := renderer.RemoveRepositoryAnnotation("proj-500", "https://github.com/org/repo1"); err == nil {
+		t.Error("Expected error removing unknown repository, got nil")
+	}
+}
+
+func TestAddRepositoryAnnotationEpic(t *testing.T) {
+	tmpDir := t.TempDir()
+	renderer := NewJSONLRenderer(tmpDir)
+
+	export := &pb.Export{
+		Issues: []*pb.Issue{
+			{Id: "proj-601", Title: "Issue 601", Status: pb.Status_STATUS_OPEN, Epic: "proj-600"},
+		},
+		Epics: []*pb.Epic{
+			{Id: "proj-600", Name: "Epic 600", Status: pb.Status_STATUS_OPEN, Metadata: &pb.Metadata{JiraKey: "PROJ-600"}},
+		},
+	}
+	if err := renderer.RenderExport(export); err != nil {
+		t.Fatalf("RenderExport failed: %v", err)
+	}
+
+	if err := renderer.AddRepositoryAnnotation("proj-600", "https://github.com/org/repo"); err != nil {
+		t.Fatalf("AddRepositoryAnnotation failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".beads", "epics.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read epics.jsonl: %v", err)
+	}
+
+	var epic BeadsEpic
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &epic); err != nil {
+		t.Fatalf("Failed to parse epic: %v", err)
+	}
+	if epic.Metadata.JiraKey != "PROJ-600" {
+		t.Errorf("Expected existing metadata to be kept, got %v", epic.Metadata)
+	}
+	if len(epic.Metadata.Repositories) != 1 || epic.Metadata.Repositories[0] != "https://github.com/org/repo" {
+		t.Errorf("Expected epic repository annotation, got %v", epic.Metadata.Repositories)
+	}
+}
+
+func TestMigrateMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatalf("Failed to create beads dir: %v", err)
+	}
+
+	legacyIssues := `{"id":"proj-1","title":"One","status":"open","metadata":{"jiraKey":"PROJ-1","repositories":"https://github.com/org/a,https://github.com/org/b"}}
+{"id":"proj-2","title":"Two","status":"open"}
+`
+	legacyEpics := `{"id":"proj-3","name":"Epic","status":"open","metadata":{"repositories":"https://github.com/org/c"}}
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), []byte(legacyIssues), 0644); err != nil {
+		t.Fatalf("Failed to write issues.jsonl: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "epics.jsonl"), []byte(legacyEpics), 0644); err != nil {
+		t.Fatalf("Failed to write epics.jsonl: %v", err)
+	}
+
+	if err := NewJSONLRenderer(tmpDir).MigrateMetadata(); err != nil {
+		t.Fatalf("MigrateMetadata failed: %v", err)
+	}
+
+	issues, err := os.ReadFile(filepath.Join(beadsDir, "issues.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read issues.jsonl: %v", err)
+	}
+	if !strings.Contains(string(issues), `"repositories":["https://github.com/org/a","https://github.com/org/b"]`) {
+		t.Errorf("Expected issue repositories to be migrated, got: %s", issues)
+	}
+	if !strings.Contains(string(issues), `"id":"proj-2"`) {
+		t.Errorf("Expected unannotated issue to be kept, got: %s", issues)
+	}
+
+	epics, err := os.ReadFile(filepath.Join(beadsDir, "epics.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read epics.jsonl: %v", err)
+	}
+	if !strings.Contains(string(epics), `"repositories":["https://github.com/org/c"]`) {
+		t.Errorf("Expected epic repositories to be migrated, got: %s", epics)
+	}
+}
+
+func TestJSONLStream(t *testing.T) {
+	// A stream writes the same files as RenderExport
+	export := &pb.Export{
+		Issues: []*pb.Issue{
+			{Id: "proj-2", Title: "First Issue", Status: pb.Status_STATUS_OPEN, Epic: "proj-1"},
+			{Id: "proj-3", Title: "Second Issue", Status: pb.Status_STATUS_CLOSED, DependsOn: []string{"proj-2"}},
+		},
+		Epics: []*pb.Epic{
+			{Id: "proj-1", Name: "Epic", Status: pb.Status_STATUS_IN_PROGRESS},
+		},
+	}
+
+	wantDir := t.TempDir()
+	if err := NewJSONLRenderer(wantDir).RenderExport(export); err != nil {
+		t.Fatalf("RenderExport failed: %v", err)
+	}
+
+	gotDir := t.TempDir()
+	stream, err := NewJSONLRenderer(gotDir).NewStream()
+	if err != nil {
+		t.Fatalf("NewStream failed: %v", err)
+	}
+	if err := stream.WriteIssue(export.Issues[0]); err != nil {
+		t.Fatalf("WriteIssue failed: %v", err)
+	}
+	if err := stream.WriteEpic(export.Epics[0]); err != nil {
+		t.Fatalf("WriteEpic failed: %v", err)
+	}
+	if err := stream.WriteIssue(export.Issues[1]); err != nil {
+		t.Fatalf("WriteIssue failed: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	for _, name := range []string{"issues.jsonl", "epics.jsonl"} {
+		want, err := os.ReadFile(filepath.Join(wantDir, ".beads", name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		got, err := os.ReadFile(filepath.Join(gotDir, ".beads", name))
+		if err != nil {
+			t.Fatalf("Failed to read streamed %s: %v", name, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("Streamed %s differs:\n%s\nwant:\n%s", name, got, want)
+		}
+	}
+}
+
+func TestJSONLStreamWithoutEpics(t *testing.T) {
+	tmpDir := t.TempDir()
+	stream, err := NewJSONLRenderer(tmpDir).NewStream()
+	if err != nil {
+		t.Fatalf("NewStream failed: %v", err)
+	}
+	if err := stream.WriteIssue(&pb.Issue{Id: "proj-1", Title: "Issue"}); err != nil {
+		t.Fatalf("WriteIssue failed: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, ".beads", "epics.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("Expected no epics.jsonl without epics, got: %v", err)
+	}
+}
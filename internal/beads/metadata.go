@@ -0,0 +1,123 @@
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BeadsMetadata represents the metadata of a beads issue or epic in JSON format.
+// Known keys are typed; any other string values are kept in Custom and
+// written back as top-level metadata keys.
+type BeadsMetadata struct {
+	JiraKey       string
+	JiraID        string
+	JiraIssueType string
+	Repositories  []string
+	PullRequests  []BeadsPullRequest
+	Custom        map[string]string
+}
+
+// BeadsPullRequest represents a pull request related to an issue, in JSON format
+type BeadsPullRequest struct {
+	URL        string `json:"url"`
+	Title      string `json:"title,omitempty"`
+	State      string `json:"state,omitempty"`
+	Repository string `json:"repository,omitempty"`
+}
+
+// MarshalJSON writes the metadata as a flat JSON object, with repositories
+// and pull requests as arrays
+func (m BeadsMetadata) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]interface{}, len(m.Custom)+5)
+	for k, v := range m.Custom {
+		fields[k] = v
+	}
+	if m.JiraKey != "" {
+		fields["jiraKey"] = m.JiraKey
+	}
+	if m.JiraID != "" {
+		fields["jiraId"] = m.JiraID
+	}
+	if m.JiraIssueType != "" {
+		fields["jiraIssueType"] = m.JiraIssueType
+	}
+	if len(m.Repositories) > 0 {
+		fields["repositories"] = m.Repositories
+	}
+	if len(m.PullRequests) > 0 {
+		fields["pullRequests"] = m.PullRequests
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON reads a flat JSON metadata object. Repositories written by
+// older versions as a comma-separated string are migrated to an array.
+func (m *BeadsMetadata) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	*m = BeadsMetadata{}
+	for key, raw := range fields {
+		var err error
+		switch key {
+		case "jiraKey":
+			err = json.Unmarshal(raw, &m.JiraKey)
+		case "jiraId":
+			err = json.Unmarshal(raw, &m.JiraID)
+		case "jiraIssueType":
+			err = json.Unmarshal(raw, &m.JiraIssueType)
+		case "repositories":
+			m.Repositories, err = unmarshalRepositories(raw)
+		case "pullRequests":
+			err = json.Unmarshal(raw, &m.PullRequests)
+		default:
+			var value string
+			err = json.Unmarshal(raw, &value)
+			if err == nil {
+				if m.Custom == nil {
+					m.Custom = make(map[string]string)
+				}
+				m.Custom[key] = value
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("invalid metadata %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// unmarshalRepositories reads repositories from a JSON array, or from a
+// legacy comma-separated string
+func unmarshalRepositories(raw json.RawMessage) ([]string, error) {
+	var legacy string
+	if err := json.Unmarshal(raw, &legacy); err == nil {
+		var repos []string
+		for _, repo := range strings.Split(legacy, ",") {
+			if repo = strings.TrimSpace(repo); repo != "" {
+				repos = append(repos, repo)
+			}
+		}
+		return repos, nil
+	}
+
+	var repos []string
+	if err := json.Unmarshal(raw, &repos); err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+// hasRepository reports whether the metadata already lists a repository
+func (m *BeadsMetadata) hasRepository(repository string) bool {
+	for _, repo := range m.Repositories {
+		if repo == repository {
+			return true
+		}
+	}
+	return false
+}
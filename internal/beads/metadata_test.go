@@ -0,0 +1,122 @@
+package beads
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBeadsMetadataMarshalJSON(t *testing.T) {
+	metadata := BeadsMetadata{
+		JiraKey:      "PROJ-1",
+		Repositories: []string{"https://github.com/acme/api,v2", "https://github.com/acme/web"},
+		PullRequests: []BeadsPullRequest{{URL: "https://github.com/acme/api/pull/12", State: "open"}},
+		Custom:       map[string]string{"team": "platform"},
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		t.Fatalf("Failed to marshal metadata: %v", err)
+	}
+
+	expected := `{"jiraKey":"PROJ-1","pullRequests":[{"url":"https://github.com/acme/api/pull/12","state":"open"}],` +
+		`"repositories":["https://github.com/acme/api,v2","https://github.com/acme/web"],"team":"platform"}`
+	if string(data) != expected {
+		t.Errorf("Unexpected JSON:\n got: %s\nwant: %s", data, expected)
+	}
+
+	var roundTrip BeadsMetadata
+	if err := json.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatalf("Failed to unmarshal metadata: %v", err)
+	}
+	if len(roundTrip.Repositories) != 2 || roundTrip.Repositories[0] != "https://github.com/acme/api,v2" {
+		t.Errorf("Expected repository with comma to survive round trip, got %v", roundTrip.Repositories)
+	}
+	if roundTrip.Custom["team"] != "platform" {
+		t.Errorf("Expected custom key to survive round trip, got %v", roundTrip.Custom)
+	}
+}
+
+func TestBeadsMetadataUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantRepos     []string
+		wantPRs       []BeadsPullRequest
+		wantErr       bool
+		wantJiraKey   string
+		wantCustomKey string
+	}{
+		{
+			name:      "repositories array",
+			input:     `{"repositories":["https://github.com/org/repo1","https://github.com/org/repo2"]}`,
+			wantRepos: []string{"https://github.com/org/repo1", "https://github.com/org/repo2"},
+		},
+		{
+			name:        "legacy comma-separated repositories",
+			input:       `{"jiraKey":"PROJ-1","repositories":"https://github.com/org/repo1, https://github.com/org/repo2,"}`,
+			wantRepos:   []string{"https://github.com/org/repo1", "https://github.com/org/repo2"},
+			wantJiraKey: "PROJ-1",
+		},
+		{
+			name:  "pull requests array",
+			input: `{"pullRequests":[{"url":"https://github.com/org/repo/pull/1","state":"merged"},{"url":"https://github.com/org/repo/pull/2"}]}`,
+			wantPRs: []BeadsPullRequest{
+				{URL: "https://github.com/org/repo/pull/1", State: "merged"},
+				{URL: "https://github.com/org/repo/pull/2"},
+			},
+		},
+		{
+			name:          "custom keys",
+			input:         `{"sourceSystem":"jira"}`,
+			wantCustomKey: "sourceSystem",
+		},
+		{
+			name:    "invalid repositories",
+			input:   `{"repositories":42}`,
+			wantErr: true,
+		},
+		{
+			name:    "pull requests string",
+			input:   `{"pullRequests":"https://github.com/org/repo/pull/1 (merged)"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var metadata BeadsMetadata
+			err := json.Unmarshal([]byte(tt.input), &metadata)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(metadata.Repositories) != len(tt.wantRepos) {
+				t.Fatalf("Expected repositories %v, got %v", tt.wantRepos, metadata.Repositories)
+			}
+			for i, repo := range tt.wantRepos {
+				if metadata.Repositories[i] != repo {
+					t.Errorf("Expected repository %s at %d, got %s", repo, i, metadata.Repositories[i])
+				}
+			}
+
+			if len(metadata.PullRequests) != len(tt.wantPRs) {
+				t.Fatalf("Expected pull requests %v, got %v", tt.wantPRs, metadata.PullRequests)
+			}
+			for i, pr := range tt.wantPRs {
+				if metadata.PullRequests[i] != pr {
+					t.Errorf("Expected pull request %v at %d, got %v", pr, i, metadata.PullRequests[i])
+				}
+			}
+
+			if metadata.JiraKey != tt.wantJiraKey {
+				t.Errorf("Expected jiraKey %q, got %q", tt.wantJiraKey, metadata.JiraKey)
+			}
+			if tt.wantCustomKey != "" && metadata.Custom[tt.wantCustomKey] == "" {
+				t.Errorf("Expected custom key %s, got %v", tt.wantCustomKey, metadata.Custom)
+			}
+		})
+	}
+}
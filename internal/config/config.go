@@ -1,31 +1,185 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config holds the configuration for jira-beads-sync
 type Config struct {
-	Jira JiraConfig `yaml:"jira"`
+	Jira    JiraConfig    `yaml:"jira,omitempty"`
+	Mapping MappingConfig `yaml:"mapping,omitempty"`
+	Cache   CacheConfig   `yaml:"cache,omitempty"`
+
+	// Profiles holds named Jira configurations, e.g. for a Cloud site and an
+	// on-prem instance. DefaultProfile is used when none is selected; without
+	// one, the top-level jira settings are used.
+	Profiles       map[string]JiraConfig `yaml:"profiles,omitempty"`
+	DefaultProfile string                `yaml:"default_profile,omitempty"`
+
+	// Profile is the name of the profile loaded into Jira, or "" for the
+	// top-level jira settings
+	Profile string `yaml:"-"`
+
+	// topLevelJira holds the top-level jira settings of the file while a
+	// profile is loaded into Jira, so saving writes them back unchanged
+	topLevelJira JiraConfig
 }
 
 // JiraConfig holds Jira-specific configuration
 type JiraConfig struct {
 	BaseURL    string `yaml:"base_url"`
 	Username   string `yaml:"username"`
-	APIToken   string `yaml:"api_token"`
-	AuthMethod string `yaml:"auth_method"` // "basic" or "bearer"
+	APIToken   string `yaml:"api_token,omitempty"`
+	AuthMethod string `yaml:"auth_method"`          // "basic", "bearer" or "oauth2"
+	RankField  string `yaml:"rank_field,omitempty"` // e.g. "customfield_10019"; discovered if empty
+
+	// APITokenCommand is a command whose output is the API token, e.g.
+	// "pass show jira"; APITokenFile is a file containing it;
+	// APITokenEncrypted keeps it in the passphrase-encrypted credential
+	// store. Each keeps the token out of this file, and is resolved by
+	// Config.Token. An inline api_token (or JIRA_API_TOKEN) takes precedence.
+	APITokenCommand   string `yaml:"api_token_command,omitempty"`
+	APITokenFile      string `yaml:"api_token_file,omitempty"`
+	APITokenEncrypted bool   `yaml:"api_token_encrypted,omitempty"`
+
+	// SkipDevelopment disables fetching remote links and development
+	// information (pull requests, branches) for each imported issue, which
+	// costs two or more requests per issue
+	SkipDevelopment bool `yaml:"skip_development,omitempty"`
+
+	// SearchFields overrides the issue fields requested when fetching issues
+	// through search (fetch-by-label, fetch-jql)
+	SearchFields []string `yaml:"search_fields,omitempty"`
+
+	// Deployment selects the search API: "cloud", "server" or "datacenter"
+	// (REST API v2), or "auto" to detect it from /rest/api/2/serverInfo.
+	// Empty means "auto".
+	Deployment string `yaml:"deployment,omitempty"`
+
+	// OAuth2 holds the OAuth 2.0 app and tokens used with auth_method "oauth2"
+	OAuth2 *OAuth2Config `yaml:"oauth2,omitempty"`
+
+	// TLS and proxy settings. HTTPS_PROXY, HTTP_PROXY and NO_PROXY are
+	// honoured when proxy_url is empty.
+	CAFile             string `yaml:"ca_file,omitempty"`     // PEM bundle of additional trusted CAs
+	ClientCert         string `yaml:"client_cert,omitempty"` // PEM client certificate for mutual TLS
+	ClientKey          string `yaml:"client_key,omitempty"`  // PEM private key of client_cert
+	ProxyURL           string `yaml:"proxy_url,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"` // for testing only
+}
+
+// OAuth2Config holds an OAuth 2.0 (3LO) app's credentials and the tokens
+// obtained for it by 'configure'. Tokens are updated in place when refreshed.
+type OAuth2Config struct {
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret,omitempty"`
+	AuthURL      string   `yaml:"auth_url,omitempty"`  // Atlassian Cloud if empty
+	TokenURL     string   `yaml:"token_url,omitempty"` // Atlassian Cloud if empty
+	Scopes       []string `yaml:"scopes,omitempty"`
+	CallbackPort int      `yaml:"callback_port,omitempty"` // DefaultOAuth2CallbackPort if 0
+
+	// CloudID is the Atlassian Cloud site ID; API requests go through
+	// api.atlassian.com when it is set
+	CloudID string `yaml:"cloud_id,omitempty"`
+
+	AccessToken  string    `yaml:"access_token,omitempty"`
+	RefreshToken string    `yaml:"refresh_token,omitempty"`
+	Expiry       time.Time `yaml:"expiry,omitempty"`
+}
+
+// DefaultOAuth2CallbackPort is the localhost port of the OAuth 2.0 callback,
+// http://localhost:8085/callback, which must be registered with the app
+const DefaultOAuth2CallbackPort = 8085
+
+// RedirectURL returns the localhost callback URL of the authorization flow
+func (o *OAuth2Config) RedirectURL() string {
+	port := o.CallbackPort
+	if port == 0 {
+		port = DefaultOAuth2CallbackPort
+	}
+	return fmt.Sprintf("http://localhost:%d/callback", port)
+}
+
+// MappingConfig controls how Jira fields are mapped onto beads issues.
+// Each value is either "labels" (namespaced labels such as "fixVersion:2.4")
+// or "fields" (dedicated beads issue fields). Empty means "labels".
+type MappingConfig struct {
+	FixVersions     string `yaml:"fix_versions,omitempty"`
+	AffectsVersions string `yaml:"affects_versions,omitempty"`
+	Components      string `yaml:"components,omitempty"`
+
+	// Timezone is the zone timestamps are written to beads in: "UTC", "Local"
+	// or an IANA name such as "Europe/Dublin". Empty means UTC.
+	Timezone string `yaml:"timezone,omitempty"`
+}
+
+// CacheConfig controls the on-disk cache of Jira API responses, which is
+// shared by all profiles; responses are keyed by URL and credentials
+type CacheConfig struct {
+	Disabled bool   `yaml:"disabled,omitempty"`
+	Dir      string `yaml:"dir,omitempty"` // the user cache directory if empty
+
+	// TTL is how long a response is used without asking Jira whether it
+	// changed, e.g. "10m". Empty or "0" always asks, with a conditional
+	// request that Jira answers without a body if nothing changed.
+	TTL       string `yaml:"ttl,omitempty"`
+	MaxSizeMB int    `yaml:"max_size_mb,omitempty"` // 100 if 0
 }
 
 // configPathFunc is a variable that can be overridden in tests
 var configPathFunc = getConfigPath
 
-// Load loads configuration from a file or environment variables
+// Load loads configuration from a file or environment variables, using the
+// profile named by JIRA_PROFILE or the default profile
 func Load() (*Config, error) {
+	return LoadProfile("")
+}
+
+// LoadProfile loads configuration using the named profile. An empty name
+// selects the profile named by JIRA_PROFILE, then the default profile.
+func LoadProfile(profile string) (*Config, error) {
+	return LoadProfileForURL(profile, "")
+}
+
+// LoadProfileForURL loads configuration like LoadProfile. If no profile is
+// named, the profile whose base URL has the same host as rawURL is used,
+// falling back to the default profile. The error wraps ErrHostMismatch if
+// the loaded configuration is for another host than rawURL, so credentials
+// are never sent to an instance they weren't configured for.
+func LoadProfileForURL(profile, rawURL string) (*Config, error) {
+	if profile == "" {
+		profile = os.Getenv("JIRA_PROFILE")
+	}
+	return loadProfile(profile, rawURL)
+}
+
+// LoadMapping loads configuration for the offline commands, which only use
+// the field mapping shared by all profiles. A profile named by JIRA_PROFILE
+// that the config file doesn't define falls back to the default profile, as
+// the variable may be set for Jira commands with another config file; a
+// profile named with --profile must be defined.
+func LoadMapping(profile string) (*Config, error) {
+	if profile != "" {
+		return loadProfile(profile, "")
+	}
+
+	config, err := loadProfile(os.Getenv("JIRA_PROFILE"), "")
+	if errors.Is(err, ErrProfileNotFound) {
+		return loadProfile("", "")
+	}
+	return config, err
+}
+
+// loadProfile loads configuration using the named profile, or the one for
+// rawURL's host if none is named
+func loadProfile(profile, rawURL string) (*Config, error) {
 	config := &Config{}
 
 	// Try to load from config file first
@@ -36,6 +190,17 @@ func Load() (*Config, error) {
 		}
 	}
 
+	topLevel := false
+	if profile == "" && rawURL != "" {
+		profile = config.profileForURL(rawURL)
+		topLevel = profile == "" && config.Jira.BaseURL != "" && urlHost(config.Jira.BaseURL) == urlHost(rawURL)
+	}
+	if !topLevel {
+		if err := config.selectProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+
 	// Override with environment variables if present
 	if baseURL := os.Getenv("JIRA_BASE_URL"); baseURL != "" {
 		config.Jira.BaseURL = baseURL
@@ -55,6 +220,10 @@ func Load() (*Config, error) {
 		config.Jira.AuthMethod = "basic"
 	}
 
+	if err := config.checkHost(rawURL); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -63,11 +232,26 @@ func (c *Config) Validate() error {
 	if c.Jira.BaseURL == "" {
 		return fmt.Errorf("jira base URL is required")
 	}
+	if c.DefaultProfile != "" {
+		if _, ok := c.Profiles[c.DefaultProfile]; !ok {
+			return fmt.Errorf("default profile %q is not defined", c.DefaultProfile)
+		}
+	}
 	if c.Jira.AuthMethod == "" {
 		c.Jira.AuthMethod = "basic" // Default to basic auth
 	}
-	if c.Jira.AuthMethod != "basic" && c.Jira.AuthMethod != "bearer" {
-		return fmt.Errorf("jira auth method must be 'basic' or 'bearer', got: %s", c.Jira.AuthMethod)
+	if c.Jira.AuthMethod != "basic" && c.Jira.AuthMethod != "bearer" && c.Jira.AuthMethod != "oauth2" {
+		return fmt.Errorf("jira auth method must be 'basic', 'bearer' or 'oauth2', got: %s", c.Jira.AuthMethod)
+	}
+
+	switch c.Jira.Deployment {
+	case "", "auto", "cloud", "server", "datacenter":
+	default:
+		return fmt.Errorf("jira deployment must be 'auto', 'cloud', 'server' or 'datacenter', got: %s", c.Jira.Deployment)
+	}
+
+	if (c.Jira.ClientCert == "") != (c.Jira.ClientKey == "") {
+		return fmt.Errorf("jira client_cert and client_key must be set together")
 	}
 
 	// For basic auth, we need username and API token
@@ -75,22 +259,104 @@ func (c *Config) Validate() error {
 		if c.Jira.Username == "" {
 			return fmt.Errorf("jira username is required for basic auth")
 		}
-		if c.Jira.APIToken == "" {
+		if !c.Jira.HasAPIToken() {
 			return fmt.Errorf("jira API token is required")
 		}
 	}
 
 	// For bearer auth, we only need the token (username is optional)
 	if c.Jira.AuthMethod == "bearer" {
-		if c.Jira.APIToken == "" {
+		if !c.Jira.HasAPIToken() {
 			return fmt.Errorf("jira bearer token is required")
 		}
 	}
 
+	// For OAuth 2.0, we need the app; tokens are obtained by 'configure'
+	if c.Jira.AuthMethod == "oauth2" {
+		if c.Jira.OAuth2 == nil || c.Jira.OAuth2.ClientID == "" {
+			return fmt.Errorf("jira OAuth 2.0 client ID is required")
+		}
+	}
+
+	if err := c.Cache.Validate(); err != nil {
+		return err
+	}
+
+	return c.Mapping.Validate()
+}
+
+// Validate checks the cache TTL and size
+func (c *CacheConfig) Validate() error {
+	if _, err := c.TTLDuration(); err != nil {
+		return err
+	}
+	if c.MaxSizeMB < 0 {
+		return fmt.Errorf("cache max_size_mb must not be negative, got: %d", c.MaxSizeMB)
+	}
+	return nil
+}
+
+// TTLDuration returns how long a cached response is used without asking Jira
+func (c *CacheConfig) TTLDuration() (time.Duration, error) {
+	if c.TTL == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(c.TTL)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("cache ttl must be a duration such as 10m, got: %s", c.TTL)
+	}
+	return ttl, nil
+}
+
+// Directory returns the directory of the cache, by default in the user's
+// cache directory
+func (c *CacheConfig) Directory() (string, error) {
+	if c.Dir != "" {
+		return expandHome(c.Dir), nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "jira-beads-sync", "http"), nil
+}
+
+// Validate checks that every mapping target is either "labels" or "fields",
+// and that the timezone is known
+func (m *MappingConfig) Validate() error {
+	targets := []struct {
+		name   string
+		target string
+	}{
+		{"fix_versions", m.FixVersions},
+		{"affects_versions", m.AffectsVersions},
+		{"components", m.Components},
+	}
+	for _, t := range targets {
+		if t.target != "" && t.target != "labels" && t.target != "fields" {
+			return fmt.Errorf("mapping %s must be 'labels' or 'fields', got: %s", t.name, t.target)
+		}
+	}
+	if _, err := m.Location(); err != nil {
+		return err
+	}
 	return nil
 }
 
-// Save saves the configuration to a file
+// Location returns the zone timestamps are written to beads in
+func (m *MappingConfig) Location() (*time.Location, error) {
+	if m.Timezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(m.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("mapping timezone %s is not a known time zone: %w", m.Timezone, err)
+	}
+	return location, nil
+}
+
+// Save saves the configuration to a file. Settings loaded from a profile are
+// saved back to that profile.
 func (c *Config) Save() error {
 	configPath := configPathFunc()
 
@@ -100,7 +366,7 @@ func (c *Config) Save() error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	data, err := yaml.Marshal(c)
+	data, err := yaml.Marshal(c.fileConfig())
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -112,6 +378,35 @@ func (c *Config) Save() error {
 	return nil
 }
 
+// SaveOAuth2Token stores refreshed OAuth 2.0 tokens in the config file, in
+// the profile or top-level jira settings c was loaded from. Only the tokens
+// are updated, so values from environment variables aren't written to the
+// file.
+func (c *Config) SaveOAuth2Token(accessToken, refreshToken string, expiry time.Time) error {
+	configPath := configPathFunc()
+
+	config := &Config{}
+	if err := loadFromFile(configPath, config); err != nil {
+		return fmt.Errorf("failed to load config file: %w", err)
+	}
+	// An empty Profile means the top-level settings were loaded, which
+	// selectProfile would take to mean the default profile
+	if c.Profile != "" {
+		if err := config.selectProfile(c.Profile); err != nil {
+			return err
+		}
+	}
+	if config.Jira.OAuth2 == nil {
+		return fmt.Errorf("config file has no OAuth 2.0 settings")
+	}
+
+	config.Jira.OAuth2.AccessToken = accessToken
+	config.Jira.OAuth2.RefreshToken = refreshToken
+	config.Jira.OAuth2.Expiry = expiry
+
+	return config.Save()
+}
+
 // getConfigPath returns the path to the config file
 func getConfigPath() string {
 	// Try XDG_CONFIG_HOME first
@@ -142,8 +437,10 @@ func loadFromFile(path string, config *Config) error {
 	return nil
 }
 
-// PromptForConfig interactively prompts the user for configuration
-func PromptForConfig() (*Config, error) {
+// PromptForConfig interactively prompts the user for configuration. With
+// encrypt, an API token is kept in the encrypted credential store without
+// asking.
+func PromptForConfig(encrypt bool) (*Config, error) {
 	fmt.Println("Jira Configuration")
 	fmt.Println("==================")
 	fmt.Println()
@@ -159,7 +456,8 @@ func PromptForConfig() (*Config, error) {
 	fmt.Println("Authentication Method:")
 	fmt.Println("  1. Basic Auth (username + API token) - for Jira Cloud with .atlassian.net")
 	fmt.Println("  2. Bearer Token - for Jira Cloud with custom domain or Jira Server/Data Center")
-	fmt.Print("Select authentication method (1 or 2) [1]: ")
+	fmt.Println("  3. OAuth 2.0 - authorize an OAuth app in the browser")
+	fmt.Print("Select authentication method (1, 2 or 3) [1]: ")
 
 	var authChoice string
 	if _, err := fmt.Scanln(&authChoice); err != nil && err.Error() != "unexpected newline" {
@@ -181,6 +479,10 @@ func PromptForConfig() (*Config, error) {
 		if _, err := fmt.Scanln(&config.Jira.APIToken); err != nil {
 			return nil, fmt.Errorf("failed to read API token: %w", err)
 		}
+
+		if err := promptForEncryption(&config.Jira, encrypt); err != nil {
+			return nil, err
+		}
 	case "2":
 		config.Jira.AuthMethod = "bearer"
 
@@ -190,11 +492,30 @@ func PromptForConfig() (*Config, error) {
 			return nil, fmt.Errorf("failed to read bearer token: %w", err)
 		}
 
+		if err := promptForEncryption(&config.Jira, encrypt); err != nil {
+			return nil, err
+		}
+
 		// Username is optional for bearer auth but can be used for display purposes
 		fmt.Print("Username (optional, for display only): ")
 		_, _ = fmt.Scanln(&config.Jira.Username) // Ignore errors for optional field
+	case "3":
+		config.Jira.AuthMethod = "oauth2"
+		config.Jira.OAuth2 = &OAuth2Config{}
+
+		fmt.Println()
+		fmt.Printf("Register %s as the OAuth app's callback URL.\n", config.Jira.OAuth2.RedirectURL())
+		fmt.Print("OAuth Client ID: ")
+		if _, err := fmt.Scanln(&config.Jira.OAuth2.ClientID); err != nil {
+			return nil, fmt.Errorf("failed to read client ID: %w", err)
+		}
+
+		fmt.Print("OAuth Client Secret: ")
+		if _, err := fmt.Scanln(&config.Jira.OAuth2.ClientSecret); err != nil {
+			return nil, fmt.Errorf("failed to read client secret: %w", err)
+		}
 	default:
-		return nil, fmt.Errorf("invalid choice: %s (must be 1 or 2)", authChoice)
+		return nil, fmt.Errorf("invalid choice: %s (must be 1, 2 or 3)", authChoice)
 	}
 
 	if err := config.Validate(); err != nil {
@@ -203,3 +524,21 @@ func PromptForConfig() (*Config, error) {
 
 	return config, nil
 }
+
+// promptForEncryption asks whether to keep the token in the encrypted
+// credential store rather than in plaintext in the config file
+func promptForEncryption(jira *JiraConfig, encrypt bool) error {
+	if encrypt {
+		jira.APITokenEncrypted = true
+		return nil
+	}
+	fmt.Print("Store the token encrypted with a passphrase instead of in the config file? (y/N): ")
+
+	var answer string
+	if _, err := fmt.Scanln(&answer); err != nil && err.Error() != "unexpected newline" {
+		return fmt.Errorf("failed to read answer: %w", err)
+	}
+
+	jira.APITokenEncrypted = strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes")
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestConfigValidate(t *testing.T) {
@@ -119,7 +120,170 @@ func TestConfigValidate(t *testing.T) {
 				},
 			},
 			expectError: true,
-			errorMsg:    "jira auth method must be 'basic' or 'bearer', got: invalid",
+			errorMsg:    "jira auth method must be 'basic', 'bearer' or 'oauth2', got: invalid",
+		},
+		{
+			name: "valid oauth2 config",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:    "https://example.atlassian.net",
+					AuthMethod: "oauth2",
+					OAuth2:     &OAuth2Config{ClientID: "client-1", ClientSecret: "secret-1"},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "oauth2 missing client ID",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:    "https://example.atlassian.net",
+					AuthMethod: "oauth2",
+				},
+			},
+			expectError: true,
+			errorMsg:    "jira OAuth 2.0 client ID is required",
+		},
+		{
+			name: "client certificate without key",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:    "https://jira.example.com",
+					Username:   "user@example.com",
+					APIToken:   "token123",
+					ClientCert: "/etc/ssl/client.pem",
+				},
+			},
+			expectError: true,
+			errorMsg:    "jira client_cert and client_key must be set together",
+		},
+		{
+			name: "data center deployment",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:    "https://jira.example.com",
+					Username:   "user@example.com",
+					APIToken:   "token123",
+					Deployment: "datacenter",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid deployment",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:    "https://jira.example.com",
+					Username:   "user@example.com",
+					APIToken:   "token123",
+					Deployment: "on-prem",
+				},
+			},
+			expectError: true,
+			errorMsg:    "jira deployment must be 'auto', 'cloud', 'server' or 'datacenter', got: on-prem",
+		},
+		{
+			name: "valid mapping",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:  "https://jira.example.com",
+					Username: "user@example.com",
+					APIToken: "token123",
+				},
+				Mapping: MappingConfig{
+					FixVersions: "fields",
+					Components:  "labels",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid mapping target",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:  "https://jira.example.com",
+					Username: "user@example.com",
+					APIToken: "token123",
+				},
+				Mapping: MappingConfig{
+					Components: "metadata",
+				},
+			},
+			expectError: true,
+			errorMsg:    "mapping components must be 'labels' or 'fields', got: metadata",
+		},
+		{
+			name: "valid mapping timezone",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:  "https://jira.example.com",
+					Username: "user@example.com",
+					APIToken: "token123",
+				},
+				Mapping: MappingConfig{
+					Timezone: "UTC",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "unknown mapping timezone",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:  "https://jira.example.com",
+					Username: "user@example.com",
+					APIToken: "token123",
+				},
+				Mapping: MappingConfig{
+					Timezone: "Mars/Olympus_Mons",
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "valid cache settings",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:  "https://jira.example.com",
+					Username: "user@example.com",
+					APIToken: "token123",
+				},
+				Cache: CacheConfig{
+					TTL:       "10m",
+					MaxSizeMB: 50,
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid cache ttl",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:  "https://jira.example.com",
+					Username: "user@example.com",
+					APIToken: "token123",
+				},
+				Cache: CacheConfig{
+					TTL: "ten minutes",
+				},
+			},
+			expectError: true,
+			errorMsg:    "cache ttl must be a duration such as 10m, got: ten minutes",
+		},
+		{
+			name: "negative cache size",
+			config: &Config{
+				Jira: JiraConfig{
+					BaseURL:  "https://jira.example.com",
+					Username: "user@example.com",
+					APIToken: "token123",
+				},
+				Cache: CacheConfig{
+					MaxSizeMB: -1,
+				},
+			},
+			expectError: true,
+			errorMsg:    "cache max_size_mb must not be negative, got: -1",
 		},
 	}
 
@@ -547,3 +711,125 @@ func TestLoadFromFileNonExistent(t *testing.T) {
 		t.Error("Expected error for non-existent file, got nil")
 	}
 }
+
+func TestSaveOAuth2Token(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yml")
+
+	originalConfigPathFunc := configPathFunc
+	defer func() { configPathFunc = originalConfigPathFunc }()
+
+	configPathFunc = func() string {
+		return configPath
+	}
+
+	config := &Config{
+		Jira: JiraConfig{
+			BaseURL:    "https://example.atlassian.net",
+			AuthMethod: "oauth2",
+			OAuth2: &OAuth2Config{
+				ClientID:     "client-1",
+				ClientSecret: "secret-1",
+				CloudID:      "cloud-1",
+				AccessToken:  "access-1",
+				RefreshToken: "refresh-1",
+			},
+		},
+	}
+	if err := config.Save(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Environment overrides must not be written back with the tokens
+	t.Setenv("JIRA_BASE_URL", "https://override.example.com")
+
+	expiry := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := config.SaveOAuth2Token("access-2", "refresh-2", expiry); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	saved := &Config{}
+	if err := loadFromFile(configPath, saved); err != nil {
+		t.Fatalf("Failed to load saved config: %v", err)
+	}
+
+	if saved.Jira.BaseURL != "https://example.atlassian.net" {
+		t.Errorf("Expected base URL to be unchanged, got '%s'", saved.Jira.BaseURL)
+	}
+	oauth2 := saved.Jira.OAuth2
+	if oauth2.AccessToken != "access-2" || oauth2.RefreshToken != "refresh-2" || !oauth2.Expiry.Equal(expiry) {
+		t.Errorf("Expected refreshed tokens, got %+v", oauth2)
+	}
+	if oauth2.ClientID != "client-1" || oauth2.CloudID != "cloud-1" {
+		t.Errorf("Expected app settings to be kept, got %+v", oauth2)
+	}
+}
+
+func TestSaveOAuth2TokenTopLevelWithDefaultProfile(t *testing.T) {
+	configPath := useConfigFile(t, `jira:
+  base_url: https://legacy.atlassian.net
+  auth_method: oauth2
+  oauth2:
+    client_id: client-1
+    access_token: legacy-access-1
+    refresh_token: legacy-refresh-1
+default_profile: cloud
+profiles:
+  cloud:
+    base_url: https://acme.atlassian.net
+    auth_method: oauth2
+    oauth2:
+      client_id: client-2
+      access_token: cloud-access-1
+      refresh_token: cloud-refresh-1
+`)
+
+	// An issue URL on the top-level host loads the top-level settings, not
+	// the default profile
+	cfg, err := LoadProfileForURL("", "https://legacy.atlassian.net/browse/OLD-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := cfg.SaveOAuth2Token("legacy-access-2", "legacy-refresh-2", time.Time{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	saved := &Config{}
+	if err := loadFromFile(configPath, saved); err != nil {
+		t.Fatalf("Failed to load saved config: %v", err)
+	}
+	if got := saved.Jira.OAuth2; got.AccessToken != "legacy-access-2" || got.RefreshToken != "legacy-refresh-2" {
+		t.Errorf("Expected the top-level tokens refreshed, got %+v", got)
+	}
+	if got := saved.Profiles["cloud"].OAuth2; got.AccessToken != "cloud-access-1" || got.RefreshToken != "cloud-refresh-1" {
+		t.Errorf("Expected the default profile's tokens unchanged, got %+v", got)
+	}
+
+	// A profile loaded by name gets its own tokens
+	cfg, err = LoadProfile("cloud")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := cfg.SaveOAuth2Token("cloud-access-2", "cloud-refresh-2", time.Time{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	saved = &Config{}
+	if err := loadFromFile(configPath, saved); err != nil {
+		t.Fatalf("Failed to load saved config: %v", err)
+	}
+	if got := saved.Profiles["cloud"].OAuth2; got.AccessToken != "cloud-access-2" {
+		t.Errorf("Expected the cloud profile's tokens refreshed, got %+v", got)
+	}
+	if got := saved.Jira.OAuth2; got.AccessToken != "legacy-access-2" {
+		t.Errorf("Expected the top-level tokens kept, got %+v", got)
+	}
+}
+
+func TestOAuth2RedirectURL(t *testing.T) {
+	if got := (&OAuth2Config{}).RedirectURL(); got != "http://localhost:8085/callback" {
+		t.Errorf("Expected default callback URL, got '%s'", got)
+	}
+	if got := (&OAuth2Config{CallbackPort: 9000}).RedirectURL(); got != "http://localhost:9000/callback" {
+		t.Errorf("Expected callback URL on port 9000, got '%s'", got)
+	}
+}
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+// PassphraseEnv is the environment variable holding the passphrase of the
+// encrypted credential store, for non-interactive use
+const PassphraseEnv = "JIRA_BEADS_SYNC_PASSPHRASE"
+
+const (
+	credentialsFileName = "credentials.enc"
+	credentialsVersion  = 1
+
+	// scrypt parameters recommended for interactive logins
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32 // AES-256
+)
+
+// credentialsAAD binds the ciphertext to the file format
+var credentialsAAD = []byte("jira-beads-sync credentials v1")
+
+// encryptedCredentials is the on-disk format of the credential store: API
+// tokens by profile name, as JSON encrypted with AES-GCM under a key derived
+// from a passphrase with scrypt
+type encryptedCredentials struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// credentialCache holds the decrypted store, so the passphrase is asked for
+// at most once per process
+var credentialCache struct {
+	sync.Mutex
+	tokens     map[string]string
+	passphrase []byte
+}
+
+// passphraseFunc reads the store passphrase; confirm asks for it twice when
+// creating the store. It can be overridden in tests.
+var passphraseFunc = readPassphrase
+
+// credentialsPath returns the path of the encrypted credential store
+func credentialsPath() string {
+	return filepath.Join(filepath.Dir(configPathFunc()), credentialsFileName)
+}
+
+// readPassphrase reads the passphrase from PassphraseEnv, or from the terminal
+// without echoing it
+func readPassphrase(confirm bool) ([]byte, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("the credential store is encrypted; set %s or run in a terminal", PassphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, "Credential store passphrase: ")
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("passphrase must not be empty")
+	}
+
+	if confirm {
+		fmt.Fprint(os.Stderr, "Confirm passphrase: ")
+		again, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		if string(again) != string(passphrase) {
+			return nil, fmt.Errorf("passphrases don't match")
+		}
+	}
+
+	return passphrase, nil
+}
+
+// loadCredentials returns the decrypted store, asking for the passphrase the
+// first time. A missing store is empty. The caller must hold credentialCache.
+func loadCredentials() (map[string]string, error) {
+	if credentialCache.tokens != nil {
+		return credentialCache.tokens, nil
+	}
+
+	data, err := os.ReadFile(credentialsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credential store: %w", err)
+	}
+
+	var store encryptedCredentials
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("failed to parse credential store: %w", err)
+	}
+	if store.Version != credentialsVersion || store.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported credential store version %d (%s)", store.Version, store.KDF)
+	}
+
+	passphrase, err := passphraseFunc(false)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := scrypt.Key(passphrase, store.Salt, store.N, store.R, store.P, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, store.Nonce, store.Ciphertext, credentialsAAD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credential store: wrong passphrase or corrupted file")
+	}
+
+	tokens := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted credentials: %w", err)
+	}
+
+	credentialCache.tokens = tokens
+	credentialCache.passphrase = passphrase
+	return tokens, nil
+}
+
+// saveCredentials encrypts and writes the store with a fresh salt and nonce.
+// The caller must hold credentialCache.
+func saveCredentials(tokens map[string]string) error {
+	passphrase := credentialCache.passphrase
+	if passphrase == nil {
+		var err error
+		if passphrase, err = passphraseFunc(true); err != nil {
+			return err
+		}
+	}
+
+	plaintext, err := json.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	store := encryptedCredentials{
+		Version: credentialsVersion,
+		KDF:     "scrypt",
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    make([]byte, 16),
+		Nonce:   make([]byte, 12),
+	}
+	if _, err := rand.Read(store.Salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	if _, err := rand.Read(store.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	key, err := scrypt.Key(passphrase, store.Salt, store.N, store.R, store.P, scryptKeyLen)
+	if err != nil {
+		return fmt.Errorf("failed to derive key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	store.Ciphertext = gcm.Seal(nil, store.Nonce, plaintext, credentialsAAD)
+
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credential store: %w", err)
+	}
+
+	path := credentialsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write credential store: %w", err)
+	}
+
+	credentialCache.tokens = tokens
+	credentialCache.passphrase = passphrase
+	return nil
+}
+
+// newGCM returns an AES-GCM cipher for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return gcm, nil
+}
+
+// encryptedToken returns the API token of a profile from the encrypted store
+func encryptedToken(profile string) (string, error) {
+	credentialCache.Lock()
+	defer credentialCache.Unlock()
+
+	tokens, err := loadCredentials()
+	if err != nil {
+		return "", err
+	}
+
+	token, ok := tokens[profile]
+	if !ok {
+		if profile == "" {
+			return "", fmt.Errorf("no API token in the encrypted credential store. Run 'jira-beads-sync configure'")
+		}
+		return "", fmt.Errorf("no API token for profile %q in the encrypted credential store. Run 'jira-beads-sync --profile %s configure'", profile, profile)
+	}
+	return token, nil
+}
+
+// storeEncryptedToken saves the API token of a profile in the encrypted
+// store, creating it if needed
+func storeEncryptedToken(profile, token string) error {
+	credentialCache.Lock()
+	defer credentialCache.Unlock()
+
+	tokens, err := loadCredentials()
+	if err != nil {
+		return err
+	}
+
+	updated := make(map[string]string, len(tokens)+1)
+	for name, t := range tokens {
+		updated[name] = t
+	}
+	updated[profile] = token
+
+	return saveCredentials(updated)
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// usePassphrase makes the credential store use a fixed passphrase and start
+// with an empty cache, returning a counter of passphrase prompts
+func usePassphrase(t *testing.T, passphrase string) *int {
+	prompts := 0
+	originalPassphraseFunc := passphraseFunc
+	t.Cleanup(func() {
+		passphraseFunc = originalPassphraseFunc
+		resetCredentialCache()
+	})
+
+	resetCredentialCache()
+	passphraseFunc = func(confirm bool) ([]byte, error) {
+		prompts++
+		return []byte(passphrase), nil
+	}
+	return &prompts
+}
+
+func resetCredentialCache() {
+	credentialCache.Lock()
+	defer credentialCache.Unlock()
+	credentialCache.tokens = nil
+	credentialCache.passphrase = nil
+}
+
+func TestEncryptedCredentialStore(t *testing.T) {
+	useConfigFile(t, "")
+	prompts := usePassphrase(t, "correct horse battery staple")
+
+	if err := storeEncryptedToken("cloud", "cloud-token"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := storeEncryptedToken("onprem", "onprem-token"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(credentialsPath())
+	if err != nil {
+		t.Fatalf("Failed to read credential store: %v", err)
+	}
+	if strings.Contains(string(data), "cloud-token") || strings.Contains(string(data), "onprem-token") {
+		t.Error("Expected tokens not to appear in plaintext in the credential store")
+	}
+	if info, err := os.Stat(credentialsPath()); err == nil && info.Mode().Perm()&0077 != 0 {
+		t.Errorf("Credential store has too permissive permissions: %o", info.Mode().Perm())
+	}
+
+	// A new process decrypts the store once and serves later lookups from the cache
+	resetCredentialCache()
+	*prompts = 0
+	for _, profile := range []string{"cloud", "onprem", "cloud"} {
+		token, err := encryptedToken(profile)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if token != profile+"-token" {
+			t.Errorf("Expected token '%s-token', got '%s'", profile, token)
+		}
+	}
+	if *prompts != 1 {
+		t.Errorf("Expected 1 passphrase prompt, got %d", *prompts)
+	}
+
+	if _, err := encryptedToken("staging"); err == nil || !strings.Contains(err.Error(), `profile "staging"`) {
+		t.Errorf("Expected missing profile error, got: %v", err)
+	}
+}
+
+func TestEncryptedCredentialStoreWrongPassphrase(t *testing.T) {
+	useConfigFile(t, "")
+	usePassphrase(t, "right passphrase")
+
+	if err := storeEncryptedToken("", "secret-token"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	resetCredentialCache()
+	passphraseFunc = func(confirm bool) ([]byte, error) {
+		return []byte("wrong passphrase"), nil
+	}
+
+	_, err := encryptedToken("")
+	if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("Expected decryption error, got: %v", err)
+	}
+}
+
+func TestSaveProfileEncrypted(t *testing.T) {
+	configPath := useConfigFile(t, "")
+	usePassphrase(t, "passphrase")
+
+	jira := JiraConfig{
+		BaseURL:           "https://jira.corp.example",
+		AuthMethod:        "bearer",
+		APIToken:          "onprem-token",
+		APITokenEncrypted: true,
+	}
+	if err := SaveProfile("onprem", jira); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	if strings.Contains(string(data), "onprem-token") {
+		t.Errorf("Expected token not to be saved in the config file, got:\n%s", data)
+	}
+
+	resetCredentialCache()
+	cfg, err := LoadProfile("onprem")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected valid config, got: %v", err)
+	}
+	token, err := cfg.Token()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if token != "onprem-token" {
+		t.Errorf("Expected token from the encrypted store, got '%s'", token)
+	}
+}
+
+func TestMigrateSecretToEncryptedStore(t *testing.T) {
+	configPath := useConfigFile(t, profilesYAML)
+	usePassphrase(t, "passphrase")
+
+	if _, err := MigrateSecret("", SecretTarget{Encrypt: true}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	if strings.Contains(string(data), "cloud-token") {
+		t.Errorf("Expected inline token to be removed, got:\n%s", data)
+	}
+
+	resetCredentialCache()
+	token, err := encryptedToken("cloud")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if token != "cloud-token" {
+		t.Errorf("Expected migrated token, got '%s'", token)
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrProfileNotFound is returned when the selected profile isn't defined
+var ErrProfileNotFound = errors.New("profile not found")
+
+// ErrHostMismatch is returned when a URL points to another Jira instance than
+// the selected configuration, whose credentials must not be sent there
+var ErrHostMismatch = errors.New("no configuration for this Jira host")
+
+// ProfileNames returns the names of the configured profiles in sorted order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selectProfile loads the named profile into Jira. An empty name selects the
+// default profile, or keeps the top-level jira settings if there is none.
+func (c *Config) selectProfile(name string) error {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		c.Profile = ""
+		return nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("%w in config file: %s", ErrProfileNotFound, name)
+	}
+	c.topLevelJira = c.Jira
+	c.Jira = profile
+	c.Profile = name
+	return nil
+}
+
+// profileForURL returns the profile whose base URL has the same host as
+// rawURL, or "" if none does (or the top-level jira settings do)
+func (c *Config) profileForURL(rawURL string) string {
+	host := urlHost(rawURL)
+	if host == "" || urlHost(c.Jira.BaseURL) == host {
+		return ""
+	}
+
+	for _, name := range c.ProfileNames() {
+		if urlHost(c.Profiles[name].BaseURL) == host {
+			return name
+		}
+	}
+	return ""
+}
+
+// checkHost returns ErrHostMismatch if rawURL is on another host than the
+// loaded Jira settings. Without a base URL there is nothing to leak, and the
+// configuration is incomplete anyway.
+func (c *Config) checkHost(rawURL string) error {
+	host := urlHost(rawURL)
+	if host == "" || c.Jira.BaseURL == "" || urlHost(c.Jira.BaseURL) == host {
+		return nil
+	}
+
+	configured := "the top-level jira settings"
+	if c.Profile != "" {
+		configured = fmt.Sprintf("profile %q", c.Profile)
+	}
+	return fmt.Errorf("%w: %s is for %s, not %s; pass --profile with a profile for %s",
+		ErrHostMismatch, configured, urlHost(c.Jira.BaseURL), host, host)
+}
+
+// urlHost returns the lowercased host (and port) of a URL, or ""
+func urlHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Host)
+}
+
+// fileConfig returns the configuration as it is written to the config file,
+// with the Jira settings stored under the profile they were loaded from and
+// tokens resolved from a command or file left out
+func (c *Config) fileConfig() *Config {
+	out := *c
+	out.Jira = stripResolvedToken(c.Jira)
+	out.Profiles = make(map[string]JiraConfig, len(c.Profiles)+1)
+	for name, profile := range c.Profiles {
+		out.Profiles[name] = stripResolvedToken(profile)
+	}
+
+	if c.Profile != "" {
+		out.Profiles[c.Profile] = out.Jira
+		out.Jira = stripResolvedToken(c.topLevelJira)
+	}
+	if len(out.Profiles) == 0 {
+		out.Profiles = nil
+	}
+	return &out
+}
+
+// SaveProfile saves Jira settings to the named profile of the config file,
+// keeping the file's other profiles and settings. An empty name saves to the
+// default profile, or to the top-level jira settings if there is none. The
+// first profile saved to a file without Jira settings becomes the default.
+// With api_token_encrypted the token goes to the encrypted credential store.
+func SaveProfile(name string, jira JiraConfig) error {
+	config := &Config{}
+	configPath := configPathFunc()
+	if _, err := os.Stat(configPath); err == nil {
+		if err := loadFromFile(configPath, config); err != nil {
+			return fmt.Errorf("failed to load config file: %w", err)
+		}
+	}
+
+	if name == "" {
+		name = config.DefaultProfile
+	}
+	if name != "" && config.DefaultProfile == "" && config.Jira.BaseURL == "" {
+		config.DefaultProfile = name
+	}
+	if jira.APITokenEncrypted && jira.APIToken != "" {
+		if err := storeEncryptedToken(name, jira.APIToken); err != nil {
+			return err
+		}
+	}
+	if name != "" {
+		if config.Profiles == nil {
+			config.Profiles = make(map[string]JiraConfig)
+		}
+		config.Profiles[name] = jira
+	} else {
+		config.Jira = jira
+	}
+
+	return config.Save()
+}
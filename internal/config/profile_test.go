@@ -0,0 +1,259 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const profilesYAML = `default_profile: cloud
+profiles:
+  cloud:
+    base_url: https://acme.atlassian.net
+    username: user@acme.com
+    api_token: cloud-token
+    auth_method: basic
+  onprem:
+    base_url: https://jira.corp.example:8443
+    api_token: onprem-token
+    auth_method: bearer
+mapping:
+  components: fields
+`
+
+// useConfigFile points the config path at a temporary file with the given
+// content, restoring it when the test ends
+func useConfigFile(t *testing.T, content string) string {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	if content != "" {
+		if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+	}
+
+	originalConfigPathFunc := configPathFunc
+	t.Cleanup(func() { configPathFunc = originalConfigPathFunc })
+	configPathFunc = func() string {
+		return configPath
+	}
+
+	return configPath
+}
+
+func TestLoadProfile(t *testing.T) {
+	useConfigFile(t, profilesYAML)
+
+	tests := []struct {
+		name        string
+		profile     string
+		envProfile  string
+		expected    string
+		expectedURL string
+	}{
+		{"default profile", "", "", "cloud", "https://acme.atlassian.net"},
+		{"named profile", "onprem", "", "onprem", "https://jira.corp.example:8443"},
+		{"JIRA_PROFILE", "", "onprem", "onprem", "https://jira.corp.example:8443"},
+		{"flag overrides JIRA_PROFILE", "cloud", "onprem", "cloud", "https://acme.atlassian.net"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JIRA_PROFILE", tt.envProfile)
+
+			cfg, err := LoadProfile(tt.profile)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if cfg.Profile != tt.expected {
+				t.Errorf("Expected profile '%s', got '%s'", tt.expected, cfg.Profile)
+			}
+			if cfg.Jira.BaseURL != tt.expectedURL {
+				t.Errorf("Expected base URL '%s', got '%s'", tt.expectedURL, cfg.Jira.BaseURL)
+			}
+			if cfg.Mapping.Components != "fields" {
+				t.Errorf("Expected mapping shared across profiles, got '%s'", cfg.Mapping.Components)
+			}
+		})
+	}
+}
+
+func TestLoadProfileNotFound(t *testing.T) {
+	useConfigFile(t, profilesYAML)
+
+	_, err := LoadProfile("staging")
+	if !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("Expected ErrProfileNotFound, got: %v", err)
+	}
+}
+
+func TestLoadMappingUndefinedProfile(t *testing.T) {
+	useConfigFile(t, profilesYAML)
+
+	// JIRA_PROFILE may be set for another config file, so an undefined one
+	// falls back to the default profile and its mapping
+	t.Setenv("JIRA_PROFILE", "staging")
+	cfg, err := LoadMapping("")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Profile != "cloud" || cfg.Mapping.Components != "fields" {
+		t.Errorf("Expected the default profile and mapping, got profile '%s' and components '%s'", cfg.Profile, cfg.Mapping.Components)
+	}
+
+	// A profile named with --profile must be defined
+	if _, err := LoadMapping("staging"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("Expected ErrProfileNotFound, got: %v", err)
+	}
+}
+
+func TestLoadProfileForURL(t *testing.T) {
+	useConfigFile(t, "jira:\n  base_url: https://legacy.example.com\n"+profilesYAML)
+
+	tests := []struct {
+		name        string
+		profile     string
+		url         string
+		expected    string
+		expectedURL string
+		expectError bool
+	}{
+		{"matching host", "", "https://jira.corp.example:8443/browse/OPS-7", "onprem", "https://jira.corp.example:8443", false},
+		{"host is case insensitive", "", "https://ACME.atlassian.net/browse/PROJ-1", "cloud", "https://acme.atlassian.net", false},
+		{"key without URL uses default", "", "", "cloud", "https://acme.atlassian.net", false},
+		{"top-level settings match", "", "https://legacy.example.com/browse/OLD-1", "", "https://legacy.example.com", false},
+		{"unknown host", "", "https://other.atlassian.net/browse/X-1", "", "", true},
+		{"explicit profile for another host", "cloud", "https://jira.corp.example:8443/browse/OPS-7", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadProfileForURL(tt.profile, tt.url)
+			if tt.expectError {
+				if !errors.Is(err, ErrHostMismatch) {
+					t.Errorf("Expected ErrHostMismatch, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if cfg.Profile != tt.expected {
+				t.Errorf("Expected profile '%s', got '%s'", tt.expected, cfg.Profile)
+			}
+			if cfg.Jira.BaseURL != tt.expectedURL {
+				t.Errorf("Expected base URL '%s', got '%s'", tt.expectedURL, cfg.Jira.BaseURL)
+			}
+		})
+	}
+}
+
+func TestSaveWritesBackToProfile(t *testing.T) {
+	configPath := useConfigFile(t, profilesYAML)
+
+	cfg, err := LoadProfile("onprem")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	cfg.Jira.APIToken = "rotated-token"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	saved := &Config{}
+	if err := loadFromFile(configPath, saved); err != nil {
+		t.Fatalf("Failed to load saved config: %v", err)
+	}
+	if saved.Jira.BaseURL != "" {
+		t.Errorf("Expected no top-level jira settings, got base URL '%s'", saved.Jira.BaseURL)
+	}
+	if saved.Profiles["onprem"].APIToken != "rotated-token" {
+		t.Errorf("Expected onprem token to be updated, got '%s'", saved.Profiles["onprem"].APIToken)
+	}
+	if saved.Profiles["cloud"].APIToken != "cloud-token" {
+		t.Errorf("Expected cloud profile to be unchanged, got '%s'", saved.Profiles["cloud"].APIToken)
+	}
+}
+
+func TestSaveThroughProfileKeepsTopLevelSettings(t *testing.T) {
+	configPath := useConfigFile(t, `jira:
+  base_url: https://legacy.example.com
+  username: legacy@example.com
+  api_token: legacy-token
+  auth_method: basic
+`+profilesYAML)
+
+	cfg, err := LoadProfile("onprem")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	cfg.Jira.APIToken = "rotated-token"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	saved := &Config{}
+	if err := loadFromFile(configPath, saved); err != nil {
+		t.Fatalf("Failed to load saved config: %v", err)
+	}
+	if saved.Jira.BaseURL != "https://legacy.example.com" || saved.Jira.APIToken != "legacy-token" {
+		t.Errorf("Expected the top-level jira settings kept, got %+v", saved.Jira)
+	}
+	if saved.Profiles["onprem"].APIToken != "rotated-token" {
+		t.Errorf("Expected onprem token to be updated, got '%s'", saved.Profiles["onprem"].APIToken)
+	}
+}
+
+func TestSaveProfile(t *testing.T) {
+	configPath := useConfigFile(t, "")
+
+	first := JiraConfig{BaseURL: "https://acme.atlassian.net", Username: "user@acme.com", APIToken: "t1", AuthMethod: "basic"}
+	if err := SaveProfile("cloud", first); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	second := JiraConfig{BaseURL: "https://jira.corp.example", APIToken: "t2", AuthMethod: "bearer"}
+	if err := SaveProfile("onprem", second); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	saved := &Config{}
+	if err := loadFromFile(configPath, saved); err != nil {
+		t.Fatalf("Failed to load saved config: %v", err)
+	}
+	if saved.DefaultProfile != "cloud" {
+		t.Errorf("Expected first profile to become the default, got '%s'", saved.DefaultProfile)
+	}
+	if strings.Join(saved.ProfileNames(), ",") != "cloud,onprem" {
+		t.Errorf("Expected profiles cloud,onprem, got %v", saved.ProfileNames())
+	}
+
+	// Saving without a name updates the default profile
+	first.APIToken = "t3"
+	if err := SaveProfile("", first); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	cfg, err := LoadProfile("")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Profile != "cloud" || cfg.Jira.APIToken != "t3" {
+		t.Errorf("Expected default profile cloud with token t3, got '%s' with '%s'", cfg.Profile, cfg.Jira.APIToken)
+	}
+}
+
+func TestValidateUndefinedDefaultProfile(t *testing.T) {
+	cfg := &Config{
+		Jira: JiraConfig{
+			BaseURL:  "https://jira.example.com",
+			Username: "user@example.com",
+			APIToken: "token123",
+		},
+		DefaultProfile: "missing",
+	}
+
+	err := cfg.Validate()
+	if err == nil || err.Error() != `default profile "missing" is not defined` {
+		t.Errorf("Expected undefined default profile error, got: %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// HasAPIToken reports whether an API token is configured inline, by command,
+// by file or in the encrypted credential store
+func (j *JiraConfig) HasAPIToken() bool {
+	return j.APIToken != "" || j.APITokenCommand != "" || j.APITokenFile != "" || j.APITokenEncrypted
+}
+
+// Token returns the API token of the selected profile. If it isn't set
+// inline, it is resolved on first use from the encrypted credential store, a
+// command or a file, so it is only unlocked when a Jira client needs it.
+func (c *Config) Token() (string, error) {
+	if err := c.resolveAPIToken(); err != nil {
+		return "", err
+	}
+	return c.Jira.APIToken, nil
+}
+
+// resolveAPIToken sets the API token of the selected profile if it isn't set
+// inline. The store is decrypted on first use and cached for the process.
+func (c *Config) resolveAPIToken() error {
+	if c.Jira.APIToken == "" && c.Jira.APITokenEncrypted {
+		token, err := encryptedToken(c.Profile)
+		if err != nil {
+			return err
+		}
+		c.Jira.APIToken = token
+		return nil
+	}
+	return c.Jira.resolveAPIToken()
+}
+
+// resolveAPIToken sets APIToken from api_token_command or api_token_file if
+// it isn't set inline. The token is resolved once; it is never written back
+// to the config file.
+func (j *JiraConfig) resolveAPIToken() error {
+	if j.APIToken != "" {
+		return nil
+	}
+
+	switch {
+	case j.APITokenCommand != "":
+		token, err := runTokenCommand(j.APITokenCommand)
+		if err != nil {
+			return err
+		}
+		j.APIToken = token
+	case j.APITokenFile != "":
+		token, err := readTokenFile(j.APITokenFile)
+		if err != nil {
+			return err
+		}
+		j.APIToken = token
+	}
+
+	return nil
+}
+
+// runTokenCommand runs a credential helper command through the shell and
+// returns the first line of its output. stdin and stderr are passed through
+// so helpers can prompt, e.g. for a GPG passphrase.
+func runTokenCommand(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("api_token_command failed: %w", err)
+	}
+
+	// Like pass, helpers may print more than the secret; only the first line is the token
+	token, _, _ := strings.Cut(stdout.String(), "\n")
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("api_token_command printed no token")
+	}
+	return token, nil
+}
+
+// readTokenFile reads an API token from a file
+func readTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to read api_token_file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("api_token_file %s is empty", path)
+	}
+	return token, nil
+}
+
+// expandHome expands a leading "~/" to the user's home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// stripResolvedToken clears a token that was resolved from a command or file,
+// so it isn't saved inline
+func stripResolvedToken(j JiraConfig) JiraConfig {
+	if j.APITokenCommand != "" || j.APITokenFile != "" || j.APITokenEncrypted {
+		j.APIToken = ""
+	}
+	return j
+}
+
+// DefaultTokenFile returns the file migrate-secret moves a profile's token to
+func DefaultTokenFile(profile string) string {
+	name := "api-token"
+	if profile != "" {
+		name = profile + "-api-token"
+	}
+	return filepath.Join(filepath.Dir(configPathFunc()), name)
+}
+
+// SecretTarget is where MigrateSecret moves an inline API token: a credential
+// helper command, the encrypted credential store, or a file
+type SecretTarget struct {
+	Command string
+	Encrypt bool
+	File    string // DefaultTokenFile if empty
+}
+
+// MigrateSecret removes the inline API token of a profile from the config
+// file. With a command, the command must print the same token; with Encrypt
+// the token moves to the encrypted credential store; otherwise it is written
+// to a file readable only by the user. It returns where the token now comes
+// from.
+func MigrateSecret(profile string, target SecretTarget) (string, error) {
+	configPath := configPathFunc()
+
+	config := &Config{}
+	if err := loadFromFile(configPath, config); err != nil {
+		return "", fmt.Errorf("failed to load config file: %w", err)
+	}
+	if err := config.selectProfile(profile); err != nil {
+		return "", err
+	}
+
+	token := config.Jira.APIToken
+	if token == "" {
+		return "", fmt.Errorf("no inline api_token to migrate")
+	}
+
+	config.Jira.APITokenCommand = ""
+	config.Jira.APITokenFile = ""
+	config.Jira.APITokenEncrypted = false
+
+	var source string
+	switch {
+	case target.Command != "":
+		// Make sure the helper works before removing the only copy of the token
+		output, err := runTokenCommand(target.Command)
+		if err != nil {
+			return "", err
+		}
+		if output != token {
+			return "", fmt.Errorf("api_token_command output doesn't match the stored token; not migrating")
+		}
+		config.Jira.APITokenCommand = target.Command
+		source = "command: " + target.Command
+	case target.Encrypt:
+		if err := storeEncryptedToken(config.Profile, token); err != nil {
+			return "", err
+		}
+		config.Jira.APITokenEncrypted = true
+		source = "encrypted store: " + credentialsPath()
+	default:
+		tokenFile := target.File
+		if tokenFile == "" {
+			tokenFile = DefaultTokenFile(config.Profile)
+		}
+		path := expandHome(tokenFile)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return "", fmt.Errorf("failed to create token directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+			return "", fmt.Errorf("failed to write token file: %w", err)
+		}
+		config.Jira.APITokenFile = tokenFile
+		source = "file: " + tokenFile
+	}
+
+	config.Jira.APIToken = ""
+	if err := config.Save(); err != nil {
+		return "", err
+	}
+
+	return source, nil
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestResolveAPIToken(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("token command tests use a POSIX shell")
+	}
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		jira     JiraConfig
+		expected string
+		errorMsg string
+	}{
+		{
+			name:     "inline token wins",
+			jira:     JiraConfig{APIToken: "inline-token", APITokenCommand: "echo command-token"},
+			expected: "inline-token",
+		},
+		{
+			name:     "command",
+			jira:     JiraConfig{APITokenCommand: "printf 'command-token\\nlogin: user\\n'"},
+			expected: "command-token",
+		},
+		{
+			name:     "file",
+			jira:     JiraConfig{APITokenFile: tokenFile},
+			expected: "file-token",
+		},
+		{
+			name:     "failing command",
+			jira:     JiraConfig{APITokenCommand: "exit 3"},
+			errorMsg: "api_token_command failed",
+		},
+		{
+			name:     "empty command output",
+			jira:     JiraConfig{APITokenCommand: "true"},
+			errorMsg: "api_token_command printed no token",
+		},
+		{
+			name:     "missing file",
+			jira:     JiraConfig{APITokenFile: filepath.Join(t.TempDir(), "missing")},
+			errorMsg: "failed to read api_token_file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jira := tt.jira
+			err := jira.resolveAPIToken()
+			if tt.errorMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if jira.APIToken != tt.expected {
+				t.Errorf("Expected token '%s', got '%s'", tt.expected, jira.APIToken)
+			}
+		})
+	}
+}
+
+func TestSaveOmitsResolvedToken(t *testing.T) {
+	configPath := useConfigFile(t, "")
+
+	cfg := &Config{
+		Jira: JiraConfig{
+			BaseURL:         "https://jira.example.com",
+			Username:        "user@example.com",
+			APIToken:        "resolved-token",
+			APITokenCommand: "pass show jira",
+		},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	if strings.Contains(string(data), "resolved-token") {
+		t.Errorf("Expected resolved token not to be saved, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), "api_token_command: pass show jira") {
+		t.Errorf("Expected token command to be saved, got:\n%s", data)
+	}
+}
+
+func TestMigrateSecretToFile(t *testing.T) {
+	configPath := useConfigFile(t, profilesYAML)
+
+	source, err := MigrateSecret("onprem", SecretTarget{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tokenFile := filepath.Join(filepath.Dir(configPath), "onprem-api-token")
+	if source != "file: "+tokenFile {
+		t.Errorf("Expected token file source, got '%s'", source)
+	}
+
+	data, err := os.ReadFile(tokenFile)
+	if err != nil {
+		t.Fatalf("Failed to read token file: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != "onprem-token" {
+		t.Errorf("Expected token in file, got '%s'", data)
+	}
+	if info, err := os.Stat(tokenFile); err == nil && info.Mode().Perm()&0077 != 0 {
+		t.Errorf("Token file has too permissive permissions: %o", info.Mode().Perm())
+	}
+
+	configData, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	if strings.Contains(string(configData), "onprem-token") {
+		t.Errorf("Expected inline token to be removed, got:\n%s", configData)
+	}
+	if !strings.Contains(string(configData), "cloud-token") {
+		t.Errorf("Expected other profiles to be kept, got:\n%s", configData)
+	}
+
+	// The migrated profile still loads its token
+	cfg, err := LoadProfile("onprem")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	token, err := cfg.Token()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if token != "onprem-token" {
+		t.Errorf("Expected token from file, got '%s'", token)
+	}
+}
+
+func TestMigrateSecretToCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("token command tests use a POSIX shell")
+	}
+
+	configPath := useConfigFile(t, profilesYAML)
+
+	// A helper that returns a different token is refused
+	if _, err := MigrateSecret("cloud", SecretTarget{Command: "echo stale-token"}); err == nil || !strings.Contains(err.Error(), "doesn't match") {
+		t.Errorf("Expected mismatch error, got: %v", err)
+	}
+
+	if _, err := MigrateSecret("cloud", SecretTarget{Command: "echo cloud-token"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	saved := &Config{}
+	if err := loadFromFile(configPath, saved); err != nil {
+		t.Fatalf("Failed to load saved config: %v", err)
+	}
+	cloud := saved.Profiles["cloud"]
+	if cloud.APIToken != "" || cloud.APITokenCommand != "echo cloud-token" {
+		t.Errorf("Expected token command instead of inline token, got %+v", cloud)
+	}
+
+	// Nothing left to migrate
+	if _, err := MigrateSecret("cloud", SecretTarget{}); err == nil {
+		t.Error("Expected error when there is no inline token")
+	}
+}
+
+func TestValidateTokenCommand(t *testing.T) {
+	cfg := &Config{
+		Jira: JiraConfig{
+			BaseURL:         "https://jira.example.com",
+			Username:        "user@example.com",
+			APITokenCommand: "pass show jira",
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected token command to satisfy validation, got: %v", err)
+	}
+}
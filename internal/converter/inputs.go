@@ -0,0 +1,230 @@
+package converter
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jirapb "github.com/conallob/jira-beads-sync/gen/jira"
+	"github.com/conallob/jira-beads-sync/internal/jira"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Stdin is the input name that reads an export from standard input
+const Stdin = "-"
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ExpandInputs expands glob patterns in input paths, e.g. "export-*.json".
+// Plain paths and "-" are kept as they are.
+func ExpandInputs(args []string) ([]string, error) {
+	var inputs []string
+	for _, arg := range args {
+		if arg == Stdin || !strings.ContainsAny(arg, "*?[") {
+			inputs = append(inputs, arg)
+			continue
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", arg)
+		}
+		inputs = append(inputs, matches...)
+	}
+	return inputs, nil
+}
+
+// input is a Jira export file, possibly gzip or zstd compressed, that can be
+// read once per conversion pass
+type input struct {
+	name   string
+	path   string
+	format jira.Format
+	export *jirapb.Export // CSV and XML exports, which are parsed whole
+
+	// diagnostics are the problems lenient parsing found in the last read
+	diagnostics []jira.Diagnostic
+}
+
+// openInputs prepares the inputs for reading. Standard input is copied to a
+// temporary file, since conversion reads every input twice; cleanup removes it.
+func (p *Pipeline) openInputs(names []string) (inputs []*input, cleanup func(), err error) {
+	var tempFiles []string
+	cleanup = func() {
+		for _, path := range tempFiles {
+			_ = os.Remove(path)
+		}
+	}
+	defer func() {
+		if err != nil {
+			cleanup()
+		}
+	}()
+
+	readStdin := false
+	for _, name := range names {
+		in := &input{name: name, path: name}
+		if name == Stdin {
+			if readStdin {
+				return nil, cleanup, fmt.Errorf("standard input can only be read once")
+			}
+			readStdin = true
+			if in.path, err = spool(p.stdin); err != nil {
+				return nil, cleanup, err
+			}
+			in.name = "standard input"
+			tempFiles = append(tempFiles, in.path)
+		}
+
+		if err := p.detectInput(in); err != nil {
+			return nil, cleanup, err
+		}
+		inputs = append(inputs, in)
+	}
+
+	return inputs, cleanup, nil
+}
+
+// spool copies a reader to a temporary file and returns its path
+func spool(r io.Reader) (path string, err error) {
+	file, err := os.CreateTemp("", "jira-export-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to buffer standard input: %w", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to buffer standard input: %w", cerr)
+		}
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	if _, err := io.Copy(file, r); err != nil {
+		return "", fmt.Errorf("failed to buffer standard input: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// detectInput detects the format of an input, and parses it if it is CSV or XML
+func (p *Pipeline) detectInput(in *input) error {
+	reader, err := in.open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	buffered := bufio.NewReader(reader)
+	prefix, err := buffered.Peek(512)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read %s: %w", in.name, err)
+	}
+	in.format = jira.DetectFormat(prefix)
+	if in.format == jira.FormatJSON {
+		return nil
+	}
+
+	data, err := io.ReadAll(buffered)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", in.name, err)
+	}
+	if in.export, err = p.jiraAdapter.ParseAny(data); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", in.name, err)
+	}
+	in.diagnostics = p.jiraAdapter.Diagnostics()
+	return nil
+}
+
+// open opens an input, decompressing it if it starts with a gzip or zstd header
+func (in *input) open() (io.ReadCloser, error) {
+	file, err := os.Open(in.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", in.name, err)
+	}
+
+	buffered := bufio.NewReader(file)
+	magic, _ := buffered.Peek(4)
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to decompress %s: %w", in.name, err)
+		}
+		return readCloser{gz, func() error { _ = gz.Close(); return file.Close() }}, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(buffered)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to decompress %s: %w", in.name, err)
+		}
+		return readCloser{zr, func() error { zr.Close(); return file.Close() }}, nil
+	}
+
+	return readCloser{buffered, file.Close}, nil
+}
+
+// eachIssue calls fn for every issue of an input, in order
+func (p *Pipeline) eachIssue(in *input, fn func(*jirapb.Issue) error) error {
+	if p.issueHook != nil {
+		next := fn
+		fn = func(issue *jirapb.Issue) error {
+			if err := p.issueHook(issue); err != nil {
+				return fmt.Errorf("failed to prepare issue %s: %w", issue.Key, err)
+			}
+			return next(issue)
+		}
+	}
+
+	if in.export != nil {
+		for _, issue := range in.export.Issues {
+			if err := fn(issue); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	reader, err := in.open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	decoder := p.jiraAdapter.NewIssueDecoder(reader)
+	for {
+		issue, err := decoder.Next()
+		if err == io.EOF {
+			in.diagnostics = decoder.Diagnostics()
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", in.name, err)
+		}
+		if err := fn(issue); err != nil {
+			return err
+		}
+	}
+}
+
+// readCloser pairs a reader with the function closing it and what it wraps
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error {
+	return r.close()
+}
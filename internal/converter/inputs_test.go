@@ -0,0 +1,274 @@
+package converter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// jsonExport builds a Jira JSON export of issues given as
+// key, type, summary and updated date, with optional raw extra fields
+func jsonExport(issues ...[5]string) string {
+	var parts []string
+	for i, issue := range issues {
+		extra := ""
+		if issue[4] != "" {
+			extra = ", " + issue[4]
+		}
+		parts = append(parts, fmt.Sprintf(`{"id": "%d", "key": %q, "fields": {"summary": %q, "issuetype": {"name": %q}, "updated": "%sT10:00:00.000+0000"%s}}`,
+			10001+i, issue[0], issue[2], issue[1], issue[3], extra))
+	}
+	return `{"issues": [` + strings.Join(parts, ",") + `]}`
+}
+
+func writeFile(t *testing.T, path string, data []byte) string {
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+func gzipData(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func zstdData(t *testing.T, data string) []byte {
+	w, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatalf("Failed to create zstd writer: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+	return w.EncodeAll([]byte(data), nil)
+}
+
+// readBeads reads a beads JSONL file of the output directory
+func readBeads(t *testing.T, dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, ".beads", name))
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+const blockedBy = `"issuelinks": [{"type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}, "inwardIssue": {"key": "PROJ-4"}}],
+	"parent": {"key": "PROJ-1", "fields": {"issuetype": {"name": "Epic"}}}`
+
+func TestPipelineConvertFilesMergesInputs(t *testing.T) {
+	inputDir := t.TempDir()
+	first := writeFile(t, filepath.Join(inputDir, "export-1.json"), []byte(jsonExport(
+		[5]string{"PROJ-1", "Epic", "Authentication", "2024-01-01", ""},
+		[5]string{"PROJ-2", "Story", "Old login title", "2024-01-02", ""},
+	)))
+	// The second part is compressed, and has a newer copy of PROJ-2 linking
+	// to issues of both files
+	second := writeFile(t, filepath.Join(inputDir, "export-2.json.gz"), gzipData(t, jsonExport(
+		[5]string{"PROJ-2", "Story", "Create login API endpoint", "2024-01-05", blockedBy},
+		[5]string{"PROJ-4", "Task", "Setup database schema", "2024-01-03", ""},
+	)))
+
+	outputDir := t.TempDir()
+	if err := NewPipeline(outputDir).ConvertFiles([]string{first, second}); err != nil {
+		t.Fatalf("ConvertFiles failed: %v", err)
+	}
+
+	issues := readBeads(t, outputDir, "issues.jsonl")
+	if n := strings.Count(issues, `"jiraKey":"PROJ-2"`); n != 1 {
+		t.Errorf("Expected PROJ-2 once, got %d times:\n%s", n, issues)
+	}
+	for _, field := range []string{
+		`"title":"Create login API endpoint"`,
+		`"epic":"proj-1"`,
+		`"dependsOn":["proj-4"]`,
+		`"jiraKey":"PROJ-4"`,
+	} {
+		if !strings.Contains(issues, field) {
+			t.Errorf("Expected %s in issues.jsonl:\n%s", field, issues)
+		}
+	}
+	if strings.Contains(issues, "Old login title") {
+		t.Errorf("Expected the older copy of PROJ-2 to be dropped:\n%s", issues)
+	}
+	if epics := readBeads(t, outputDir, "epics.jsonl"); !strings.Contains(epics, `"jiraKey":"PROJ-1"`) {
+		t.Errorf("Expected epic PROJ-1:\n%s", epics)
+	}
+}
+
+func TestPipelineConvertFilesTieKeepsLaterInput(t *testing.T) {
+	inputDir := t.TempDir()
+	first := writeFile(t, filepath.Join(inputDir, "a.json"), []byte(jsonExport(
+		[5]string{"PROJ-1", "Task", "First copy", "2024-01-01", ""},
+	)))
+	second := writeFile(t, filepath.Join(inputDir, "b.json"), []byte(jsonExport(
+		[5]string{"PROJ-1", "Task", "Second copy", "2024-01-01", ""},
+	)))
+
+	outputDir := t.TempDir()
+	if err := NewPipeline(outputDir).ConvertFiles([]string{first, second}); err != nil {
+		t.Fatalf("ConvertFiles failed: %v", err)
+	}
+
+	issues := readBeads(t, outputDir, "issues.jsonl")
+	if !strings.Contains(issues, "Second copy") || strings.Contains(issues, "First copy") {
+		t.Errorf("Expected only the second copy:\n%s", issues)
+	}
+}
+
+func TestPipelineConvertFilesStdinAndZstd(t *testing.T) {
+	inputDir := t.TempDir()
+	compressed := writeFile(t, filepath.Join(inputDir, "epic.json.zst"), zstdData(t, jsonExport(
+		[5]string{"PROJ-1", "Epic", "Authentication", "2024-01-01", ""},
+	)))
+
+	outputDir := t.TempDir()
+	pipeline := NewPipeline(outputDir)
+	pipeline.stdin = strings.NewReader(jsonExport(
+		[5]string{"PROJ-2", "Story", "Create login API endpoint", "2024-01-02", blockedBy},
+	))
+
+	if err := pipeline.ConvertFiles([]string{Stdin, compressed}); err != nil {
+		t.Fatalf("ConvertFiles failed: %v", err)
+	}
+
+	issues := readBeads(t, outputDir, "issues.jsonl")
+	if !strings.Contains(issues, `"epic":"proj-1"`) {
+		t.Errorf("Expected the issue from stdin to link the epic from the zstd file:\n%s", issues)
+	}
+}
+
+func TestPipelineConvertFilesMixedFormats(t *testing.T) {
+	// A CSV export refers to its parent by ID, which is in the JSON export
+	inputDir := t.TempDir()
+	epics := writeFile(t, filepath.Join(inputDir, "epics.json"), []byte(jsonExport(
+		[5]string{"PROJ-1", "Epic", "Authentication", "2024-01-01", ""},
+	)))
+	stories := writeFile(t, filepath.Join(inputDir, "stories.csv"), []byte(
+		"Summary,Issue key,Issue id,Parent,Issue Type,Status\n"+
+			"Create login API endpoint,PROJ-2,10002,10001,Story,To Do\n"))
+
+	outputDir := t.TempDir()
+	if err := NewPipeline(outputDir).ConvertFiles([]string{stories, epics}); err != nil {
+		t.Fatalf("ConvertFiles failed: %v", err)
+	}
+
+	issues := readBeads(t, outputDir, "issues.jsonl")
+	if !strings.Contains(issues, `"epic":"proj-1"`) {
+		t.Errorf("Expected the CSV issue to link the epic from the JSON file:\n%s", issues)
+	}
+}
+
+func TestPipelineConvertFilesSingleIssue(t *testing.T) {
+	// A response of /issue/{key} saved as it is, next to a search page
+	inputDir := t.TempDir()
+	epic := writeFile(t, filepath.Join(inputDir, "PROJ-1.json"), []byte(
+		`{"expand": "renderedFields", "id": "10001", "key": "PROJ-1", "fields": {"summary": "Authentication", "issuetype": {"name": "Epic"}}}`))
+	page := writeFile(t, filepath.Join(inputDir, "search.json"), []byte(
+		`{"startAt": 0, "maxResults": 50, "total": 1, "issues": [{"id": "10002", "key": "PROJ-2", "fields": {"summary": "Create login API endpoint", "issuetype": {"name": "Story"}, `+
+			`"parent": {"key": "PROJ-1", "fields": {"issuetype": {"name": "Epic"}}}}}]}`))
+
+	outputDir := t.TempDir()
+	if err := NewPipeline(outputDir).ConvertFiles([]string{epic, page}); err != nil {
+		t.Fatalf("ConvertFiles failed: %v", err)
+	}
+
+	if epics := readBeads(t, outputDir, "epics.jsonl"); !strings.Contains(epics, `"jiraKey":"PROJ-1"`) {
+		t.Errorf("Expected epic PROJ-1 from the single issue:\n%s", epics)
+	}
+	if issues := readBeads(t, outputDir, "issues.jsonl"); !strings.Contains(issues, `"epic":"proj-1"`) {
+		t.Errorf("Expected PROJ-2 to link the epic:\n%s", issues)
+	}
+}
+
+func TestPipelineConvertFilesLenient(t *testing.T) {
+	inputDir := t.TempDir()
+	export := writeFile(t, filepath.Join(inputDir, "export.json"), []byte(`{"issues": [
+		{"key": "PROJ-1", "fields": {"summary": "Good", "issuetype": {"name": "Task"}}},
+		{"key": "PROJ-2", "fields": {"issuetype": {"name": "Task"}, "created": "yesterday"}}]}`))
+
+	outputDir := t.TempDir()
+	pipeline := NewPipeline(outputDir)
+	if err := pipeline.ConvertFiles([]string{export}); err == nil {
+		t.Fatal("Expected strict conversion to fail")
+	}
+
+	pipeline.SetLenient(true)
+	if err := pipeline.ConvertFiles([]string{export}); err != nil {
+		t.Fatalf("ConvertFiles failed: %v", err)
+	}
+
+	if issues := readBeads(t, outputDir, "issues.jsonl"); !strings.Contains(issues, `"title":"PROJ-2"`) {
+		t.Errorf("Expected PROJ-2 titled with its key:\n%s", issues)
+	}
+	// Both passes read the export, but each problem is reported once
+	diagnostics := pipeline.Diagnostics()
+	if len(diagnostics) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %v", diagnostics)
+	}
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Source != export || diagnostic.Key != "PROJ-2" {
+			t.Errorf("Expected a diagnostic of PROJ-2 in %s, got %+v", export, diagnostic)
+		}
+	}
+}
+
+func TestPipelineConvertFilesErrors(t *testing.T) {
+	pipeline := NewPipeline(t.TempDir())
+	pipeline.stdin = strings.NewReader(`{"issues": []}`)
+
+	tests := []struct {
+		name     string
+		inputs   []string
+		errorMsg string
+	}{
+		{"no inputs", nil, "no Jira export files given"},
+		{"missing file", []string{"nonexistent.json"}, "failed to read file nonexistent.json"},
+		{"stdin twice", []string{Stdin, Stdin}, "standard input can only be read once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pipeline.ConvertFiles(tt.inputs)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestExpandInputs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"export-1.json", "export-2.json.gz", "notes.txt"} {
+		writeFile(t, filepath.Join(dir, name), []byte("{}"))
+	}
+
+	got, err := ExpandInputs([]string{filepath.Join(dir, "export-*"), Stdin, "plain.json"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, "export-1.json"),
+		filepath.Join(dir, "export-2.json.gz"),
+		Stdin,
+		"plain.json",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if _, err := ExpandInputs([]string{filepath.Join(dir, "missing-*.json")}); err == nil || !strings.Contains(err.Error(), "no files match") {
+		t.Errorf("Expected an error for a pattern without matches, got: %v", err)
+	}
+}
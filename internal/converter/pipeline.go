@@ -2,45 +2,244 @@ package converter
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"time"
 
+	beadspb "github.com/conallob/jira-beads-sync/gen/beads"
+	jirapb "github.com/conallob/jira-beads-sync/gen/jira"
 	"github.com/conallob/jira-beads-sync/internal/beads"
 	"github.com/conallob/jira-beads-sync/internal/jira"
 )
 
-// Pipeline orchestrates the full conversion from Jira JSON to beads JSONL
+// Pipeline orchestrates the full conversion from a Jira JSON, CSV or XML export to beads JSONL
 type Pipeline struct {
 	jiraAdapter   *jira.Adapter
 	converter     *ProtoConverter
 	jsonlRenderer *beads.JSONLRenderer
+	stdin         io.Reader // read for the input "-"
+	diagnostics   []jira.Diagnostic
+	issueHook     func(*jirapb.Issue) error // called with every issue read
+	sprint        *jirapb.Sprint            // summarised in sprint.json when set
 }
 
-// NewPipeline creates a new conversion pipeline
+// NewPipeline creates a new conversion pipeline with default options
 func NewPipeline(outputDir string) *Pipeline {
+	return NewPipelineWithOptions(outputDir, DefaultOptions())
+}
+
+// NewPipelineWithOptions creates a new conversion pipeline
+func NewPipelineWithOptions(outputDir string, options Options) *Pipeline {
+	jsonlRenderer := beads.NewJSONLRenderer(outputDir)
+	jsonlRenderer.SetLocation(options.Location)
+	// CSV exports have no zone; they are taken to be in the team's timezone
+	jiraAdapter := jira.NewAdapter()
+	jiraAdapter.SetLocation(options.Location)
+
 	return &Pipeline{
-		jiraAdapter:   jira.NewAdapter(),
-		converter:     NewProtoConverter(),
-		jsonlRenderer: beads.NewJSONLRenderer(outputDir),
+		jiraAdapter:   jiraAdapter,
+		converter:     NewProtoConverterWithOptions(options),
+		jsonlRenderer: jsonlRenderer,
+		stdin:         os.Stdin,
 	}
 }
 
-// ConvertFile converts a Jira JSON export file to beads JSONL files
+// SetLenient sets whether conversion skips or repairs bad issues instead of
+// failing; see jira.Adapter.SetLenient
+func (p *Pipeline) SetLenient(lenient bool) {
+	p.jiraAdapter.SetLenient(lenient)
+}
+
+// SetRankField sets the ID of the custom field holding the board rank; see
+// jira.Adapter.SetRankField
+func (p *Pipeline) SetRankField(fieldID string) {
+	p.jiraAdapter.SetRankField(fieldID)
+}
+
+// SetIssueHook sets a function called with every issue as it is read from
+// the exports, before it is converted, e.g. to add data the export doesn't
+// hold. An error from the hook fails the conversion.
+func (p *Pipeline) SetIssueHook(hook func(*jirapb.Issue) error) {
+	p.issueHook = hook
+}
+
+// SetSprint sets the sprint the exports were fetched from. Conversion then
+// also writes sprint.json, summarising the sprint and the issues planned in it.
+func (p *Pipeline) SetSprint(sprint *jirapb.Sprint) {
+	p.sprint = sprint
+}
+
+// Diagnostics returns the problems lenient conversion skipped or repaired in
+// the exports of the last conversion
+func (p *Pipeline) Diagnostics() []jira.Diagnostic {
+	return p.diagnostics
+}
+
+// ConvertFile converts a Jira JSON, CSV or XML export file to beads JSONL files
 func (p *Pipeline) ConvertFile(jiraFile string) error {
-	// Step 1: Parse Jira JSON to protobuf
-	jiraExport, err := p.jiraAdapter.ParseFile(jiraFile)
+	return p.ConvertFiles([]string{jiraFile})
+}
+
+// ConvertFiles converts one or more Jira exports to beads JSONL files. Each
+// input is a JSON, CSV or XML export file, optionally gzip or zstd
+// compressed, or "-" for standard input. Issues are merged across inputs, so
+// links between them resolve; an issue exported more than once is converted
+// from its most recently updated copy.
+//
+// JSON exports are streamed in two passes: the first indexes the issues, the
+// second converts and renders each issue as it is read. Memory use is bounded
+// by the index rather than by the size of the exports, unless the converter
+// sorts issues by rank, which needs all of them converted first.
+func (p *Pipeline) ConvertFiles(names []string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("no Jira export files given")
+	}
+	p.diagnostics = nil
+
+	inputs, cleanup, err := p.openInputs(names)
+	defer cleanup()
 	if err != nil {
 		return fmt.Errorf("failed to parse Jira file: %w", err)
 	}
 
-	// Step 2: Convert Jira protobuf to beads protobuf
-	beadsExport, err := p.converter.Convert(jiraExport)
+	// Pass 1: index the issues, choosing which copy of each to convert
+	index, err := p.indexIssues(inputs)
 	if err != nil {
-		return fmt.Errorf("failed to convert to beads format: %w", err)
+		return fmt.Errorf("failed to parse Jira file: %w", err)
+	}
+	for _, in := range inputs {
+		for _, diagnostic := range in.diagnostics {
+			diagnostic.Source = in.name
+			p.diagnostics = append(p.diagnostics, diagnostic)
+		}
 	}
 
-	// Step 3: Render beads protobuf to JSONL files
-	if err := p.jsonlRenderer.RenderExport(beadsExport); err != nil {
+	// Pass 2: convert and render the chosen copy of each issue
+	stream, err := p.jsonlRenderer.NewStream()
+	if err != nil {
 		return fmt.Errorf("failed to render JSONL files: %w", err)
 	}
+	var ranked []*beadspb.Issue       // held back to be sorted
+	var sprintIssues []*beadspb.Issue // planned in the sprint set with SetSprint
+	for i, in := range inputs {
+		position := 0
+		err = p.eachIssue(in, func(jiraIssue *jirapb.Issue) error {
+			at := issuePosition{input: i, index: position}
+			position++
+			if index.chosen[jiraIssue.Key].issuePosition != at {
+				return nil
+			}
+			index.resolveParent(jiraIssue)
+
+			issue, epic, err := p.converter.ConvertOne(jiraIssue)
+			if err != nil {
+				return fmt.Errorf("failed to convert to beads format: %w", err)
+			}
+			switch {
+			case epic != nil:
+				err = stream.WriteEpic(epic)
+			case p.converter.options.SortByRank:
+				ranked = append(ranked, issue)
+			default:
+				err = stream.WriteIssue(issue)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to render JSONL files: %w", err)
+			}
+			if issue != nil && p.sprint != nil && issue.Sprint.GetId() == p.sprint.Id {
+				sprintIssues = append(sprintIssues, issue)
+			}
+			return nil
+		})
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		sortByRank(ranked)
+		for _, issue := range ranked {
+			if err = stream.WriteIssue(issue); err != nil {
+				err = fmt.Errorf("failed to render JSONL files: %w", err)
+				break
+			}
+		}
+	}
+	if cerr := stream.Close(); cerr != nil && err == nil {
+		err = fmt.Errorf("failed to render JSONL files: %w", cerr)
+	}
+	if err == nil && p.sprint != nil {
+		err = p.jsonlRenderer.RenderSprintSummary(p.converter.convertSprint(p.sprint), sprintIssues)
+	}
+	return err
+}
+
+// issuePosition locates an issue among the inputs of a conversion
+type issuePosition struct {
+	input int
+	index int
+}
+
+// indexedIssue is the copy of an issue chosen for conversion
+type indexedIssue struct {
+	issuePosition
+	updated time.Time
+}
+
+// issueIndex holds what converting one issue needs to know about the others
+type issueIndex struct {
+	chosen map[string]indexedIssue // by key
+	keys   map[string]string       // keys by issue ID
+}
 
-	return nil
+// indexIssues reads every input, registering epics with the converter and
+// choosing the most recently updated copy of each issue; the later input wins
+// a tie
+func (p *Pipeline) indexIssues(inputs []*input) (*issueIndex, error) {
+	index := &issueIndex{
+		chosen: make(map[string]indexedIssue),
+		keys:   make(map[string]string),
+	}
+
+	for i, in := range inputs {
+		position := 0
+		err := p.eachIssue(in, func(issue *jirapb.Issue) error {
+			candidate := indexedIssue{
+				issuePosition: issuePosition{input: i, index: position},
+				updated:       issue.Fields.Updated.AsTime(),
+			}
+			position++
+
+			if current, ok := index.chosen[issue.Key]; !ok || !candidate.updated.Before(current.updated) {
+				index.chosen[issue.Key] = candidate
+			}
+			if issue.Id != "" {
+				index.keys[issue.Id] = issue.Key
+			}
+			if issue.Fields.IssueType.Name == "Epic" {
+				p.converter.AddEpic(issue)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return index, nil
+}
+
+// resolveParent replaces a parent ID left unresolved by a CSV export with the
+// key of the parent, when the parent is in another input
+func (x *issueIndex) resolveParent(issue *jirapb.Issue) {
+	parent := issue.Fields.Parent
+	if parent == nil {
+		return
+	}
+	if _, ok := x.chosen[parent.Key]; ok {
+		return
+	}
+	if key, ok := x.keys[parent.Key]; ok {
+		parent.Id = parent.Key
+		parent.Key = key
+	}
 }
@@ -1,10 +1,15 @@
 package converter
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	jirapb "github.com/conallob/jira-beads-sync/gen/jira"
+	"github.com/conallob/jira-beads-sync/internal/beads"
+	"github.com/conallob/jira-beads-sync/internal/jira"
 )
 
 func TestNewPipeline(t *testing.T) {
@@ -176,3 +181,151 @@ func containsSubstring(s, substr string) bool {
 func splitLines(s string) []string {
 	return strings.Split(strings.TrimSpace(s), "\n")
 }
+
+func TestPipelineConvertCSVAndXMLFiles(t *testing.T) {
+	for _, file := range []string{
+		"../../testdata/sample-jira-export.csv",
+		"../../testdata/sample-jira-export.xml",
+	} {
+		t.Run(filepath.Ext(file), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			pipeline := NewPipeline(tmpDir)
+
+			if err := pipeline.ConvertFile(file); err != nil {
+				t.Fatalf("ConvertFile failed: %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(tmpDir, ".beads", "issues.jsonl"))
+			if err != nil {
+				t.Fatalf("Failed to read issues.jsonl: %v", err)
+			}
+
+			// The samples hold the same issues as the JSON one
+			for _, field := range []string{
+				`"id":"proj-2"`,
+				`"title":"Create login API endpoint"`,
+				`"status":"open"`,
+				`"epic":"proj-1"`,
+				`"dependsOn":["proj-4"]`,
+				`"jiraKey":"PROJ-2"`,
+			} {
+				if !strings.Contains(string(content), field) {
+					t.Errorf("Expected field '%s' not found in issues.jsonl.\nContent:\n%s", field, content)
+				}
+			}
+		})
+	}
+}
+
+func TestPipelineStreamMatchesConvert(t *testing.T) {
+	const jiraFile = "../../testdata/sample-jira-export.json"
+
+	// Convert the whole export in memory for reference
+	export, err := jira.NewAdapter().ParseFile(jiraFile)
+	if err != nil {
+		t.Fatalf("Failed to parse sample: %v", err)
+	}
+	beadsExport, err := NewProtoConverter().Convert(export)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	wantDir := t.TempDir()
+	if err := beads.NewJSONLRenderer(wantDir).RenderExport(beadsExport); err != nil {
+		t.Fatalf("RenderExport failed: %v", err)
+	}
+
+	gotDir := t.TempDir()
+	if err := NewPipeline(gotDir).ConvertFile(jiraFile); err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+
+	for _, name := range []string{"issues.jsonl", "epics.jsonl"} {
+		want, err := os.ReadFile(filepath.Join(wantDir, ".beads", name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		got, err := os.ReadFile(filepath.Join(gotDir, ".beads", name))
+		if err != nil {
+			t.Fatalf("Failed to read streamed %s: %v", name, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("Streamed %s differs:\n%s\nwant:\n%s", name, got, want)
+		}
+	}
+}
+
+func TestPipelineConvertFileStreamErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		errorMsg string
+	}{
+		{"no issues", `{"issues": []}`, "export contains no issues"},
+		{"invalid issue", `{"issues": [{"key": "PROJ-1", "fields": {"issuetype": {"name": "Task"}}}]}`, "has no summary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			jiraFile := filepath.Join(tmpDir, "export.json")
+			if err := os.WriteFile(jiraFile, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write export: %v", err)
+			}
+
+			err := NewPipeline(tmpDir).ConvertFile(jiraFile)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestPipelineRankSprintAndIssueHook(t *testing.T) {
+	inputDir := t.TempDir()
+	rank := func(value string) string {
+		return `"customfield_10019": "` + value + `", "sprint": {"id": 42, "name": "Sprint 42"}`
+	}
+	input := writeFile(t, filepath.Join(inputDir, "export.json"), []byte(jsonExport(
+		[5]string{"PROJ-1", "Task", "Second", "2024-01-01", rank("0|i0002:")},
+		[5]string{"PROJ-2", "Task", "Unranked", "2024-01-01", ""},
+		[5]string{"PROJ-3", "Task", "First", "2024-01-01", rank("0|i0001:")},
+	)))
+
+	options := DefaultOptions()
+	options.SortByRank = true
+	outputDir := t.TempDir()
+	pipeline := NewPipelineWithOptions(outputDir, options)
+	pipeline.SetRankField("customfield_10019")
+	pipeline.SetSprint(&jirapb.Sprint{Id: 42, Name: "Sprint 42"})
+	pipeline.SetIssueHook(func(issue *jirapb.Issue) error {
+		issue.Repositories = []string{"https://github.com/acme/" + strings.ToLower(issue.Key)}
+		return nil
+	})
+
+	if err := pipeline.ConvertFile(input); err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+
+	var keys []string
+	for _, line := range splitLines(strings.TrimSpace(readBeads(t, outputDir, "issues.jsonl"))) {
+		var issue beads.BeadsIssue
+		if err := json.Unmarshal([]byte(line), &issue); err != nil {
+			t.Fatalf("Failed to parse issue: %v", err)
+		}
+		keys = append(keys, issue.Metadata.JiraKey)
+		if len(issue.Metadata.Repositories) != 1 {
+			t.Errorf("Expected the hook's repository on %s, got %v", issue.Metadata.JiraKey, issue.Metadata.Repositories)
+		}
+	}
+	if got := strings.Join(keys, ","); got != "PROJ-3,PROJ-1,PROJ-2" {
+		t.Errorf("Expected issues in rank order, got %s", got)
+	}
+
+	var summary beads.SprintSummary
+	if err := json.Unmarshal([]byte(readBeads(t, outputDir, "sprint.json")), &summary); err != nil {
+		t.Fatalf("Failed to parse sprint summary: %v", err)
+	}
+	if summary.ID != 42 || strings.Join(summary.Issues, ",") != "proj-1,proj-3" {
+		t.Errorf("Expected sprint 42 planning proj-1 and proj-3, got %+v", summary)
+	}
+}
@@ -2,23 +2,66 @@ package converter
 
 import (
 	"fmt"
+	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	beadspb "github.com/conallob/jira-beads-sync/gen/beads"
 	jirapb "github.com/conallob/jira-beads-sync/gen/jira"
 )
 
+// FieldTarget controls where a multi-valued Jira field ends up on a beads issue
+type FieldTarget string
+
+const (
+	// TargetLabels maps each value to a namespaced label, e.g. "fixVersion:2.4"
+	TargetLabels FieldTarget = "labels"
+	// TargetFields maps the values to the dedicated beads issue field
+	TargetFields FieldTarget = "fields"
+)
+
+// Options configures how Jira fields are mapped onto beads issues
+type Options struct {
+	FixVersions     FieldTarget
+	AffectsVersions FieldTarget
+	Components      FieldTarget
+
+	// SortByRank orders issues by their Jira board rank; unranked issues keep
+	// their original order after the ranked ones
+	SortByRank bool
+
+	// Location is the zone timestamps are rendered in; nil means UTC
+	Location *time.Location
+}
+
+// DefaultOptions returns the default field mapping options
+func DefaultOptions() Options {
+	return Options{
+		FixVersions:     TargetLabels,
+		AffectsVersions: TargetLabels,
+		Components:      TargetLabels,
+	}
+}
+
 // ProtoConverter handles converting Jira protobuf to beads protobuf
 type ProtoConverter struct {
 	issueMap map[string]*jirapb.Issue // Map of Jira keys to issues
 	epicMap  map[string]string        // Map of Jira epic keys to beads epic IDs
+	options  Options
 }
 
-// NewProtoConverter creates a new protobuf-based converter
+// NewProtoConverter creates a new protobuf-based converter with default options
 func NewProtoConverter() *ProtoConverter {
+	return NewProtoConverterWithOptions(DefaultOptions())
+}
+
+// NewProtoConverterWithOptions creates a new protobuf-based converter
+func NewProtoConverterWithOptions(options Options) *ProtoConverter {
 	return &ProtoConverter{
 		issueMap: make(map[string]*jirapb.Issue),
 		epicMap:  make(map[string]string),
+		options:  options,
 	}
 }
 
@@ -34,6 +77,7 @@ func (c *ProtoConverter) Convert(jiraExport *jirapb.Export) (*beadspb.Export, er
 	beadsExport := &beadspb.Export{
 		Issues: []*beadspb.Issue{},
 		Epics:  []*beadspb.Epic{},
+		Sprint: c.convertSprint(jiraExport.Sprint),
 	}
 
 	// Convert epics first so we can reference them in issues
@@ -66,25 +110,69 @@ func (c *ProtoConverter) Convert(jiraExport *jirapb.Export) (*beadspb.Export, er
 		return nil, fmt.Errorf("failed to add dependencies: %w", err)
 	}
 
+	if c.options.SortByRank {
+		sortByRank(beadsExport.Issues)
+	}
+
 	return beadsExport, nil
 }
 
+// AddEpic registers an epic for ConvertOne, so issues converted before or
+// after it can reference it
+func (c *ProtoConverter) AddEpic(jiraIssue *jirapb.Issue) {
+	c.epicMap[jiraIssue.Key] = c.generateBeadsID(jiraIssue.Key)
+}
+
+// ConvertOne converts a single Jira issue to a beads epic or issue, for
+// exports streamed one issue at a time. Epics of the export must have been
+// registered with AddEpic first. Exactly one of the results is non-nil.
+func (c *ProtoConverter) ConvertOne(jiraIssue *jirapb.Issue) (*beadspb.Issue, *beadspb.Epic, error) {
+	if jiraIssue.Fields.IssueType.Name == "Epic" {
+		epic, err := c.convertEpic(jiraIssue)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert epic %s: %w", jiraIssue.Key, err)
+		}
+		return nil, epic, nil
+	}
+
+	issue, err := c.convertIssue(jiraIssue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert issue %s: %w", jiraIssue.Key, err)
+	}
+	for _, depKey := range issueDependencies(jiraIssue) {
+		depBeadsID := c.generateBeadsID(depKey)
+		if !contains(issue.DependsOn, depBeadsID) {
+			issue.DependsOn = append(issue.DependsOn, depBeadsID)
+		}
+	}
+	return issue, nil, nil
+}
+
 // convertEpic converts a Jira epic to a beads epic
 func (c *ProtoConverter) convertEpic(jiraIssue *jirapb.Issue) (*beadspb.Epic, error) {
 	epic := &beadspb.Epic{
 		Id:          c.generateBeadsID(jiraIssue.Key),
 		Name:        jiraIssue.Fields.Summary,
 		Description: jiraIssue.Fields.Description,
-		Status:      c.mapStatus(jiraIssue.Fields.Status),
+		Status:      c.mapIssueStatus(jiraIssue.Fields),
 		Created:     jiraIssue.Fields.Created,
 		Updated:     jiraIssue.Fields.Updated,
+		Due:         jiraIssue.Fields.DueDate,
+		Resolution:  c.mapResolution(jiraIssue.Fields.Resolution),
+		Resolved:    jiraIssue.Fields.ResolutionDate,
+		Labels:      append([]string(nil), jiraIssue.Fields.Labels...),
 		Metadata: &beadspb.Metadata{
 			JiraKey:       jiraIssue.Key,
 			JiraId:        jiraIssue.Id,
 			JiraIssueType: jiraIssue.Fields.IssueType.Name,
+			Repositories:  repositories(jiraIssue),
+			PullRequests:  pullRequests(jiraIssue.PullRequests),
 		},
 	}
 
+	// Map fix versions, affects versions and components
+	epic.Labels, epic.FixVersions, epic.AffectsVersions, epic.Components = c.mapVersionsAndComponents(jiraIssue.Fields, epic.Labels)
+
 	return epic, nil
 }
 
@@ -94,19 +182,29 @@ func (c *ProtoConverter) convertIssue(jiraIssue *jirapb.Issue) (*beadspb.Issue,
 		Id:          c.generateBeadsID(jiraIssue.Key),
 		Title:       jiraIssue.Fields.Summary,
 		Description: jiraIssue.Fields.Description,
-		Status:      c.mapStatus(jiraIssue.Fields.Status),
+		Status:      c.mapIssueStatus(jiraIssue.Fields),
 		Priority:    c.mapPriority(jiraIssue.Fields.Priority),
-		Labels:      jiraIssue.Fields.Labels,
+		Labels:      append([]string(nil), jiraIssue.Fields.Labels...),
 		DependsOn:   []string{},
 		Created:     jiraIssue.Fields.Created,
 		Updated:     jiraIssue.Fields.Updated,
+		Due:         jiraIssue.Fields.DueDate,
+		Resolution:  c.mapResolution(jiraIssue.Fields.Resolution),
+		Resolved:    jiraIssue.Fields.ResolutionDate,
+		Sprint:      c.convertSprint(jiraIssue.Fields.Sprint),
+		Rank:        jiraIssue.Fields.Rank,
 		Metadata: &beadspb.Metadata{
 			JiraKey:       jiraIssue.Key,
 			JiraId:        jiraIssue.Id,
 			JiraIssueType: jiraIssue.Fields.IssueType.Name,
+			Repositories:  repositories(jiraIssue),
+			PullRequests:  pullRequests(jiraIssue.PullRequests),
 		},
 	}
 
+	// Map fix versions, affects versions and components
+	issue.Labels, issue.FixVersions, issue.AffectsVersions, issue.Components = c.mapVersionsAndComponents(jiraIssue.Fields, issue.Labels)
+
 	// Set assignee if present
 	if jiraIssue.Fields.Assignee != nil {
 		issue.Assignee = jiraIssue.Fields.Assignee.EmailAddress
@@ -115,21 +213,19 @@ func (c *ProtoConverter) convertIssue(jiraIssue *jirapb.Issue) (*beadspb.Issue,
 		}
 	}
 
-	// Link to epic if this issue belongs to one
-	if jiraIssue.Fields.Parent != nil {
-		// Check if parent is an epic
-		if jiraIssue.Fields.Parent.Fields.IssueType.Name == "Epic" {
-			if epicID, exists := c.epicMap[jiraIssue.Fields.Parent.Key]; exists {
-				issue.Epic = epicID
-			}
-		}
+	// Link to epic if this issue belongs to one. Only epics of the export are
+	// in epicMap, and parents from CSV and XML exports may lack their type.
+	parent := jiraIssue.Fields.Parent
+	epicID, parentIsEpic := c.epicMap[parent.GetKey()]
+	if parent != nil && parentIsEpic {
+		issue.Epic = epicID
 	}
 
 	// Handle dependencies from parent-child relationships
-	if jiraIssue.Fields.Parent != nil && jiraIssue.Fields.IssueType.Subtask {
+	if parent != nil && jiraIssue.Fields.IssueType.Subtask {
 		// Subtasks depend on their parent (unless parent is an epic)
-		if jiraIssue.Fields.Parent.Fields.IssueType.Name != "Epic" {
-			parentBeadsID := c.generateBeadsID(jiraIssue.Fields.Parent.Key)
+		if !parentIsEpic && parent.GetFields().GetIssueType().GetName() != "Epic" {
+			parentBeadsID := c.generateBeadsID(parent.Key)
 			issue.DependsOn = append(issue.DependsOn, parentBeadsID)
 		}
 	}
@@ -137,6 +233,24 @@ func (c *ProtoConverter) convertIssue(jiraIssue *jirapb.Issue) (*beadspb.Issue,
 	return issue, nil
 }
 
+// convertSprint converts a Jira sprint to a beads sprint
+func (c *ProtoConverter) convertSprint(jiraSprint *jirapb.Sprint) *beadspb.Sprint {
+	if jiraSprint == nil {
+		return nil
+	}
+
+	return &beadspb.Sprint{
+		Id:        jiraSprint.Id,
+		Name:      jiraSprint.Name,
+		State:     jiraSprint.State,
+		Goal:      jiraSprint.Goal,
+		Start:     jiraSprint.StartDate,
+		End:       jiraSprint.EndDate,
+		Completed: jiraSprint.CompleteDate,
+		BoardId:   jiraSprint.OriginBoardId,
+	}
+}
+
 // addDependencies adds dependency relationships from Jira issue links
 func (c *ProtoConverter) addDependencies(jiraExport *jirapb.Export, beadsExport *beadspb.Export) error {
 	// Get dependencies from Jira
@@ -199,6 +313,62 @@ func (c *ProtoConverter) mapStatus(jiraStatus *jirapb.Status) beadspb.Status {
 	}
 }
 
+// mapIssueStatus maps a Jira issue's status and resolution to beads status.
+// Jira sets a resolution once an issue is finished, so a resolved issue is
+// closed even if its workflow status sits outside the "done" category. A
+// status in the "new" or "indeterminate" category still wins, as workflows
+// that reopen issues don't always clear their resolution.
+func (c *ProtoConverter) mapIssueStatus(fields *jirapb.Fields) beadspb.Status {
+	status := c.mapStatus(fields.Status)
+	if !isResolved(fields.Resolution) {
+		return status
+	}
+	if fields.Status != nil && fields.Status.StatusCategory != nil {
+		switch fields.Status.StatusCategory.Key {
+		case "new", "indeterminate":
+			return status
+		}
+	}
+	return beadspb.Status_STATUS_CLOSED
+}
+
+// isResolved reports whether an issue has a resolution. Some workflows set
+// a resolution named "Unresolved" or "None" instead of leaving it empty.
+func isResolved(resolution *jirapb.Resolution) bool {
+	if resolution == nil {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(resolution.Name)) {
+	case "", "unresolved", "none":
+		return false
+	}
+	return true
+}
+
+// mapResolution normalises a Jira resolution name to a beads close reason
+// Converts "Won't Do" to "wontfix", "Cannot Reproduce" to "cannot_reproduce", etc.
+func (c *ProtoConverter) mapResolution(resolution *jirapb.Resolution) string {
+	if !isResolved(resolution) {
+		return ""
+	}
+
+	name := strings.ToLower(strings.TrimSpace(resolution.Name))
+	name = strings.NewReplacer("'", "", "’", "").Replace(name)
+
+	switch name {
+	case "done", "fixed", "resolved", "complete", "completed":
+		return "fixed"
+	case "wont do", "wont fix", "wontfix", "wont implement", "declined", "rejected":
+		return "wontfix"
+	case "duplicate":
+		return "duplicate"
+	case "cannot reproduce", "cant reproduce", "cannot reproduce bug":
+		return "cannot_reproduce"
+	default:
+		return strings.Join(strings.Fields(name), "_")
+	}
+}
+
 // mapPriority maps Jira priority to beads priority
 func (c *ProtoConverter) mapPriority(jiraPriority *jirapb.Priority) beadspb.Priority {
 	if jiraPriority == nil {
@@ -224,6 +394,166 @@ func (c *ProtoConverter) mapPriority(jiraPriority *jirapb.Priority) beadspb.Prio
 	}
 }
 
+// mapVersionsAndComponents maps fix versions, affects versions and components
+// to their own fields or to namespaced labels, as configured. It returns labels
+// with any namespaced labels appended, and the values mapped to fields.
+func (c *ProtoConverter) mapVersionsAndComponents(fields *jirapb.Fields, labels []string) (outLabels, fixVersions, affectsVersions, components []string) {
+	outLabels = labels
+
+	if names := versionNames(fields.FixVersions); c.options.FixVersions == TargetFields {
+		fixVersions = names
+	} else {
+		outLabels = appendNamespacedLabels(outLabels, "fixVersion", names)
+	}
+
+	if names := versionNames(fields.Versions); c.options.AffectsVersions == TargetFields {
+		affectsVersions = names
+	} else {
+		outLabels = appendNamespacedLabels(outLabels, "affectsVersion", names)
+	}
+
+	if names := componentNames(fields.Components); c.options.Components == TargetFields {
+		components = names
+	} else {
+		outLabels = appendNamespacedLabels(outLabels, "component", names)
+	}
+
+	return outLabels, fixVersions, affectsVersions, components
+}
+
+// versionNames returns the names of the given Jira versions
+func versionNames(versions []*jirapb.Version) []string {
+	var names []string
+	for _, version := range versions {
+		if version.Name != "" {
+			names = append(names, version.Name)
+		}
+	}
+	return names
+}
+
+// componentNames returns the names of the given Jira components
+func componentNames(components []*jirapb.Component) []string {
+	var names []string
+	for _, component := range components {
+		if component.Name != "" {
+			names = append(names, component.Name)
+		}
+	}
+	return names
+}
+
+// repositories collects the repository URLs related to an issue, from its
+// development information and from remote links pointing into a repository
+func repositories(jiraIssue *jirapb.Issue) []string {
+	var repos []string
+	seen := make(map[string]bool)
+	add := func(repo string) {
+		if repo != "" && !seen[repo] {
+			seen[repo] = true
+			repos = append(repos, repo)
+		}
+	}
+
+	for _, repo := range jiraIssue.Repositories {
+		add(repo)
+	}
+	for _, pr := range jiraIssue.PullRequests {
+		add(pr.RepositoryUrl)
+	}
+	for _, link := range jiraIssue.RemoteLinks {
+		add(repositoryFromURL(link.Url))
+	}
+
+	return repos
+}
+
+// repositoryPathMarkers are the path segments that follow "owner/repo" in
+// GitHub, GitLab and Bitbucket Cloud URLs
+var repositoryPathMarkers = map[string]bool{
+	"pull":           true,
+	"pulls":          true,
+	"pull-requests":  true,
+	"merge_requests": true,
+	"tree":           true,
+	"blob":           true,
+	"commit":         true,
+	"commits":        true,
+	"compare":        true,
+	"branch":         true,
+	"src":            true,
+}
+
+// repositoryFromURL returns the repository URL of a link into a repository,
+// e.g. https://github.com/acme/api/pull/12 -> https://github.com/acme/api.
+// It returns an empty string for links that don't point into a repository.
+func repositoryFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	path := strings.Trim(u.Path, "/")
+
+	// GitLab separates the (possibly nested) project path with "/-/"
+	if idx := strings.Index(path, "/-/"); idx > 0 {
+		return fmt.Sprintf("%s://%s/%s", u.Scheme, u.Host, path[:idx])
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) >= 3 && repositoryPathMarkers[parts[2]] {
+		return fmt.Sprintf("%s://%s/%s/%s", u.Scheme, u.Host, parts[0], parts[1])
+	}
+
+	return ""
+}
+
+// pullRequests converts Jira development pull requests to beads pull requests
+func pullRequests(jiraPullRequests []*jirapb.PullRequest) []*beadspb.PullRequest {
+	var prs []*beadspb.PullRequest
+	for _, pr := range jiraPullRequests {
+		prs = append(prs, &beadspb.PullRequest{
+			Url:        pr.Url,
+			Title:      pr.Name,
+			State:      strings.ToLower(pr.Status),
+			Repository: pr.RepositoryUrl,
+		})
+	}
+	return prs
+}
+
+// appendNamespacedLabels appends "namespace:value" labels, skipping duplicates
+func appendNamespacedLabels(labels []string, namespace string, values []string) []string {
+	for _, value := range values {
+		label := namespace + ":" + value
+		if !contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// sortByRank stable-sorts issues by rank, placing unranked issues last.
+// Lexorank values ("0|i0000r:") sort lexicographically in board order after
+// their bucket prefix, which Jira rotates between when it rebalances ranks.
+func sortByRank(issues []*beadspb.Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		ri, rj := issues[i].Rank, issues[j].Rank
+		if ri == "" || rj == "" {
+			return ri != "" && rj == ""
+		}
+		return rankValue(ri) < rankValue(rj)
+	})
+}
+
+// rankValue returns a Lexorank value without its "bucket|" prefix
+func rankValue(rank string) string {
+	if _, value, found := strings.Cut(rank, "|"); found {
+		return value
+	}
+	return rank
+}
+
 // generateBeadsID generates a beads-friendly ID from a Jira key
 // Converts "PROJ-123" to "proj-123"
 func (c *ProtoConverter) generateBeadsID(jiraKey string) string {
@@ -255,21 +585,27 @@ func (c *ProtoConverter) getDependencies(export *jirapb.Export) map[string][]str
 	dependencies := make(map[string][]string)
 
 	for _, issue := range export.Issues {
-		var deps []string
-		for _, link := range issue.Fields.IssueLinks {
-			// Check if this issue is blocked by another
-			if link.Type.Inward == "is blocked by" && link.InwardIssue != nil {
-				deps = append(deps, link.InwardIssue.Key)
-			}
-			// Check if this issue depends on another
-			if link.Type.Outward == "depends on" && link.OutwardIssue != nil {
-				deps = append(deps, link.OutwardIssue.Key)
-			}
-		}
-		if len(deps) > 0 {
+		if deps := issueDependencies(issue); len(deps) > 0 {
 			dependencies[issue.Key] = deps
 		}
 	}
 
 	return dependencies
 }
+
+// issueDependencies returns the keys of the issues an issue depends on
+// according to its links
+func issueDependencies(issue *jirapb.Issue) []string {
+	var deps []string
+	for _, link := range issue.Fields.IssueLinks {
+		// Check if this issue is blocked by another
+		if link.Type.Inward == "is blocked by" && link.InwardIssue != nil {
+			deps = append(deps, link.InwardIssue.Key)
+		}
+		// Check if this issue depends on another
+		if link.Type.Outward == "depends on" && link.OutwardIssue != nil {
+			deps = append(deps, link.OutwardIssue.Key)
+		}
+	}
+	return deps
+}
@@ -5,6 +5,8 @@ import (
 
 	beadspb "github.com/conallob/jira-beads-sync/gen/beads"
 	jirapb "github.com/conallob/jira-beads-sync/gen/jira"
+	"github.com/conallob/jira-beads-sync/internal/jira"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -153,6 +155,241 @@ func TestProtoMapStatus(t *testing.T) {
 	}
 }
 
+func TestProtoMapResolution(t *testing.T) {
+	conv := NewProtoConverter()
+
+	tests := []struct {
+		name       string
+		resolution *jirapb.Resolution
+		want       string
+	}{
+		{name: "nil resolution", resolution: nil, want: ""},
+		{name: "empty name", resolution: &jirapb.Resolution{}, want: ""},
+		{name: "unresolved", resolution: &jirapb.Resolution{Name: "Unresolved"}, want: ""},
+		{name: "done", resolution: &jirapb.Resolution{Name: "Done"}, want: "fixed"},
+		{name: "fixed", resolution: &jirapb.Resolution{Name: "Fixed"}, want: "fixed"},
+		{name: "won't do", resolution: &jirapb.Resolution{Name: "Won't Do"}, want: "wontfix"},
+		{name: "won't fix", resolution: &jirapb.Resolution{Name: "Won't Fix"}, want: "wontfix"},
+		{name: "typographic apostrophe", resolution: &jirapb.Resolution{Name: "Won’t Do"}, want: "wontfix"},
+		{name: "duplicate", resolution: &jirapb.Resolution{Name: "Duplicate"}, want: "duplicate"},
+		{name: "cannot reproduce", resolution: &jirapb.Resolution{Name: "Cannot Reproduce"}, want: "cannot_reproduce"},
+		{name: "custom resolution", resolution: &jirapb.Resolution{Name: "Out of Scope"}, want: "out_of_scope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conv.mapResolution(tt.resolution); got != tt.want {
+				t.Errorf("mapResolution() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtoMapIssueStatusWithResolution(t *testing.T) {
+	conv := NewProtoConverter()
+
+	status := func(name, category string) *jirapb.Status {
+		return &jirapb.Status{Name: name, StatusCategory: &jirapb.StatusCategory{Key: category}}
+	}
+
+	tests := []struct {
+		name   string
+		fields *jirapb.Fields
+		want   beadspb.Status
+	}{
+		{
+			name:   "no resolution",
+			fields: &jirapb.Fields{Status: status("In Review", "indeterminate")},
+			want:   beadspb.Status_STATUS_IN_PROGRESS,
+		},
+		{
+			name:   "resolution outside the done category",
+			fields: &jirapb.Fields{Status: &jirapb.Status{Name: "Abandoned"}, Resolution: &jirapb.Resolution{Name: "Won't Do"}},
+			want:   beadspb.Status_STATUS_CLOSED,
+		},
+		{
+			name:   "resolution with an unknown category",
+			fields: &jirapb.Fields{Status: status("Abandoned", "undefined"), Resolution: &jirapb.Resolution{Name: "Won't Do"}},
+			want:   beadspb.Status_STATUS_CLOSED,
+		},
+		{
+			name:   "reopened issue keeping its resolution",
+			fields: &jirapb.Fields{Status: status("Reopened", "new"), Resolution: &jirapb.Resolution{Name: "Fixed"}},
+			want:   beadspb.Status_STATUS_OPEN,
+		},
+		{
+			name:   "in progress issue keeping its resolution",
+			fields: &jirapb.Fields{Status: status("In Review", "indeterminate"), Resolution: &jirapb.Resolution{Name: "Done"}},
+			want:   beadspb.Status_STATUS_IN_PROGRESS,
+		},
+		{
+			name:   "unresolved resolution",
+			fields: &jirapb.Fields{Status: &jirapb.Status{Name: "Triage"}, Resolution: &jirapb.Resolution{Name: "Unresolved"}},
+			want:   beadspb.Status_STATUS_OPEN,
+		},
+		{
+			name:   "none resolution",
+			fields: &jirapb.Fields{Status: &jirapb.Status{Name: "Triage"}, Resolution: &jirapb.Resolution{Name: "None"}},
+			want:   beadspb.Status_STATUS_OPEN,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conv.mapIssueStatus(tt.fields); got != tt.want {
+				t.Errorf("mapIssueStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtoConvertIssueDueDateAndResolution(t *testing.T) {
+	conv := NewProtoConverter()
+
+	due := timestamppb.Now()
+	resolved := timestamppb.Now()
+	jiraIssue := &jirapb.Issue{
+		Id:  "10004",
+		Key: "PROJ-4",
+		Fields: &jirapb.Fields{
+			Summary:   "Abandoned",
+			IssueType: &jirapb.IssueType{Name: "Story"},
+			Status: &jirapb.Status{
+				Name:           "Done",
+				StatusCategory: &jirapb.StatusCategory{Key: "done"},
+			},
+			DueDate:        due,
+			Resolution:     &jirapb.Resolution{Name: "Won't Do"},
+			ResolutionDate: resolved,
+		},
+	}
+
+	issue, err := conv.convertIssue(jiraIssue)
+	if err != nil {
+		t.Fatalf("convertIssue failed: %v", err)
+	}
+
+	if issue.Status != beadspb.Status_STATUS_CLOSED {
+		t.Errorf("Expected STATUS_CLOSED, got %v", issue.Status)
+	}
+	if issue.Resolution != "wontfix" {
+		t.Errorf("Expected resolution wontfix, got %q", issue.Resolution)
+	}
+	if issue.Due != due {
+		t.Errorf("Expected due date to be carried over, got %v", issue.Due)
+	}
+	if issue.Resolved != resolved {
+		t.Errorf("Expected resolved date to be carried over, got %v", issue.Resolved)
+	}
+
+	epic, err := conv.convertEpic(jiraIssue)
+	if err != nil {
+		t.Fatalf("convertEpic failed: %v", err)
+	}
+	if epic.Resolution != "wontfix" || epic.Due != due || epic.Resolved != resolved {
+		t.Errorf("Expected epic to carry due date and resolution, got %v", epic)
+	}
+}
+
+func TestProtoConvertSprint(t *testing.T) {
+	conv := NewProtoConverter()
+
+	start := timestamppb.Now()
+	sprint := &jirapb.Sprint{
+		Id:            42,
+		Name:          "Sprint 42",
+		State:         "active",
+		Goal:          "Ship the login flow",
+		StartDate:     start,
+		OriginBoardId: 7,
+	}
+
+	export, err := conv.Convert(&jirapb.Export{
+		Sprint: sprint,
+		Issues: []*jirapb.Issue{
+			{
+				Key: "PROJ-1",
+				Fields: &jirapb.Fields{
+					Summary:   "Planned",
+					IssueType: &jirapb.IssueType{Name: "Story"},
+					Sprint:    sprint,
+				},
+			},
+			{
+				Key: "PROJ-2",
+				Fields: &jirapb.Fields{
+					Summary:   "Unplanned",
+					IssueType: &jirapb.IssueType{Name: "Story"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	if export.Sprint == nil || export.Sprint.Id != 42 || export.Sprint.BoardId != 7 {
+		t.Fatalf("Expected export sprint 42 on board 7, got %v", export.Sprint)
+	}
+	if export.Sprint.Goal != "Ship the login flow" || export.Sprint.Start != start {
+		t.Errorf("Expected sprint goal and start date to be carried over, got %v", export.Sprint)
+	}
+	if export.Issues[0].Sprint == nil || export.Issues[0].Sprint.Name != "Sprint 42" {
+		t.Errorf("Expected PROJ-1 to be in Sprint 42, got %v", export.Issues[0].Sprint)
+	}
+	if export.Issues[1].Sprint != nil {
+		t.Errorf("Expected PROJ-2 to have no sprint, got %v", export.Issues[1].Sprint)
+	}
+}
+
+func TestProtoConvertSortByRank(t *testing.T) {
+	newIssue := func(key, rank string) *jirapb.Issue {
+		return &jirapb.Issue{
+			Key: key,
+			Fields: &jirapb.Fields{
+				Summary:   key,
+				IssueType: &jirapb.IssueType{Name: "Story"},
+				Rank:      rank,
+			},
+		}
+	}
+	jiraExport := &jirapb.Export{
+		Issues: []*jirapb.Issue{
+			newIssue("PROJ-1", "0|i0000r:"),
+			newIssue("PROJ-2", ""),
+			newIssue("PROJ-3", "0|i0000f:"),
+			newIssue("PROJ-4", ""),
+			newIssue("PROJ-5", "0|i0000k:"),
+			newIssue("PROJ-6", "1|i0000a:"),
+			newIssue("PROJ-7", "2|i0000z:"),
+		},
+	}
+
+	unsorted, err := NewProtoConverter().Convert(jiraExport)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if unsorted.Issues[0].Id != "proj-1" || unsorted.Issues[0].Rank != "0|i0000r:" {
+		t.Errorf("Expected export order without SortByRank, got %s", unsorted.Issues[0].Id)
+	}
+
+	options := DefaultOptions()
+	options.SortByRank = true
+	sorted, err := NewProtoConverterWithOptions(options).Convert(jiraExport)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	// The bucket prefix is ignored, as issues are in different buckets while
+	// Jira rebalances ranks
+	want := []string{"proj-6", "proj-3", "proj-5", "proj-1", "proj-7", "proj-2", "proj-4"}
+	for i, id := range want {
+		if sorted.Issues[i].Id != id {
+			t.Errorf("Expected %s at position %d, got %s", id, i, sorted.Issues[i].Id)
+		}
+	}
+}
+
 func TestProtoMapPriority(t *testing.T) {
 	conv := NewProtoConverter()
 
@@ -340,6 +577,212 @@ func TestProtoConvertIssue(t *testing.T) {
 	}
 }
 
+func TestProtoConvertIssueVersionsAndComponents(t *testing.T) {
+	jiraIssue := &jirapb.Issue{
+		Id:  "10003",
+		Key: "PROJ-3",
+		Fields: &jirapb.Fields{
+			Summary:     "Versioned Issue",
+			IssueType:   &jirapb.IssueType{Name: "Bug"},
+			Labels:      []string{"backend"},
+			FixVersions: []*jirapb.Version{{Id: "100", Name: "2.4"}},
+			Versions:    []*jirapb.Version{{Id: "99", Name: "2.3"}},
+			Components:  []*jirapb.Component{{Id: "7", Name: "api"}},
+		},
+	}
+
+	t.Run("labels", func(t *testing.T) {
+		conv := NewProtoConverter()
+		issue, err := conv.convertIssue(jiraIssue)
+		if err != nil {
+			t.Fatalf("convertIssue failed: %v", err)
+		}
+
+		want := []string{"backend", "fixVersion:2.4", "affectsVersion:2.3", "component:api"}
+		if len(issue.Labels) != len(want) {
+			t.Fatalf("Expected labels %v, got %v", want, issue.Labels)
+		}
+		for i, label := range want {
+			if issue.Labels[i] != label {
+				t.Errorf("Expected label %q at index %d, got %q", label, i, issue.Labels[i])
+			}
+		}
+		if len(issue.FixVersions) != 0 || len(issue.AffectsVersions) != 0 || len(issue.Components) != 0 {
+			t.Error("Expected dedicated fields to be empty when mapping to labels")
+		}
+
+		// The Jira labels must not be modified
+		if len(jiraIssue.Fields.Labels) != 1 {
+			t.Errorf("Expected Jira labels to be untouched, got %v", jiraIssue.Fields.Labels)
+		}
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		conv := NewProtoConverterWithOptions(Options{
+			FixVersions:     TargetFields,
+			AffectsVersions: TargetFields,
+			Components:      TargetFields,
+		})
+		issue, err := conv.convertIssue(jiraIssue)
+		if err != nil {
+			t.Fatalf("convertIssue failed: %v", err)
+		}
+
+		if len(issue.Labels) != 1 || issue.Labels[0] != "backend" {
+			t.Errorf("Expected only the Jira label, got %v", issue.Labels)
+		}
+		if len(issue.FixVersions) != 1 || issue.FixVersions[0] != "2.4" {
+			t.Errorf("Expected fix versions [2.4], got %v", issue.FixVersions)
+		}
+		if len(issue.AffectsVersions) != 1 || issue.AffectsVersions[0] != "2.3" {
+			t.Errorf("Expected affects versions [2.3], got %v", issue.AffectsVersions)
+		}
+		if len(issue.Components) != 1 || issue.Components[0] != "api" {
+			t.Errorf("Expected components [api], got %v", issue.Components)
+		}
+	})
+}
+
+func TestProtoConvertEpicVersionsAndComponents(t *testing.T) {
+	jiraEpic := &jirapb.Issue{
+		Id:  "10004",
+		Key: "PROJ-4",
+		Fields: &jirapb.Fields{
+			Summary:     "Versioned Epic",
+			IssueType:   &jirapb.IssueType{Name: "Epic"},
+			Labels:      []string{"roadmap"},
+			FixVersions: []*jirapb.Version{{Id: "100", Name: "2.4"}},
+			Versions:    []*jirapb.Version{{Id: "99", Name: "2.3"}},
+			Components:  []*jirapb.Component{{Id: "7", Name: "api"}},
+		},
+	}
+
+	t.Run("labels", func(t *testing.T) {
+		epic, err := NewProtoConverter().convertEpic(jiraEpic)
+		if err != nil {
+			t.Fatalf("convertEpic failed: %v", err)
+		}
+
+		want := []string{"roadmap", "fixVersion:2.4", "affectsVersion:2.3", "component:api"}
+		if len(epic.Labels) != len(want) {
+			t.Fatalf("Expected labels %v, got %v", want, epic.Labels)
+		}
+		for i, label := range want {
+			if epic.Labels[i] != label {
+				t.Errorf("Expected label %q at index %d, got %q", label, i, epic.Labels[i])
+			}
+		}
+		if len(epic.FixVersions) != 0 || len(epic.AffectsVersions) != 0 || len(epic.Components) != 0 {
+			t.Error("Expected dedicated fields to be empty when mapping to labels")
+		}
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		conv := NewProtoConverterWithOptions(Options{
+			FixVersions:     TargetFields,
+			AffectsVersions: TargetFields,
+			Components:      TargetFields,
+		})
+		epic, err := conv.convertEpic(jiraEpic)
+		if err != nil {
+			t.Fatalf("convertEpic failed: %v", err)
+		}
+
+		if len(epic.Labels) != 1 || epic.Labels[0] != "roadmap" {
+			t.Errorf("Expected only the Jira label, got %v", epic.Labels)
+		}
+		if len(epic.FixVersions) != 1 || epic.FixVersions[0] != "2.4" {
+			t.Errorf("Expected fix versions [2.4], got %v", epic.FixVersions)
+		}
+		if len(epic.AffectsVersions) != 1 || epic.AffectsVersions[0] != "2.3" {
+			t.Errorf("Expected affects versions [2.3], got %v", epic.AffectsVersions)
+		}
+		if len(epic.Components) != 1 || epic.Components[0] != "api" {
+			t.Errorf("Expected components [api], got %v", epic.Components)
+		}
+	})
+}
+
+func TestProtoConvertDevelopment(t *testing.T) {
+	jiraExport := &jirapb.Export{
+		Issues: []*jirapb.Issue{
+			{
+				Key: "PROJ-1",
+				Fields: &jirapb.Fields{
+					Summary:   "With pull requests",
+					IssueType: &jirapb.IssueType{Name: "Story"},
+				},
+				PullRequests: []*jirapb.PullRequest{
+					{
+						Name:          "Add login",
+						Url:           "https://github.com/acme/api/pull/12",
+						Status:        "MERGED",
+						RepositoryUrl: "https://github.com/acme/api",
+					},
+				},
+				Repositories: []string{"https://github.com/acme/web"},
+				RemoteLinks: []*jirapb.RemoteLink{
+					{Url: "https://github.com/acme/api/pull/12"},
+					{Url: "https://gitlab.com/acme/infra/-/merge_requests/4"},
+					{Url: "https://wiki.example.com/display/ENG/Design"},
+				},
+			},
+		},
+	}
+
+	beadsExport, err := NewProtoConverter().Convert(jiraExport)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	metadata := beadsExport.Issues[0].Metadata
+	expectedRepos := []string{
+		"https://github.com/acme/web",
+		"https://github.com/acme/api",
+		"https://gitlab.com/acme/infra",
+	}
+	if len(metadata.Repositories) != len(expectedRepos) {
+		t.Fatalf("Expected repositories %v, got %v", expectedRepos, metadata.Repositories)
+	}
+	for i, repo := range expectedRepos {
+		if metadata.Repositories[i] != repo {
+			t.Errorf("Expected repository %s at %d, got %s", repo, i, metadata.Repositories[i])
+		}
+	}
+
+	if len(metadata.PullRequests) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(metadata.PullRequests))
+	}
+	pr := metadata.PullRequests[0]
+	if pr.Url != "https://github.com/acme/api/pull/12" || pr.State != "merged" || pr.Title != "Add login" {
+		t.Errorf("Unexpected pull request: %v", pr)
+	}
+}
+
+func TestRepositoryFromURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://github.com/acme/api/pull/12", "https://github.com/acme/api"},
+		{"https://github.com/acme/api/tree/feature/login", "https://github.com/acme/api"},
+		{"https://github.com/acme/api/commit/abc123", "https://github.com/acme/api"},
+		{"https://gitlab.com/acme/group/api/-/merge_requests/3", "https://gitlab.com/acme/group/api"},
+		{"https://bitbucket.org/acme/api/pull-requests/7", "https://bitbucket.org/acme/api"},
+		{"https://github.com/acme/api", ""},
+		{"https://wiki.example.com/display/ENG/Design", ""},
+		{"not a url", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := repositoryFromURL(tt.url); got != tt.expected {
+				t.Errorf("repositoryFromURL(%q) = %q, want %q", tt.url, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestProtoConvertNilExport(t *testing.T) {
 	conv := NewProtoConverter()
 	_, err := conv.Convert(nil)
@@ -438,3 +881,41 @@ func TestProtoGetDependencies(t *testing.T) {
 		t.Errorf("Expected PROJ-1 to depend on PROJ-2, got %v", proj1Deps)
 	}
 }
+
+func TestProtoConvertOneMatchesConvert(t *testing.T) {
+	export, err := jira.NewAdapter().ParseFile("../../testdata/sample-jira-export.json")
+	if err != nil {
+		t.Fatalf("Failed to parse sample: %v", err)
+	}
+
+	want, err := NewProtoConverter().Convert(export)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	conv := NewProtoConverter()
+	for _, issue := range export.Issues {
+		if issue.Fields.IssueType.Name == "Epic" {
+			conv.AddEpic(issue)
+		}
+	}
+	got := &beadspb.Export{}
+	for _, jiraIssue := range export.Issues {
+		issue, epic, err := conv.ConvertOne(jiraIssue)
+		if err != nil {
+			t.Fatalf("ConvertOne failed: %v", err)
+		}
+		if (issue == nil) == (epic == nil) {
+			t.Fatalf("Expected exactly one of issue and epic for %s", jiraIssue.Key)
+		}
+		if epic != nil {
+			got.Epics = append(got.Epics, epic)
+		} else {
+			got.Issues = append(got.Issues, issue)
+		}
+	}
+
+	if !proto.Equal(got, want) {
+		t.Errorf("ConvertOne differs from Convert:\n%v\n%v", got, want)
+	}
+}
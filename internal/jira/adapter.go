@@ -1,86 +1,122 @@
 package jira
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	pb "github.com/conallob/jira-beads-sync/gen/jira"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// Adapter handles converting JSON Jira exports to protobuf format
-type Adapter struct{}
+// Adapter handles converting JSON, CSV and XML Jira exports to protobuf format
+type Adapter struct {
+	rankField   string         // ID of the Lexorank "Rank" custom field, e.g. "customfield_10019"
+	location    *time.Location // zone of CSV dates, which have none; nil means UTC
+	lenient     bool
+	diagnostics []Diagnostic // from the last export parsed whole
+}
 
 // NewAdapter creates a new Jira JSON to protobuf adapter
 func NewAdapter() *Adapter {
 	return &Adapter{}
 }
 
-// ParseFile reads and parses a Jira export JSON file into protobuf
+// SetLocation sets the zone CSV export dates are read in. Jira writes them
+// without a zone, in the timezone of the user who exported them, so this
+// should be that user's Jira timezone. Nil means UTC.
+func (a *Adapter) SetLocation(location *time.Location) {
+	a.location = location
+}
+
+// SetRankField sets the ID of the custom field holding the board rank.
+// Rank is a custom field whose ID differs between Jira instances.
+func (a *Adapter) SetRankField(fieldID string) {
+	a.rankField = fieldID
+}
+
+// RankField returns the ID of the custom field holding the board rank
+func (a *Adapter) RankField() string {
+	return a.rankField
+}
+
+// ParseFile reads and parses a Jira export file into protobuf, detecting
+// whether it is JSON, CSV or XML
 func (a *Adapter) ParseFile(filename string) (*pb.Export, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
 	}
 
-	return a.Parse(data)
+	return a.ParseAny(data)
 }
 
-// Parse parses Jira export JSON data into protobuf
+// Parse parses Jira export JSON data into protobuf. It accepts every shape
+// IssueDecoder reads: exports, search results, single issues, and
+// concatenated pages. An issue present more than once, e.g. in overlapping
+// pages, is kept once, from its most recently updated copy.
 func (a *Adapter) Parse(data []byte) (*pb.Export, error) {
-	var jsonExport jsonExport
-	if err := json.Unmarshal(data, &jsonExport); err != nil {
-		return nil, fmt.Errorf("failed to parse Jira export: %w", err)
-	}
-
-	export := &pb.Export{
-		Issues: make([]*pb.Issue, len(jsonExport.Issues)),
-	}
-
-	for i, jsonIssue := range jsonExport.Issues {
-		issue, err := a.convertIssue(&jsonIssue)
+	a.diagnostics = nil
+	decoder := a.NewIssueDecoder(bytes.NewReader(data))
+	export := &pb.Export{}
+	positions := make(map[string]int)
+
+	for {
+		issue, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert issue %s: %w", jsonIssue.Key, err)
+			return nil, err
 		}
-		export.Issues[i] = issue
-	}
 
-	if err := a.validate(export); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+		if i, ok := positions[issue.Key]; ok {
+			if !issue.Fields.Updated.AsTime().Before(export.Issues[i].Fields.Updated.AsTime()) {
+				export.Issues[i] = issue
+			}
+			continue
+		}
+		positions[issue.Key] = len(export.Issues)
+		export.Issues = append(export.Issues, issue)
 	}
 
+	a.diagnostics = decoder.Diagnostics()
 	return export, nil
 }
 
-// validate checks if the parsed export is valid
-func (a *Adapter) validate(export *pb.Export) error {
-	if export == nil {
-		return fmt.Errorf("export is nil")
+// validateIssue checks if the issue at an index of an export is valid
+func (a *Adapter) validateIssue(index int, issue *pb.Issue) error {
+	if issue.Key == "" {
+		return fmt.Errorf("issue at index %d has no key", index)
 	}
-
-	if len(export.Issues) == 0 {
-		return fmt.Errorf("export contains no issues")
+	if issue.Fields == nil || issue.Fields.Summary == "" {
+		return fmt.Errorf("issue %s has no summary", issue.Key)
 	}
-
-	for i, issue := range export.Issues {
-		if issue.Key == "" {
-			return fmt.Errorf("issue at index %d has no key", i)
-		}
-		if issue.Fields == nil || issue.Fields.Summary == "" {
-			return fmt.Errorf("issue %s has no summary", issue.Key)
-		}
-		if issue.Fields.IssueType == nil || issue.Fields.IssueType.Name == "" {
-			return fmt.Errorf("issue %s has no issue type", issue.Key)
-		}
+	if issue.Fields.IssueType == nil || issue.Fields.IssueType.Name == "" {
+		return fmt.Errorf("issue %s has no issue type", issue.Key)
 	}
 
 	return nil
 }
 
-// convertIssue converts a JSON issue to protobuf
+// convertIssue converts a JSON issue to protobuf, failing on the first field
+// that couldn't be parsed
 func (a *Adapter) convertIssue(jsonIssue *jsonIssue) (*pb.Issue, error) {
+	issue, problems := a.convertJSONIssue(jsonIssue)
+	if len(problems) > 0 {
+		return nil, problems[0]
+	}
+	return issue, nil
+}
+
+// convertJSONIssue converts a JSON issue to protobuf, leaving out and
+// returning the fields that couldn't be parsed
+func (a *Adapter) convertJSONIssue(jsonIssue *jsonIssue) (*pb.Issue, []*fieldError) {
+	problems := jsonIssue.Fields.invalid
 	issue := &pb.Issue{
 		Id:   jsonIssue.ID,
 		Key:  jsonIssue.Key,
@@ -104,9 +140,12 @@ func (a *Adapter) convertIssue(jsonIssue *jsonIssue) (*pb.Issue, error) {
 				Name: jsonIssue.Fields.Priority.Name,
 				Id:   jsonIssue.Fields.Priority.ID,
 			},
-			Labels:     jsonIssue.Fields.Labels,
-			IssueLinks: make([]*pb.IssueLink, len(jsonIssue.Fields.IssueLinks)),
-			Subtasks:   make([]*pb.Subtask, len(jsonIssue.Fields.Subtasks)),
+			Labels:      jsonIssue.Fields.Labels,
+			IssueLinks:  make([]*pb.IssueLink, len(jsonIssue.Fields.IssueLinks)),
+			Subtasks:    make([]*pb.Subtask, len(jsonIssue.Fields.Subtasks)),
+			FixVersions: a.convertVersions(jsonIssue.Fields.FixVersions),
+			Versions:    a.convertVersions(jsonIssue.Fields.Versions),
+			Components:  make([]*pb.Component, len(jsonIssue.Fields.Components)),
 		},
 	}
 
@@ -117,6 +156,21 @@ func (a *Adapter) convertIssue(jsonIssue *jsonIssue) (*pb.Issue, error) {
 	if !jsonIssue.Fields.Updated.IsZero() {
 		issue.Fields.Updated = timestamppb.New(jsonIssue.Fields.Updated)
 	}
+	if !jsonIssue.Fields.DueDate.IsZero() {
+		issue.Fields.DueDate = timestamppb.New(jsonIssue.Fields.DueDate)
+	}
+	if !jsonIssue.Fields.ResolutionDate.IsZero() {
+		issue.Fields.ResolutionDate = timestamppb.New(jsonIssue.Fields.ResolutionDate)
+	}
+
+	// Convert resolution (null while the issue is unresolved)
+	if jsonIssue.Fields.Resolution != nil {
+		issue.Fields.Resolution = &pb.Resolution{
+			Id:          jsonIssue.Fields.Resolution.ID,
+			Name:        jsonIssue.Fields.Resolution.Name,
+			Description: jsonIssue.Fields.Resolution.Description,
+		}
+	}
 
 	// Convert assignee
 	if jsonIssue.Fields.Assignee != nil {
@@ -182,7 +236,92 @@ func (a *Adapter) convertIssue(jsonIssue *jsonIssue) (*pb.Issue, error) {
 		}
 	}
 
-	return issue, nil
+	// Convert sprints (only present in Agile API responses)
+	if jsonIssue.Fields.Sprint != nil {
+		sprint, err := a.convertSprint(jsonIssue.Fields.Sprint)
+		if err != nil {
+			problems = append(problems, &fieldError{"fields.sprint", fmt.Errorf("failed to convert sprint: %w", err)})
+		} else {
+			issue.Fields.Sprint = sprint
+		}
+	}
+	for i, closedSprint := range jsonIssue.Fields.ClosedSprints {
+		sprint, err := a.convertSprint(&closedSprint)
+		if err != nil {
+			path := fmt.Sprintf("fields.closedSprints[%d]", i)
+			problems = append(problems, &fieldError{path, fmt.Errorf("failed to convert closed sprint: %w", err)})
+			continue
+		}
+		issue.Fields.ClosedSprints = append(issue.Fields.ClosedSprints, sprint)
+	}
+
+	// Convert rank
+	if a.rankField != "" {
+		if raw, ok := jsonIssue.Fields.Custom[a.rankField]; ok {
+			var rank string
+			if err := json.Unmarshal(raw, &rank); err == nil {
+				issue.Fields.Rank = rank
+			}
+		}
+	}
+
+	// Convert components
+	for i, component := range jsonIssue.Fields.Components {
+		issue.Fields.Components[i] = &pb.Component{
+			Id:   component.ID,
+			Name: component.Name,
+		}
+	}
+
+	return issue, problems
+}
+
+// convertVersions converts JSON versions to protobuf
+func (a *Adapter) convertVersions(versions []jsonVersion) []*pb.Version {
+	pbVersions := make([]*pb.Version, len(versions))
+	for i, version := range versions {
+		pbVersions[i] = &pb.Version{
+			Id:          version.ID,
+			Name:        version.Name,
+			Released:    version.Released,
+			Archived:    version.Archived,
+			ReleaseDate: version.ReleaseDate,
+		}
+	}
+	return pbVersions
+}
+
+// convertSprint converts a JSON Agile sprint to protobuf
+func (a *Adapter) convertSprint(sprint *jsonSprint) (*pb.Sprint, error) {
+	pbSprint := &pb.Sprint{
+		Id:            sprint.ID,
+		Name:          sprint.Name,
+		State:         sprint.State,
+		Goal:          sprint.Goal,
+		OriginBoardId: sprint.OriginBoardID,
+	}
+
+	// The Agile API uses RFC 3339 timestamps, e.g. "2024-01-08T09:00:00.000+10:00"
+	dates := []struct {
+		value  string
+		target **timestamppb.Timestamp
+	}{
+		{sprint.StartDate, &pbSprint.StartDate},
+		{sprint.EndDate, &pbSprint.EndDate},
+		{sprint.CompleteDate, &pbSprint.CompleteDate},
+	}
+	for _, date := range dates {
+		if date.value == "" {
+			continue
+		}
+		t, err := ParseTimestamp(date.value)
+		if err != nil {
+			return nil, err
+		}
+		*date.target = timestamppb.New(t)
+	}
+
+	return pbSprint, nil
 }
 
 // convertIssueLink converts a JSON issue link to protobuf
@@ -270,10 +409,6 @@ func (a *Adapter) convertParent(parent *jsonParent) *pb.Parent {
 }
 
 // JSON types for unmarshaling (kept internal)
-type jsonExport struct {
-	Issues []jsonIssue `json:"issues"`
-}
-
 type jsonIssue struct {
 	ID     string     `json:"id"`
 	Key    string     `json:"key"`
@@ -296,6 +431,22 @@ type jsonFields struct {
 	Parent      *jsonParent     `json:"parent,omitempty"`
 	Epic        *jsonEpic       `json:"epic,omitempty"`
 	Subtasks    []jsonSubtask   `json:"subtasks"`
+	FixVersions []jsonVersion   `json:"fixVersions"`
+	Versions    []jsonVersion   `json:"versions"`
+	Components  []jsonComponent `json:"components"`
+
+	DueDate        time.Time       `json:"duedate"`
+	Resolution     *jsonResolution `json:"resolution,omitempty"`
+	ResolutionDate time.Time       `json:"resolutiondate"`
+
+	Sprint        *jsonSprint  `json:"sprint,omitempty"`
+	ClosedSprints []jsonSprint `json:"closedSprints"`
+
+	// Custom holds the raw values of all "customfield_*" fields
+	Custom map[string]json.RawMessage `json:"-"`
+
+	// invalid holds the fields that couldn't be parsed, left zero
+	invalid []*fieldError
 }
 
 type jsonIssueType struct {
@@ -314,6 +465,12 @@ type jsonStatusCategory struct {
 	Name string `json:"name"`
 }
 
+type jsonResolution struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
 type jsonPriority struct {
 	Name string `json:"name"`
 	ID   string `json:"id"`
@@ -374,12 +531,39 @@ type jsonSubtask struct {
 	Fields jsonLinkedFields `json:"fields"`
 }
 
+type jsonVersion struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Released    bool   `json:"released"`
+	Archived    bool   `json:"archived"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+}
+
+type jsonSprint struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	State         string `json:"state"`
+	Goal          string `json:"goal"`
+	StartDate     string `json:"startDate"`
+	EndDate       string `json:"endDate"`
+	CompleteDate  string `json:"completeDate"`
+	OriginBoardID int64  `json:"originBoardId"`
+}
+
+type jsonComponent struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 // UnmarshalJSON implements custom JSON unmarshaling for timestamps
 func (jf *jsonFields) UnmarshalJSON(b []byte) error {
 	type Alias jsonFields
 	aux := &struct {
-		Created string `json:"created"`
-		Updated string `json:"updated"`
+		Description    json.RawMessage `json:"description"`
+		Created        string          `json:"created"`
+		Updated        string          `json:"updated"`
+		DueDate        string          `json:"duedate"`
+		ResolutionDate string          `json:"resolutiondate"`
 		*Alias
 	}{
 		Alias: (*Alias)(jf),
@@ -389,21 +573,52 @@ func (jf *jsonFields) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
-	// Parse Jira timestamp format
-	if aux.Created != "" {
-		t, err := time.Parse("2006-01-02T15:04:05.000-0700", aux.Created)
+	// REST API v3 returns descriptions as Atlassian Document Format
+	description, err := descriptionText(aux.Description)
+	if err != nil {
+		return err
+	}
+	jf.Description = description
+
+	// Parse Jira timestamp format. A bad timestamp doesn't fail decoding, so
+	// lenient parsing can keep the rest of the issue.
+	jf.invalid = nil
+	timestamps := []struct {
+		name   string
+		value  string
+		parse  func(string) (time.Time, error)
+		target *time.Time
+	}{
+		{"created", aux.Created, ParseTimestamp, &jf.Created},
+		{"updated", aux.Updated, ParseTimestamp, &jf.Updated},
+		{"resolutiondate", aux.ResolutionDate, ParseTimestamp, &jf.ResolutionDate},
+		// Due dates are plain dates such as "2024-03-01"
+		{"duedate", aux.DueDate, ParseDate, &jf.DueDate},
+	}
+	for _, timestamp := range timestamps {
+		if timestamp.value == "" {
+			continue
+		}
+		t, err := timestamp.parse(timestamp.value)
 		if err != nil {
-			return err
+			jf.invalid = append(jf.invalid, &fieldError{"fields." + timestamp.name, fmt.Errorf("invalid %s: %w", timestamp.name, err)})
+			continue
 		}
-		jf.Created = t
+		*timestamp.target = t
 	}
 
-	if aux.Updated != "" {
-		t, err := time.Parse("2006-01-02T15:04:05.000-0700", aux.Updated)
-		if err != nil {
-			return err
+	// Keep custom fields, whose IDs are instance specific, for later lookup
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	for key, value := range raw {
+		if strings.HasPrefix(key, "customfield_") {
+			if jf.Custom == nil {
+				jf.Custom = make(map[string]json.RawMessage)
+			}
+			jf.Custom[key] = value
 		}
-		jf.Updated = t
 	}
 
 	return nil
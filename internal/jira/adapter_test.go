@@ -2,6 +2,7 @@ package jira
 
 import (
 	"testing"
+	"time"
 
 	pb "github.com/conallob/jira-beads-sync/gen/jira"
 )
@@ -46,6 +47,33 @@ func TestAdapterParseEmptyIssues(t *testing.T) {
 	}
 }
 
+func TestAdapterParseConcatenatedPages(t *testing.T) {
+	// Pages fetched while the project was changing can repeat an issue; the
+	// most recently updated copy is kept, in the position it was first seen
+	pages := `{"startAt": 0, "maxResults": 2, "total": 3, "issues": [
+		{"id": "1", "key": "PROJ-1", "fields": {"summary": "Old title", "issuetype": {"name": "Task"}, "updated": "2024-01-01T10:00:00.000+0000"}},
+		{"id": "2", "key": "PROJ-2", "fields": {"summary": "Second", "issuetype": {"name": "Task"}, "updated": "2024-01-01T10:00:00.000+0000"}}]}
+{"startAt": 2, "maxResults": 2, "total": 3, "issues": [
+		{"id": "1", "key": "PROJ-1", "fields": {"summary": "New title", "issuetype": {"name": "Task"}, "updated": "2024-01-02T10:00:00.000+0000"}}]}
+{"id": "3", "key": "PROJ-3", "fields": {"summary": "Third", "issuetype": {"name": "Task"}}}
+`
+
+	export, err := NewAdapter().Parse([]byte(pages))
+	if err != nil {
+		t.Fatalf("Failed to parse pages: %v", err)
+	}
+
+	if len(export.Issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %d", len(export.Issues))
+	}
+	if export.Issues[0].Key != "PROJ-1" || export.Issues[0].Fields.Summary != "New title" {
+		t.Errorf("Expected the newer PROJ-1 first, got %s %q", export.Issues[0].Key, export.Issues[0].Fields.Summary)
+	}
+	if export.Issues[2].Key != "PROJ-3" {
+		t.Errorf("Expected the single issue last, got %s", export.Issues[2].Key)
+	}
+}
+
 func TestAdapterValidation(t *testing.T) {
 	adapter := NewAdapter()
 
@@ -207,3 +235,156 @@ func TestAdapterConvertParent(t *testing.T) {
 		}
 	}
 }
+
+func TestAdapterConvertVersionsAndComponents(t *testing.T) {
+	adapter := NewAdapter()
+
+	export, err := adapter.Parse([]byte(`{"issues": [{"id": "1", "key": "PROJ-1", "fields": {
+		"summary": "Test",
+		"issuetype": {"name": "Task"},
+		"fixVersions": [{"id": "100", "name": "2.4", "released": false, "releaseDate": "2024-03-01"}],
+		"versions": [{"id": "99", "name": "2.3", "released": true, "archived": true}],
+		"components": [{"id": "7", "name": "api"}, {"id": "8", "name": "web"}]
+	}}]}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	fields := export.Issues[0].Fields
+
+	if len(fields.FixVersions) != 1 {
+		t.Fatalf("Expected 1 fix version, got %d", len(fields.FixVersions))
+	}
+	if fields.FixVersions[0].Name != "2.4" || fields.FixVersions[0].ReleaseDate != "2024-03-01" {
+		t.Errorf("Unexpected fix version: %v", fields.FixVersions[0])
+	}
+
+	if len(fields.Versions) != 1 {
+		t.Fatalf("Expected 1 affects version, got %d", len(fields.Versions))
+	}
+	if !fields.Versions[0].Released || !fields.Versions[0].Archived {
+		t.Errorf("Expected affects version to be released and archived, got %v", fields.Versions[0])
+	}
+
+	if len(fields.Components) != 2 {
+		t.Fatalf("Expected 2 components, got %d", len(fields.Components))
+	}
+	if fields.Components[0].Name != "api" || fields.Components[1].Id != "8" {
+		t.Errorf("Unexpected components: %v", fields.Components)
+	}
+}
+
+func TestAdapterConvertDueDateAndResolution(t *testing.T) {
+	adapter := NewAdapter()
+
+	export, err := adapter.Parse([]byte(`{"issues": [
+		{"id": "1", "key": "PROJ-1", "fields": {
+			"summary": "Resolved",
+			"issuetype": {"name": "Task"},
+			"duedate": "2024-03-01",
+			"resolution": {"id": "10001", "name": "Won't Do", "description": "Not doing this"},
+			"resolutiondate": "2024-02-20T09:15:00.000+0000"
+		}},
+		{"id": "2", "key": "PROJ-2", "fields": {
+			"summary": "Unresolved",
+			"issuetype": {"name": "Task"},
+			"duedate": null,
+			"resolution": null,
+			"resolutiondate": null
+		}}
+	]}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	resolved := export.Issues[0].Fields
+	if resolved.DueDate == nil {
+		t.Fatal("Expected due date to be set")
+	}
+	if got := resolved.DueDate.AsTime().Format("2006-01-02"); got != "2024-03-01" {
+		t.Errorf("Expected due date 2024-03-01, got %s", got)
+	}
+	if resolved.Resolution == nil || resolved.Resolution.Name != "Won't Do" {
+		t.Errorf("Expected resolution \"Won't Do\", got %v", resolved.Resolution)
+	}
+	if resolved.ResolutionDate == nil {
+		t.Error("Expected resolution date to be set")
+	}
+
+	unresolved := export.Issues[1].Fields
+	if unresolved.DueDate != nil || unresolved.Resolution != nil || unresolved.ResolutionDate != nil {
+		t.Errorf("Expected null due date and resolution to stay unset, got %v", unresolved)
+	}
+}
+
+func TestAdapterParseTimestampFormats(t *testing.T) {
+	export, err := NewAdapter().Parse([]byte(`{"issues": [{"id": "1", "key": "PROJ-1", "fields": {
+		"summary": "Test",
+		"issuetype": {"name": "Task"},
+		"created": "2024-01-01T10:00:00Z",
+		"updated": "2024-01-02T10:00:00+01:00",
+		"resolutiondate": "2024-01-03T10:00:00+0000",
+		"duedate": "2024-03-01T00:00:00.000+1000"
+	}}]}`))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	fields := export.Issues[0].Fields
+	tests := []struct {
+		name string
+		got  time.Time
+		want time.Time
+	}{
+		{"created", fields.Created.AsTime(), time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"updated", fields.Updated.AsTime(), time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{"resolutiondate", fields.ResolutionDate.AsTime(), time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)},
+		{"duedate", fields.DueDate.AsTime(), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if !tt.got.Equal(tt.want) {
+			t.Errorf("Expected %s %v, got %v", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func TestAdapterInvalidDueDate(t *testing.T) {
+	adapter := NewAdapter()
+
+	_, err := adapter.Parse([]byte(`{"issues": [{"id": "1", "key": "PROJ-1", "fields": {
+		"summary": "Test",
+		"issuetype": {"name": "Task"},
+		"duedate": "next tuesday"
+	}}]}`))
+	if err == nil {
+		t.Error("Expected error for invalid due date, got nil")
+	}
+}
+
+func TestAdapterConvertRank(t *testing.T) {
+	data := []byte(`{"issues": [{"id": "1", "key": "PROJ-1", "fields": {
+		"summary": "Ranked",
+		"issuetype": {"name": "Story"},
+		"customfield_10019": "0|i0000f:",
+		"customfield_10020": null
+	}}]}`)
+
+	// Without a rank field the value is ignored
+	export, err := NewAdapter().Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if export.Issues[0].Fields.Rank != "" {
+		t.Errorf("Expected no rank without a rank field, got '%s'", export.Issues[0].Fields.Rank)
+	}
+
+	adapter := NewAdapter()
+	adapter.SetRankField("customfield_10019")
+	export, err = adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if export.Issues[0].Fields.Rank != "0|i0000f:" {
+		t.Errorf("Expected rank '0|i0000f:', got '%s'", export.Issues[0].Fields.Rank)
+	}
+}
@@ -0,0 +1,114 @@
+package jira
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// adfNode is a node of an Atlassian Document Format (ADF) document
+type adfNode struct {
+	Type    string                 `json:"type"`
+	Text    string                 `json:"text"`
+	Attrs   map[string]interface{} `json:"attrs"`
+	Content []adfNode              `json:"content"`
+}
+
+// descriptionText returns a description as plain text. REST API v2 returns
+// descriptions as strings, v3 as ADF documents.
+func descriptionText(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	writeADFBlocks(&b, doc.Content, "")
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// writeADFBlocks writes block nodes separated by blank lines, prefixing
+// every line with indent (used for nested list items)
+func writeADFBlocks(b *strings.Builder, nodes []adfNode, indent string) {
+	for i, node := range nodes {
+		if i > 0 && indent == "" {
+			b.WriteString("\n")
+		}
+
+		switch node.Type {
+		case "bulletList", "orderedList":
+			for n, item := range node.Content {
+				marker := "- "
+				if node.Type == "orderedList" {
+					marker = strconv.Itoa(n+1) + ". "
+				}
+				b.WriteString(indent + marker)
+				writeADFListItem(b, item.Content, indent+"  ")
+			}
+		case "codeBlock":
+			b.WriteString(indent + "```\n")
+			b.WriteString(indent + adfInlineText(node.Content) + "\n")
+			b.WriteString(indent + "```\n")
+		case "rule":
+			b.WriteString(indent + "---\n")
+		case "paragraph", "heading":
+			b.WriteString(indent + adfInlineText(node.Content) + "\n")
+		default:
+			if len(node.Content) > 0 {
+				writeADFBlocks(b, node.Content, indent)
+			} else if node.Text != "" {
+				b.WriteString(indent + node.Text + "\n")
+			}
+		}
+	}
+}
+
+// writeADFListItem writes the content of a list item, with the first
+// paragraph on the marker line and nested blocks indented below it
+func writeADFListItem(b *strings.Builder, nodes []adfNode, indent string) {
+	for i, node := range nodes {
+		if i == 0 && node.Type == "paragraph" {
+			b.WriteString(adfInlineText(node.Content) + "\n")
+			continue
+		}
+		if i == 0 {
+			b.WriteString("\n")
+		}
+		writeADFBlocks(b, []adfNode{node}, indent)
+	}
+	if len(nodes) == 0 {
+		b.WriteString("\n")
+	}
+}
+
+// adfInlineText concatenates the text of inline nodes
+func adfInlineText(nodes []adfNode) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			b.WriteString(node.Text)
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention", "emoji", "date", "status", "inlineCard":
+			for _, attr := range []string{"text", "shortName", "url"} {
+				if value, ok := node.Attrs[attr].(string); ok && value != "" {
+					b.WriteString(value)
+					break
+				}
+			}
+		default:
+			b.WriteString(adfInlineText(node.Content))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDescriptionText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "plain string",
+			input:    `"Plain description"`,
+			expected: "Plain description",
+		},
+		{
+			name:     "null",
+			input:    `null`,
+			expected: "",
+		},
+		{
+			name: "paragraphs",
+			input: `{"type": "doc", "version": 1, "content": [
+				{"type": "paragraph", "content": [{"type": "text", "text": "First "}, {"type": "text", "text": "line", "marks": [{"type": "strong"}]}]},
+				{"type": "paragraph", "content": [{"type": "text", "text": "Second"}, {"type": "hardBreak"}, {"type": "text", "text": "line"}]}
+			]}`,
+			expected: "First line\n\nSecond\nline",
+		},
+		{
+			name: "lists and code",
+			input: `{"type": "doc", "version": 1, "content": [
+				{"type": "heading", "attrs": {"level": 2}, "content": [{"type": "text", "text": "Steps"}]},
+				{"type": "orderedList", "content": [
+					{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Log in"}]}]},
+					{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Ask "}, {"type": "mention", "attrs": {"id": "1", "text": "@alex"}}]}]}
+				]},
+				{"type": "bulletList", "content": [
+					{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Item"}]}]}
+				]},
+				{"type": "codeBlock", "content": [{"type": "text", "text": "make test"}]}
+			]}`,
+			expected: "Steps\n\n1. Log in\n2. Ask @alex\n\n- Item\n\n```\nmake test\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := descriptionText(json.RawMessage(tt.input))
+			if err != nil {
+				t.Fatalf("descriptionText() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("descriptionText() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDescriptionTextInvalid(t *testing.T) {
+	if _, err := descriptionText(json.RawMessage(`42`)); err == nil {
+		t.Error("Expected error for numeric description, got nil")
+	}
+}
@@ -0,0 +1,225 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	pb "github.com/conallob/jira-beads-sync/gen/jira"
+)
+
+// agilePageSize is the page size requested from Jira Agile API endpoints
+const agilePageSize = 50
+
+// GetSprint fetches a sprint by ID from the Jira Agile API
+func (c *Client) GetSprint(sprintID int64) (*pb.Sprint, error) {
+	apiURL := fmt.Sprintf("%s/rest/agile/1.0/sprint/%d", c.baseURL, sprintID)
+
+	body, err := c.get(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sprint %d: %w", sprintID, err)
+	}
+
+	var sprint jsonSprint
+	if err := json.Unmarshal(body, &sprint); err != nil {
+		return nil, fmt.Errorf("failed to fetch sprint %d: failed to parse response: %w", sprintID, err)
+	}
+
+	converted, err := c.adapter.convertSprint(&sprint)
+	if err != nil {
+		return nil, err
+	}
+	c.snapshot.recordSprint(converted.Id, body)
+
+	return converted, nil
+}
+
+// GetBoardSprints lists the sprints of a board.
+// state filters by sprint state ("future", "active", "closed"); empty returns all sprints.
+func (c *Client) GetBoardSprints(boardID int64, state string) ([]*pb.Sprint, error) {
+	sprints := make([]*pb.Sprint, 0)
+	startAt := 0
+
+	for page := 1; ; page++ {
+		if page > maxSearchPages {
+			return nil, fmt.Errorf("failed to list sprints for board %d: exceeded %d pages, stopping", boardID, maxSearchPages)
+		}
+		query := url.Values{}
+		query.Set("startAt", fmt.Sprintf("%d", startAt))
+		query.Set("maxResults", fmt.Sprintf("%d", agilePageSize))
+		if state != "" {
+			query.Set("state", state)
+		}
+		apiURL := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint?%s", c.baseURL, boardID, query.Encode())
+
+		var result struct {
+			Values []jsonSprint `json:"values"`
+			IsLast bool         `json:"isLast"`
+		}
+		if err := c.getPageJSON(apiURL, &result); err != nil {
+			return nil, fmt.Errorf("failed to list sprints for board %d: %w", boardID, err)
+		}
+
+		for i := range result.Values {
+			sprint, err := c.adapter.convertSprint(&result.Values[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert sprint %d: %w", result.Values[i].ID, err)
+			}
+			sprints = append(sprints, sprint)
+		}
+
+		if result.IsLast || len(result.Values) == 0 {
+			break
+		}
+		startAt += len(result.Values)
+	}
+
+	return sprints, nil
+}
+
+// GetActiveSprint resolves the active sprint of a board.
+// Returns an error if the board has no active sprint, or several parallel ones.
+func (c *Client) GetActiveSprint(boardID int64) (*pb.Sprint, error) {
+	sprints, err := c.GetBoardSprints(boardID, "active")
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(sprints) {
+	case 0:
+		return nil, fmt.Errorf("board %d has no active sprint", boardID)
+	case 1:
+		return sprints[0], nil
+	default:
+		ids := make([]string, len(sprints))
+		for i, sprint := range sprints {
+			ids[i] = fmt.Sprintf("%d (%s)", sprint.Id, sprint.Name)
+		}
+		return nil, fmt.Errorf("board %d has %d active sprints, specify one of: %v", boardID, len(sprints), ids)
+	}
+}
+
+// FetchSprint fetches all issues in a sprint and their dependencies.
+// Issues in the sprint are recorded with the sprint they were fetched from,
+// whatever their sprint field says, and the sprint itself is attached to the returned export.
+func (c *Client) FetchSprint(sprintID int64) (*pb.Export, error) {
+	sprint, err := c.GetSprint(sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Fetching sprint %d: %s\n", sprint.Id, sprint.Name)
+
+	sprintIssues, err := c.searchAgileIssues(fmt.Sprintf("%s/rest/agile/1.0/sprint/%d/issue", c.baseURL, sprintID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issues in sprint %d: %w", sprintID, err)
+	}
+
+	if len(sprintIssues) == 0 {
+		return nil, fmt.Errorf("sprint %d contains no issues", sprintID)
+	}
+
+	fmt.Printf("Found %d issue(s) in sprint %s\n", len(sprintIssues), sprint.Name)
+	fmt.Println()
+
+	for _, issue := range sprintIssues {
+		assignSprint(issue, sprint)
+		c.snapshot.recordSprintIssue(issue.Key)
+	}
+
+	issues, err := c.withDependencies(sprintIssues)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.Export{Issues: issues, Sprint: sprint}, nil
+}
+
+// assignSprint records an issue as a member of sprint. The sprint field Jira
+// returned may name another sprint, e.g. one the issue rolled over from, which
+// is kept with the issue's closed sprints.
+func assignSprint(issue *pb.Issue, sprint *pb.Sprint) {
+	if current := issue.Fields.Sprint; current != nil && current.Id != sprint.Id {
+		known := false
+		for _, closed := range issue.Fields.ClosedSprints {
+			if closed.Id == current.Id {
+				known = true
+				break
+			}
+		}
+		if !known {
+			issue.Fields.ClosedSprints = append(issue.Fields.ClosedSprints, current)
+		}
+	}
+	issue.Fields.Sprint = sprint
+}
+
+// FetchBoardBacklog fetches a board's backlog in rank order and their dependencies.
+// The rank field is discovered automatically unless it has been set explicitly.
+func (c *Client) FetchBoardBacklog(boardID int64) (*pb.Export, error) {
+	if c.adapter.RankField() == "" {
+		rankField, err := c.DiscoverRankField()
+		if err != nil {
+			return nil, err
+		}
+		c.SetRankField(rankField)
+	}
+
+	fmt.Printf("Fetching backlog of board %d...\n", boardID)
+
+	backlog, err := c.searchAgileIssues(fmt.Sprintf("%s/rest/agile/1.0/board/%d/backlog", c.baseURL, boardID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch backlog of board %d: %w", boardID, err)
+	}
+
+	if len(backlog) == 0 {
+		return nil, fmt.Errorf("backlog of board %d is empty", boardID)
+	}
+
+	fmt.Printf("Found %d issue(s) in backlog\n", len(backlog))
+	fmt.Println()
+
+	issues, err := c.withDependencies(backlog)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.Export{Issues: issues}, nil
+}
+
+// searchAgileIssues fetches every page of a Jira Agile API issue listing
+// (e.g. /sprint/{id}/issue), which returns full issue payloads
+func (c *Client) searchAgileIssues(endpoint string) ([]*pb.Issue, error) {
+	issues := make([]*pb.Issue, 0)
+	startAt := 0
+
+	for page := 1; ; page++ {
+		if page > maxSearchPages {
+			return nil, fmt.Errorf("listing exceeded %d pages, stopping", maxSearchPages)
+		}
+		apiURL := fmt.Sprintf("%s?startAt=%d&maxResults=%d", endpoint, startAt, agilePageSize)
+
+		var result struct {
+			Issues []json.RawMessage `json:"issues"`
+			Total  int               `json:"total"`
+		}
+		if err := c.getPageJSON(apiURL, &result); err != nil {
+			return nil, err
+		}
+
+		for _, data := range result.Issues {
+			issue, err := c.convertAPIIssue(data)
+			if err != nil {
+				return nil, err
+			}
+			issues = append(issues, issue)
+		}
+
+		startAt += len(result.Issues)
+		if len(result.Issues) == 0 || startAt >= result.Total {
+			break
+		}
+	}
+
+	return issues, nil
+}
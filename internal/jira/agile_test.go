@@ -0,0 +1,527 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sprintResponse(id int64, name, state string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            id,
+		"name":          name,
+		"state":         state,
+		"goal":          "Ship the login flow",
+		"startDate":     "2024-01-08T09:00:00.000+10:00",
+		"endDate":       "2024-01-22T17:00:00.000+10:00",
+		"originBoardId": 7,
+	}
+}
+
+func TestGetSprint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/sprint/42" {
+			t.Errorf("Expected path '/rest/agile/1.0/sprint/42', got '%s'", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(sprintResponse(42, "Sprint 42", "active")); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	sprint, err := client.GetSprint(42)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if sprint.Id != 42 || sprint.Name != "Sprint 42" || sprint.State != "active" {
+		t.Errorf("Unexpected sprint: %v", sprint)
+	}
+	if sprint.Goal != "Ship the login flow" {
+		t.Errorf("Expected goal 'Ship the login flow', got '%s'", sprint.Goal)
+	}
+	if sprint.StartDate == nil || sprint.EndDate == nil {
+		t.Fatal("Expected start and end dates to be set")
+	}
+	if got := sprint.StartDate.AsTime().UTC().Format("2006-01-02T15:04"); got != "2024-01-07T23:00" {
+		t.Errorf("Expected start date 2024-01-07T23:00 UTC, got %s", got)
+	}
+	if sprint.CompleteDate != nil {
+		t.Error("Expected complete date to be unset for an active sprint")
+	}
+	if sprint.OriginBoardId != 7 {
+		t.Errorf("Expected origin board 7, got %d", sprint.OriginBoardId)
+	}
+}
+
+func TestGetBoardSprintsPagination(t *testing.T) {
+	requestCount := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+
+		if r.URL.Path != "/rest/agile/1.0/board/7/sprint" {
+			t.Errorf("Expected path '/rest/agile/1.0/board/7/sprint', got '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("state") != "closed" {
+			t.Errorf("Expected state filter 'closed', got '%s'", r.URL.Query().Get("state"))
+		}
+
+		var response map[string]interface{}
+		if r.URL.Query().Get("startAt") == "0" {
+			response = map[string]interface{}{
+				"values": []map[string]interface{}{sprintResponse(1, "Sprint 1", "closed")},
+				"isLast": false,
+			}
+		} else {
+			response = map[string]interface{}{
+				"values": []map[string]interface{}{sprintResponse(2, "Sprint 2", "closed")},
+				"isLast": true,
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	sprints, err := client.GetBoardSprints(7, "closed")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(sprints) != 2 {
+		t.Fatalf("Expected 2 sprints, got %d", len(sprints))
+	}
+	if sprints[0].Id != 1 || sprints[1].Id != 2 {
+		t.Errorf("Unexpected sprint order: %d, %d", sprints[0].Id, sprints[1].Id)
+	}
+	if requestCount != 2 {
+		t.Errorf("Expected 2 requests (pagination), got %d", requestCount)
+	}
+}
+
+func TestGetActiveSprint(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []map[string]interface{}
+		wantID  int64
+		wantErr string
+	}{
+		{
+			name:   "single active sprint",
+			values: []map[string]interface{}{sprintResponse(42, "Sprint 42", "active")},
+			wantID: 42,
+		},
+		{
+			name:    "no active sprint",
+			values:  []map[string]interface{}{},
+			wantErr: "has no active sprint",
+		},
+		{
+			name: "parallel active sprints",
+			values: []map[string]interface{}{
+				sprintResponse(42, "Team A", "active"),
+				sprintResponse(43, "Team B", "active"),
+			},
+			wantErr: "has 2 active sprints",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("state") != "active" {
+					t.Errorf("Expected state filter 'active', got '%s'", r.URL.Query().Get("state"))
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				response := map[string]interface{}{"values": tt.values, "isLast": true}
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					t.Errorf("Failed to encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+			sprint, err := client.GetActiveSprint(7)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if sprint.Id != tt.wantID {
+				t.Errorf("Expected sprint %d, got %d", tt.wantID, sprint.Id)
+			}
+		})
+	}
+}
+
+func TestAgileListingPageCap(t *testing.T) {
+	defer func(pages int) { maxSearchPages = pages }(maxSearchPages)
+	maxSearchPages = 3
+
+	tests := []struct {
+		name string
+		list func(*Client) error
+	}{
+		{
+			name: "board sprints",
+			list: func(c *Client) error {
+				_, err := c.GetBoardSprints(7, "")
+				return err
+			},
+		},
+		{
+			name: "sprint issues",
+			list: func(c *Client) error {
+				_, err := c.FetchSprint(42)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestCount := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var response interface{}
+				switch r.URL.Path {
+				case "/rest/agile/1.0/sprint/42":
+					response = sprintResponse(42, "Sprint 42", "active")
+				case "/rest/agile/1.0/board/7/sprint":
+					// Never the last page
+					requestCount++
+					response = map[string]interface{}{
+						"values": []map[string]interface{}{sprintResponse(int64(requestCount), "Sprint", "closed")},
+						"isLast": false,
+					}
+				case "/rest/agile/1.0/sprint/42/issue":
+					// A total that never runs out
+					requestCount++
+					response = map[string]interface{}{
+						"total":  1000000,
+						"issues": []map[string]interface{}{createMinimalIssue(fmt.Sprintf("PROJ-%d", requestCount), "Issue")},
+					}
+				default:
+					t.Errorf("Unexpected request path: %s", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					t.Errorf("Failed to encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+			err := tt.list(client)
+			if err == nil || !strings.Contains(err.Error(), "exceeded 3 pages") {
+				t.Errorf("Expected page cap error, got: %v", err)
+			}
+			if requestCount != 3 {
+				t.Errorf("Expected 3 page requests, got %d", requestCount)
+			}
+		})
+	}
+}
+
+func TestFetchSprint(t *testing.T) {
+	fetchedIssues := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+
+		switch r.URL.Path {
+		case "/rest/agile/1.0/sprint/42":
+			response = sprintResponse(42, "Sprint 42", "active")
+		case "/rest/agile/1.0/sprint/42/issue":
+			// PROJ-1 carries its sprint, PROJ-2 does not and is blocked by PROJ-3
+			proj1 := createMinimalIssue("PROJ-1", "In sprint")
+			proj1["fields"].(map[string]interface{})["sprint"] = sprintResponse(42, "Sprint 42", "active")
+
+			proj2 := createMinimalIssue("PROJ-2", "Also in sprint")
+			proj2["fields"].(map[string]interface{})["issuelinks"] = []map[string]interface{}{
+				{
+					"id":          "1",
+					"type":        map[string]interface{}{"name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+					"inwardIssue": map[string]interface{}{"key": "PROJ-3"},
+				},
+			}
+
+			response = map[string]interface{}{
+				"startAt":    0,
+				"maxResults": 50,
+				"total":      2,
+				"issues":     []map[string]interface{}{proj1, proj2},
+			}
+		case "/rest/api/3/search/jql":
+			// Dependencies outside the sprint are fetched in one batch
+			if jql := r.URL.Query().Get("jql"); jql != "key in (PROJ-3)" {
+				t.Errorf("Expected JQL 'key in (PROJ-3)', got '%s'", jql)
+			}
+			fetchedIssues["PROJ-3"]++
+			response = map[string]interface{}{
+				"issues": []map[string]interface{}{createMinimalIssue("PROJ-3", "Blocker outside the sprint")},
+				"total":  1,
+			}
+		default:
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	export, err := client.FetchSprint(42)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if export.Sprint == nil || export.Sprint.Id != 42 {
+		t.Fatalf("Expected export to carry sprint 42, got %v", export.Sprint)
+	}
+
+	if len(export.Issues) != 3 {
+		t.Fatalf("Expected 3 issues (2 in sprint + 1 dependency), got %d", len(export.Issues))
+	}
+
+	for _, issue := range export.Issues[:2] {
+		if issue.Fields.Sprint == nil || issue.Fields.Sprint.Id != 42 {
+			t.Errorf("Expected %s to be recorded in sprint 42, got %v", issue.Key, issue.Fields.Sprint)
+		}
+	}
+
+	if export.Issues[2].Key != "PROJ-3" || export.Issues[2].Fields.Sprint != nil {
+		t.Errorf("Expected PROJ-3 as an unplanned dependency, got %s (sprint %v)", export.Issues[2].Key, export.Issues[2].Fields.Sprint)
+	}
+
+	if fetchedIssues["PROJ-3"] != 1 {
+		t.Errorf("Expected PROJ-3 to be fetched once, got %d", fetchedIssues["PROJ-3"])
+	}
+}
+
+func TestFetchSprintIssueFromAnotherSprint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+
+		switch r.URL.Path {
+		case "/rest/agile/1.0/sprint/42":
+			response = sprintResponse(42, "Sprint 42", "active")
+		case "/rest/agile/1.0/sprint/42/issue":
+			// PROJ-1 rolled over from sprint 43, which its sprint field still names
+			proj1 := createMinimalIssue("PROJ-1", "Rolled over")
+			proj1["fields"].(map[string]interface{})["sprint"] = sprintResponse(43, "Sprint 43", "closed")
+
+			response = map[string]interface{}{
+				"total":  1,
+				"issues": []map[string]interface{}{proj1},
+			}
+		default:
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	snapshot := NewSnapshot("fetch-sprint")
+	client.SetSnapshot(snapshot)
+
+	export, err := client.FetchSprint(42)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(export.Issues) != 1 {
+		t.Fatalf("Expected 1 issue, got %d", len(export.Issues))
+	}
+
+	issue := export.Issues[0]
+	if issue.Fields.Sprint == nil || issue.Fields.Sprint.Id != 42 {
+		t.Errorf("Expected PROJ-1 to be recorded in sprint 42, got %v", issue.Fields.Sprint)
+	}
+	if len(issue.Fields.ClosedSprints) != 1 || issue.Fields.ClosedSprints[0].Id != 43 {
+		t.Errorf("Expected sprint 43 kept as a closed sprint, got %v", issue.Fields.ClosedSprints)
+	}
+	if len(snapshot.SprintIssues) != 1 || snapshot.SprintIssues[0] != "PROJ-1" {
+		t.Errorf("Expected PROJ-1 recorded as a sprint issue in the snapshot, got %v", snapshot.SprintIssues)
+	}
+}
+
+func TestFetchSprintEmpty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+		if r.URL.Path == "/rest/agile/1.0/sprint/42" {
+			response = sprintResponse(42, "Sprint 42", "future")
+		} else {
+			response = map[string]interface{}{"issues": []interface{}{}, "total": 0}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	if _, err := client.FetchSprint(42); err == nil {
+		t.Error("Expected error for empty sprint, got nil")
+	}
+}
+
+func TestGetSprintNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errorMessages":["Sprint does not exist"]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	_, err := client.GetSprint(999)
+	if err == nil {
+		t.Fatal("Expected error for missing sprint, got nil")
+	}
+	if !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected error to mention status 404, got: %v", err)
+	}
+}
+
+func TestFetchBoardBacklog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+
+		switch r.URL.Path {
+		case "/rest/api/2/field":
+			response = []map[string]interface{}{
+				{"id": "summary", "name": "Summary"},
+				{
+					"id":     "customfield_10019",
+					"name":   "Rank",
+					"custom": true,
+					"schema": map[string]interface{}{"custom": "com.pyxis.greenhopper.jira:gh-lexo-rank"},
+				},
+			}
+		case "/rest/agile/1.0/board/7/backlog":
+			first := createMinimalIssue("PROJ-2", "Top of backlog")
+			first["fields"].(map[string]interface{})["customfield_10019"] = "0|i0000f:"
+			second := createMinimalIssue("PROJ-1", "Second")
+			second["fields"].(map[string]interface{})["customfield_10019"] = "0|i0000r:"
+
+			response = map[string]interface{}{
+				"startAt": 0,
+				"total":   2,
+				"issues":  []map[string]interface{}{first, second},
+			}
+		default:
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	export, err := client.FetchBoardBacklog(7)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if client.adapter.RankField() != "customfield_10019" {
+		t.Errorf("Expected rank field to be discovered, got '%s'", client.adapter.RankField())
+	}
+
+	if len(export.Issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %d", len(export.Issues))
+	}
+	if export.Issues[0].Key != "PROJ-2" || export.Issues[0].Fields.Rank != "0|i0000f:" {
+		t.Errorf("Expected PROJ-2 first with rank 0|i0000f:, got %s (%s)", export.Issues[0].Key, export.Issues[0].Fields.Rank)
+	}
+	if export.Issues[1].Fields.Rank != "0|i0000r:" {
+		t.Errorf("Expected PROJ-1 rank 0|i0000r:, got %s", export.Issues[1].Fields.Rank)
+	}
+}
+
+func TestFetchBoardBacklogWithConfiguredRankField(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/2/field" {
+			t.Error("Rank field should not be discovered when configured")
+		}
+
+		issue := createMinimalIssue("PROJ-1", "Only issue")
+		issue["fields"].(map[string]interface{})["customfield_10500"] = "0|hzzzzz:"
+
+		w.Header().Set("Content-Type", "application/json")
+		response := map[string]interface{}{"total": 1, "issues": []map[string]interface{}{issue}}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	client.SetRankField("customfield_10500")
+
+	export, err := client.FetchBoardBacklog(7)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if export.Issues[0].Fields.Rank != "0|hzzzzz:" {
+		t.Errorf("Expected rank 0|hzzzzz:, got '%s'", export.Issues[0].Fields.Rank)
+	}
+}
+
+func TestDiscoverRankFieldMissing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": "summary", "name": "Summary", "schema": {"type": "string"}}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	if _, err := client.DiscoverRankField(); err == nil {
+		t.Error("Expected error when no Rank field exists, got nil")
+	}
+}
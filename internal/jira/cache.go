@@ -0,0 +1,278 @@
+package jira
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultCacheMaxSize is the size cap of the response cache if none is set
+const DefaultCacheMaxSize = 100 << 20
+
+// CacheConfig configures the on-disk cache of Jira API responses. Responses
+// are stored with their ETag and Last-Modified validators; a cached response
+// older than TTL is revalidated with a conditional request, and used again
+// if Jira answers 304 Not Modified.
+type CacheConfig struct {
+	Dir     string        // directory of the cached responses
+	TTL     time.Duration // responses younger than this are used without asking Jira
+	MaxSize int64         // bytes; the least recently used responses are evicted beyond it. DefaultCacheMaxSize if 0
+}
+
+// responseCache stores GET responses on disk, keyed by URL and the identity
+// of the credentials they were fetched with
+type responseCache struct {
+	config CacheConfig
+	now    func() time.Time
+
+	// index of the cached files, loaded when the first response is stored
+	index map[string]cacheUsage
+	size  int64
+}
+
+// cacheUsage is the size and last use of a cached response, for eviction
+type cacheUsage struct {
+	size     int64
+	lastUsed time.Time
+}
+
+// cacheEntry is a cached response. It is stored as a JSON header line
+// followed by the response body.
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	StoredAt     time.Time `json:"storedAt"`
+
+	body []byte
+}
+
+// SetCache makes the client cache the responses of its GET requests in
+// config.Dir, which is created if needed. Jira marks its responses as not
+// cacheable by shared caches; this cache is private to the user and only
+// serves a response to the credentials that fetched it.
+func (c *Client) SetCache(config CacheConfig) error {
+	if config.Dir == "" {
+		return fmt.Errorf("cache directory is required")
+	}
+	if config.MaxSize == 0 {
+		config.MaxSize = DefaultCacheMaxSize
+	}
+	if err := os.MkdirAll(config.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	c.cache = &responseCache{config: config, now: time.Now}
+	return nil
+}
+
+// cacheIdentity identifies the credentials of the client, so cached
+// responses are never served to another user. Secrets are only included
+// hashed. OAuth 2.0 tokens rotate on every refresh, so responses fetched with
+// OAuth are keyed by the user's account instead, looked up once per client;
+// the site is part of the URL.
+func (c *Client) cacheIdentity() (string, error) {
+	if c.authMethod == "oauth2" {
+		if c.cacheAccount == "" {
+			user, err := c.GetCurrentUser()
+			if err != nil {
+				return "", err
+			}
+			if user.AccountID == "" {
+				return "", fmt.Errorf("current user has no account ID")
+			}
+			c.cacheAccount = user.AccountID
+		}
+		return "oauth2:" + c.cacheAccount, nil
+	}
+
+	secret := sha256.Sum256([]byte(c.apiToken))
+	return c.authMethod + ":" + c.username + ":" + hex.EncodeToString(secret[:]), nil
+}
+
+// key returns the file name of the cached response of a URL
+func (rc *responseCache) key(identity, apiURL string) string {
+	sum := sha256.Sum256([]byte(identity + "\n" + apiURL))
+	return hex.EncodeToString(sum[:])
+}
+
+func (rc *responseCache) path(key string) string {
+	return filepath.Join(rc.config.Dir, key)
+}
+
+// load returns the cached response of key, or nil if there is none. An
+// unreadable entry is treated as missing and replaced by the next response.
+func (rc *responseCache) load(key string) *cacheEntry {
+	data, err := os.ReadFile(rc.path(key))
+	if err != nil {
+		return nil
+	}
+
+	header, body, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(header, &entry); err != nil {
+		return nil
+	}
+	entry.body = body
+	return &entry
+}
+
+// fresh reports whether a cached response can be used without revalidating it
+func (rc *responseCache) fresh(entry *cacheEntry) bool {
+	return rc.now().Sub(entry.StoredAt) < rc.config.TTL
+}
+
+// conditional makes a request conditional on the cached response having
+// changed
+func (entry *cacheEntry) conditional(req *http.Request) {
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// used records a use of a cached response, keeping it from eviction
+func (rc *responseCache) used(key string) {
+	now := rc.now()
+	_ = os.Chtimes(rc.path(key), now, now)
+	if usage, ok := rc.index[key]; ok {
+		usage.lastUsed = now
+		rc.index[key] = usage
+	}
+}
+
+// revalidated renews a cached response Jira reported as not modified
+func (rc *responseCache) revalidated(key string, entry *cacheEntry, header http.Header) error {
+	if etag := header.Get("ETag"); etag != "" {
+		entry.ETag = etag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		entry.LastModified = lastModified
+	}
+	return rc.write(key, entry)
+}
+
+// store caches a response. Responses without validators are only worth
+// keeping when they can be used without revalidating them.
+func (rc *responseCache) store(key, apiURL string, header http.Header, body []byte) error {
+	entry := &cacheEntry{
+		URL:          apiURL,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		body:         body,
+	}
+	if entry.ETag == "" && entry.LastModified == "" && rc.config.TTL <= 0 {
+		return nil
+	}
+	return rc.write(key, entry)
+}
+
+// write stores an entry, then evicts the least recently used entries while
+// the cache is over its size cap
+func (rc *responseCache) write(key string, entry *cacheEntry) error {
+	entry.StoredAt = rc.now()
+	header, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	size := int64(len(header) + 1 + len(entry.body))
+	if size > rc.config.MaxSize {
+		return nil
+	}
+	if err := rc.loadIndex(); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so a concurrent run never reads a
+	// partial entry
+	file, err := os.CreateTemp(rc.config.Dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	_, _ = writer.Write(header)
+	_ = writer.WriteByte('\n')
+	_, _ = writer.Write(entry.body)
+	err = writer.Flush()
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), rc.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	rc.size += size - rc.index[key].size
+	rc.index[key] = cacheUsage{size: size, lastUsed: entry.StoredAt}
+	return rc.evict()
+}
+
+// loadIndex reads the sizes and last uses of the cached responses
+func (rc *responseCache) loadIndex() error {
+	if rc.index != nil {
+		return nil
+	}
+
+	files, err := os.ReadDir(rc.config.Dir)
+	if err != nil {
+		return err
+	}
+	rc.index = make(map[string]cacheUsage)
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		rc.index[file.Name()] = cacheUsage{size: info.Size(), lastUsed: info.ModTime()}
+		rc.size += info.Size()
+	}
+	return nil
+}
+
+// evict removes the least recently used responses until the cache fits its
+// size cap
+func (rc *responseCache) evict() error {
+	if rc.size <= rc.config.MaxSize {
+		return nil
+	}
+
+	keys := make([]string, 0, len(rc.index))
+	for key := range rc.index {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return rc.index[keys[i]].lastUsed.Before(rc.index[keys[j]].lastUsed)
+	})
+
+	for _, key := range keys {
+		if rc.size <= rc.config.MaxSize {
+			break
+		}
+		if err := os.Remove(rc.path(key)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		rc.size -= rc.index[key].size
+		delete(rc.index, key)
+	}
+	return nil
+}
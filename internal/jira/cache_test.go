@@ -0,0 +1,233 @@
+package jira
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// cachedIssueServer serves PROJ-1 with the given validators, answering 304
+// when a conditional request matches them
+func cachedIssueServer(t *testing.T, etag, lastModified string, requests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		if lastModified != "" {
+			w.Header().Set("Last-Modified", lastModified)
+		}
+
+		if (etag != "" && r.Header.Get("If-None-Match") == etag) ||
+			(lastModified != "" && r.Header.Get("If-Modified-Since") == lastModified) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "10001", "key": "PROJ-1", "fields": {"summary": "Cached issue",
+			"issuetype": {"name": "Task"}, "status": {"name": "To Do", "statusCategory": {"key": "new"}},
+			"created": "2024-01-01T10:00:00.000+0000", "updated": "2024-01-01T10:00:00.000+0000"}}`))
+	}))
+}
+
+func TestCacheRevalidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		etag         string
+		lastModified string
+	}{
+		{name: "etag", etag: `"abc123"`},
+		{name: "last modified", lastModified: "Mon, 01 Jan 2024 10:00:00 GMT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := cachedIssueServer(t, tt.etag, tt.lastModified, &requests)
+			defer server.Close()
+
+			client := NewClient(server.URL, "user@example.com", "token123", "basic")
+			if err := client.SetCache(CacheConfig{Dir: t.TempDir()}); err != nil {
+				t.Fatalf("Failed to set cache: %v", err)
+			}
+
+			for i := 0; i < 2; i++ {
+				issue, err := client.FetchIssue("PROJ-1")
+				if err != nil {
+					t.Fatalf("Fetch %d: expected no error, got: %v", i+1, err)
+				}
+				if issue.Fields.Summary != "Cached issue" {
+					t.Errorf("Fetch %d: expected the cached issue, got %q", i+1, issue.Fields.Summary)
+				}
+			}
+			// Without a TTL every fetch revalidates
+			if requests.Load() != 2 {
+				t.Errorf("Expected 2 requests, got %d", requests.Load())
+			}
+		})
+	}
+}
+
+func TestCacheTTLAndIdentity(t *testing.T) {
+	var requests atomic.Int32
+	server := cachedIssueServer(t, `"abc123"`, "", &requests)
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	if err := client.SetCache(CacheConfig{Dir: dir, TTL: time.Hour}); err != nil {
+		t.Fatalf("Failed to set cache: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.FetchIssue("PROJ-1"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected a fresh response to be used without a request, got %d requests", requests.Load())
+	}
+
+	// Another user's credentials never see the response
+	other := NewClient(server.URL, "other@example.com", "token456", "basic")
+	if err := other.SetCache(CacheConfig{Dir: dir, TTL: time.Hour}); err != nil {
+		t.Fatalf("Failed to set cache: %v", err)
+	}
+	if _, err := other.FetchIssue("PROJ-1"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected other credentials to miss the cache, got %d requests", requests.Load())
+	}
+
+	// Once the TTL has passed, the response is revalidated
+	client.cache.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err := client.FetchIssue("PROJ-1"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("Expected a stale response to be revalidated, got %d requests", requests.Load())
+	}
+}
+
+func TestCacheKeysOAuth2ByAccount(t *testing.T) {
+	var issueRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rest/api/2/myself":
+			account := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer token-")
+			_, _ = w.Write([]byte(`{"accountId": "account-` + account + `", "active": true}`))
+		case "/rest/api/2/issue/PROJ-1":
+			issueRequests.Add(1)
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(`{"id": "10001", "key": "PROJ-1", "fields": {"summary": "Visible to ` + r.Header.Get("Authorization") + `",
+				"issuetype": {"name": "Task"}, "status": {"name": "To Do", "statusCategory": {"key": "new"}},
+				"created": "2024-01-01T10:00:00.000+0000", "updated": "2024-01-01T10:00:00.000+0000"}}`))
+		default:
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// Two users of the same OAuth app
+	dir := t.TempDir()
+	newOAuth2Client := func(account string) *Client {
+		client := NewClient(server.URL, "", "", "oauth2")
+		client.SetOAuth2(OAuth2Config{ClientID: "app"}, OAuth2Token{
+			AccessToken: "token-" + account,
+			Expiry:      time.Now().Add(time.Hour),
+		}, nil)
+		if err := client.SetCache(CacheConfig{Dir: dir, TTL: time.Hour}); err != nil {
+			t.Fatalf("Failed to set cache: %v", err)
+		}
+		return client
+	}
+	alice, bob := newOAuth2Client("a"), newOAuth2Client("b")
+
+	for _, client := range []*Client{alice, bob, alice} {
+		if _, err := client.FetchIssue("PROJ-1"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if issueRequests.Load() != 2 {
+		t.Errorf("Expected each account to fetch the issue once, got %d requests", issueRequests.Load())
+	}
+	issue, err := bob.FetchIssue("PROJ-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if issue.Fields.Summary != "Visible to Bearer token-b" {
+		t.Errorf("Expected bob's own response, got %q", issue.Fields.Summary)
+	}
+}
+
+func TestCacheRevalidatesPages(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("ETag", `"page-1"`)
+		if r.Header.Get("If-None-Match") == `"page-1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte(`{"issues": [], "isLast": true}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	if err := client.SetCache(CacheConfig{Dir: t.TempDir(), TTL: time.Hour}); err != nil {
+		t.Fatalf("Failed to set cache: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.searchFullIssues("project = PROJ"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected search pages to be revalidated despite the TTL, got %d requests", requests.Load())
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	client := NewClient("https://jira.example.com", "user@example.com", "token123", "basic")
+	if err := client.SetCache(CacheConfig{Dir: dir, MaxSize: 400}); err != nil {
+		t.Fatalf("Failed to set cache: %v", err)
+	}
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	client.cache.now = func() time.Time { return now }
+	header := http.Header{"Etag": []string{`"v1"`}}
+	body := make([]byte, 100)
+
+	keys := []string{"a", "b", "c"}
+	for _, key := range keys {
+		now = now.Add(time.Minute)
+		if err := client.cache.store(key, "https://jira.example.com/"+key, header, body); err != nil {
+			t.Fatalf("Failed to store %s: %v", key, err)
+		}
+		// Using "a" keeps it while "b" becomes the least recently used
+		if key == "b" {
+			now = now.Add(time.Minute)
+			client.cache.used("a")
+		}
+	}
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		_, err := os.Stat(client.cache.path(key))
+		if exists := err == nil; exists != want {
+			t.Errorf("Expected %s cached: %v, got %v", key, want, exists)
+		}
+	}
+	if client.cache.size > 400 {
+		t.Errorf("Expected the cache within its size cap, got %d bytes", client.cache.size)
+	}
+}
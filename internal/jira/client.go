@@ -17,14 +17,34 @@ type Client struct {
 	httpClient *http.Client
 	username   string
 	apiToken   string
-	authMethod string // "basic" or "bearer"
+	authMethod string // "basic", "bearer" or "oauth2"
 	adapter    *Adapter
+	oauth2     *oauth2Session // set by SetOAuth2
+
+	fetchDevelopment bool           // fetch remote links and development information per issue
+	noDevStatus      bool           // the dev-status API answered 404, so it isn't asked again
+	searchFields     []string       // issue fields requested from search; defaultSearchFields if empty
+	deployment       string         // DeploymentCloud, DeploymentServer or DeploymentAuto
+	snapshot         *Snapshot      // records raw responses when set by SetSnapshot
+	cache            *responseCache // set by SetCache
+	cacheAccount     string         // account ID of the OAuth 2.0 user, for cache keys
+}
+
+// statusError is returned for a Jira response with an unexpected status
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("jira API returned status %d: %s", e.StatusCode, e.Body)
 }
 
 // NewClient creates a new Jira API client
 // authMethod should be "basic" or "bearer"
 // For basic auth: username is email/username, apiToken is API token
 // For bearer auth: apiToken is the bearer token, username is optional
+// For OAuth 2.0, call SetOAuth2 after creating the client
 func NewClient(baseURL, username, apiToken, authMethod string) *Client {
 	// Default to basic auth if not specified
 	if authMethod == "" {
@@ -38,34 +58,99 @@ func NewClient(baseURL, username, apiToken, authMethod string) *Client {
 		apiToken:   apiToken,
 		authMethod: authMethod,
 		adapter:    NewAdapter(),
+		deployment: DeploymentCloud,
 	}
 }
 
-// setAuthHeader sets the appropriate authentication header on the request
-func (c *Client) setAuthHeader(req *http.Request) {
-	if c.authMethod == "bearer" {
+// setAuthHeader sets the appropriate authentication header on the request.
+// With OAuth 2.0 an expired access token is refreshed first.
+func (c *Client) setAuthHeader(req *http.Request) error {
+	switch c.authMethod {
+	case "bearer":
 		req.Header.Set("Authorization", "Bearer "+c.apiToken)
-	} else {
+	case "oauth2":
+		accessToken, err := c.oauth2AccessToken()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	default:
 		// Default to basic auth
 		req.SetBasicAuth(c.username, c.apiToken)
 	}
+	return nil
 }
 
-// FetchIssue fetches a single issue by key (e.g., "PROJ-123")
-func (c *Client) FetchIssue(issueKey string) (*pb.Issue, error) {
-	apiURL := fmt.Sprintf("%s/rest/api/2/issue/%s", c.baseURL, issueKey)
+// getJSON performs an authenticated GET request and decodes the JSON response into v
+func (c *Client) getJSON(apiURL string, v interface{}) error {
+	body, err := c.get(apiURL)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// getPageJSON is getJSON for a page of a paged listing, which is never
+// served from the cache without revalidating it: a page fresh in the cache
+// could belong to an older listing than the pages fetched after it
+func (c *Client) getPageJSON(apiURL string, v interface{}) error {
+	body, err := c.fetch(apiURL, true)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// get performs an authenticated GET request for JSON and returns the response
+// body. With a cache set by SetCache, a fresh cached response is returned
+// without a request, and a stale one is revalidated with a conditional request.
+func (c *Client) get(apiURL string) ([]byte, error) {
+	return c.fetch(apiURL, false)
+}
+
+// fetch implements get and getPageJSON. A page is always revalidated.
+func (c *Client) fetch(apiURL string, page bool) (body []byte, err error) {
+	var cacheKey string
+	var cached *cacheEntry
+	if c.cache != nil {
+		// Without an identity the response can't be cached safely, so the
+		// request is made as if there were no cache
+		if identity, err := c.cacheIdentity(); err == nil {
+			cacheKey = c.cache.key(identity, apiURL)
+			cached = c.cache.load(cacheKey)
+		}
+		if cached != nil && !page && c.cache.fresh(cached) {
+			c.cache.used(cacheKey)
+			return cached.body, nil
+		}
+	}
 
 	req, err := http.NewRequest("GET", apiURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	c.setAuthHeader(req)
+	if err := c.setAuthHeader(req); err != nil {
+		return nil, err
+	}
 	req.Header.Set("Accept", "application/json")
+	if cached != nil {
+		cached.conditional(req)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch issue: %w", err)
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil && err == nil {
@@ -73,16 +158,38 @@ func (c *Client) FetchIssue(issueKey string) (*pb.Issue, error) {
 		}
 	}()
 
+	// A cache that can't be written only costs the next run a download, so
+	// failing to write it doesn't fail the request
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		_ = c.cache.revalidated(cacheKey, cached, resp.Header)
+		return cached.body, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("jira API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &statusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	if cacheKey != "" {
+		_ = c.cache.store(cacheKey, apiURL, resp.Header, body)
+	}
+	return body, nil
+}
+
+// FetchIssue fetches a single issue by key (e.g., "PROJ-123")
+func (c *Client) FetchIssue(issueKey string) (*pb.Issue, error) {
+	apiURL := fmt.Sprintf("%s/rest/api/2/issue/%s", c.baseURL, issueKey)
+
+	body, err := c.get(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue: %w", err)
+	}
+
 	// Parse single issue into an export with one issue
 	var jsonIssue jsonIssue
 	if err := json.Unmarshal(body, &jsonIssue); err != nil {
@@ -93,6 +200,24 @@ func (c *Client) FetchIssue(issueKey string) (*pb.Issue, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert issue: %w", err)
 	}
+	c.snapshot.recordIssue(issue.Key, body)
+
+	return issue, nil
+}
+
+// convertAPIIssue decodes and converts an issue of a search or Agile API
+// page, recording its JSON in the snapshot if one is being taken
+func (c *Client) convertAPIIssue(data json.RawMessage) (*pb.Issue, error) {
+	var jsonIssue jsonIssue
+	if err := json.Unmarshal(data, &jsonIssue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue: %w", err)
+	}
+
+	issue, err := c.adapter.convertIssue(&jsonIssue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert issue %s: %w", jsonIssue.Key, err)
+	}
+	c.snapshot.recordIssue(issue.Key, data)
 
 	return issue, nil
 }
@@ -115,7 +240,9 @@ func (c *Client) GetCurrentUser() (*UserInfo, error) {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	c.setAuthHeader(req)
+	if err := c.setAuthHeader(req); err != nil {
+		return nil, err
+	}
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
@@ -149,6 +276,45 @@ func (c *Client) GetCurrentUser() (*UserInfo, error) {
 	return &userInfo, nil
 }
 
+// rankFieldSchema is the custom field type of the Jira Software "Rank" field
+const rankFieldSchema = "com.pyxis.greenhopper.jira:gh-lexo-rank"
+
+// SetRankField sets the ID of the custom field holding the board rank
+func (c *Client) SetRankField(fieldID string) {
+	c.adapter.SetRankField(fieldID)
+	c.snapshot.recordRankField(fieldID)
+}
+
+// RankField returns the ID of the custom field holding the board rank, if
+// it has been set or discovered
+func (c *Client) RankField() string {
+	return c.adapter.RankField()
+}
+
+// DiscoverRankField looks up the ID of the Lexorank "Rank" custom field
+func (c *Client) DiscoverRankField() (string, error) {
+	apiURL := fmt.Sprintf("%s/rest/api/2/field", c.baseURL)
+
+	var fields []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Schema struct {
+			Custom string `json:"custom"`
+		} `json:"schema"`
+	}
+	if err := c.getJSON(apiURL, &fields); err != nil {
+		return "", fmt.Errorf("failed to list fields: %w", err)
+	}
+
+	for _, field := range fields {
+		if field.Schema.Custom == rankFieldSchema {
+			return field.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("no Rank field found; is Jira Software installed?")
+}
+
 // FetchIssueWithDependencies fetches an issue and all its dependencies recursively
 func (c *Client) FetchIssueWithDependencies(issueKey string) (*pb.Export, error) {
 	visited := make(map[string]bool)
@@ -175,37 +341,46 @@ func (c *Client) fetchRecursive(issueKey string, visited map[string]bool, issues
 		return fmt.Errorf("failed to fetch %s: %w", issueKey, err)
 	}
 
+	c.attachDevelopment(issue)
 	*issues = append(*issues, issue)
 
-	// Fetch subtasks
-	for _, subtask := range issue.Fields.Subtasks {
-		if err := c.fetchRecursive(subtask.Key, visited, issues); err != nil {
+	return c.fetchRelated(issue, visited, issues)
+}
+
+// fetchRelated recursively fetches the subtasks, linked issues and non-epic
+// parent of an issue that has already been fetched
+func (c *Client) fetchRelated(issue *pb.Issue, visited map[string]bool, issues *[]*pb.Issue) error {
+	for _, key := range relatedKeys(issue) {
+		if err := c.fetchRecursive(key, visited, issues); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+// relatedKeys returns the keys of an issue's subtasks, linked issues
+// (dependencies) and non-epic parent, in that order
+func relatedKeys(issue *pb.Issue) []string {
+	keys := make([]string, 0)
+
+	for _, subtask := range issue.Fields.Subtasks {
+		keys = append(keys, subtask.Key)
+	}
 
-	// Fetch linked issues (dependencies)
 	for _, link := range issue.Fields.IssueLinks {
 		if link.InwardIssue != nil {
-			if err := c.fetchRecursive(link.InwardIssue.Key, visited, issues); err != nil {
-				return err
-			}
+			keys = append(keys, link.InwardIssue.Key)
 		}
 		if link.OutwardIssue != nil {
-			if err := c.fetchRecursive(link.OutwardIssue.Key, visited, issues); err != nil {
-				return err
-			}
+			keys = append(keys, link.OutwardIssue.Key)
 		}
 	}
 
-	// Fetch parent if it exists and isn't an epic
 	if issue.Fields.Parent != nil && issue.Fields.Parent.Fields.IssueType.Name != "Epic" {
-		if err := c.fetchRecursive(issue.Fields.Parent.Key, visited, issues); err != nil {
-			return err
-		}
+		keys = append(keys, issue.Fields.Parent.Key)
 	}
 
-	return nil
+	return keys
 }
 
 // ParseIssueKeyFromURL extracts the issue key from a Jira URL
@@ -255,78 +430,39 @@ func GetBaseURLFromIssueURL(jiraURL string) (string, error) {
 	return fmt.Sprintf("%s://%s", u.Scheme, u.Host), nil
 }
 
-// SearchIssuesByLabel fetches all issues with a given label using JQL
-func (c *Client) SearchIssuesByLabel(label string) ([]string, error) {
+// labelJQL builds a JQL query matching issues with a label
+func labelJQL(label string) string {
 	// Build JQL query for label with proper quoting
 	// Escape any quotes in the label value
 	escapedLabel := strings.ReplaceAll(label, `"`, `\"`)
-	jql := fmt.Sprintf(`labels = "%s"`, escapedLabel)
-	return c.SearchIssues(jql)
+	return fmt.Sprintf(`labels = "%s"`, escapedLabel)
+}
+
+// SearchIssuesByLabel fetches all issues with a given label using JQL
+func (c *Client) SearchIssuesByLabel(label string) ([]string, error) {
+	return c.SearchIssues(labelJQL(label))
 }
 
 // SearchIssues performs a JQL search and returns issue keys.
-// Uses Jira REST API v3 (/rest/api/3/search/jql) as required by Jira Cloud.
-// Automatically paginates to retrieve all matching issues.
+// Uses /rest/api/3/search/jql on Jira Cloud and /rest/api/2/search on Jira
+// Server and Data Center. Automatically paginates to retrieve all matching issues.
 func (c *Client) SearchIssues(jql string) ([]string, error) {
-	const pageSize = 100
-	encodedJQL := url.QueryEscape(jql)
 	issueKeys := make([]string, 0)
-	startAt := 0
-
-	for {
-		apiURL := fmt.Sprintf("%s/rest/api/3/search/jql?jql=%s&fields=key&maxResults=%d&startAt=%d",
-			c.baseURL, encodedJQL, pageSize, startAt)
-
-		req, err := http.NewRequest("GET", apiURL, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
 
-		c.setAuthHeader(req)
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to search issues: %w", err)
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			if cerr := resp.Body.Close(); cerr != nil {
-				return nil, cerr
-			}
-			return nil, fmt.Errorf("jira API returned status %d: %s", resp.StatusCode, string(body))
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		if cerr := resp.Body.Close(); cerr != nil && err == nil {
-			err = cerr
-		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response: %w", err)
-		}
-
-		var searchResult struct {
-			Issues []struct {
+	err := c.searchPages(jql, "key", func(page []json.RawMessage) error {
+		for _, data := range page {
+			var issue struct {
 				Key string `json:"key"`
-			} `json:"issues"`
-			Total  int  `json:"total"`
-			IsLast bool `json:"isLast"`
-		}
-
-		if err := json.Unmarshal(body, &searchResult); err != nil {
-			return nil, fmt.Errorf("failed to parse search results: %w", err)
-		}
-
-		for _, issue := range searchResult.Issues {
+			}
+			if err := json.Unmarshal(data, &issue); err != nil {
+				return fmt.Errorf("failed to parse issue: %w", err)
+			}
 			issueKeys = append(issueKeys, issue.Key)
 		}
-
-		// Stop if we've retrieved all issues or the server signals last page
-		if searchResult.IsLast || len(searchResult.Issues) == 0 || len(issueKeys) >= searchResult.Total {
-			break
-		}
-		startAt += len(searchResult.Issues)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return issueKeys, nil
@@ -336,26 +472,21 @@ func (c *Client) SearchIssues(jql string) ([]string, error) {
 func (c *Client) FetchIssuesByLabel(label string) (*pb.Export, error) {
 	fmt.Printf("Searching for issues with label: %s\n", label)
 
-	issueKeys, err := c.SearchIssuesByLabel(label)
+	found, err := c.searchFullIssues(labelJQL(label))
 	if err != nil {
 		return nil, fmt.Errorf("failed to search by label: %w", err)
 	}
 
-	if len(issueKeys) == 0 {
+	if len(found) == 0 {
 		return nil, fmt.Errorf("no issues found with label: %s", label)
 	}
 
-	fmt.Printf("Found %d issue(s) with label %s\n", len(issueKeys), label)
+	fmt.Printf("Found %d issue(s) with label %s\n", len(found), label)
 	fmt.Println()
 
-	// Fetch all issues and their dependencies
-	visited := make(map[string]bool)
-	issues := make([]*pb.Issue, 0)
-
-	for _, key := range issueKeys {
-		if err := c.fetchRecursive(key, visited, &issues); err != nil {
-			return nil, err
-		}
+	issues, err := c.withDependencies(found)
+	if err != nil {
+		return nil, err
 	}
 
 	return &pb.Export{Issues: issues}, nil
@@ -365,26 +496,21 @@ func (c *Client) FetchIssuesByLabel(label string) (*pb.Export, error) {
 func (c *Client) FetchIssuesByJQL(jql string) (*pb.Export, error) {
 	fmt.Printf("Searching with JQL: %s\n", jql)
 
-	issueKeys, err := c.SearchIssues(jql)
+	found, err := c.searchFullIssues(jql)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search by JQL: %w", err)
 	}
 
-	if len(issueKeys) == 0 {
+	if len(found) == 0 {
 		return nil, fmt.Errorf("no issues found matching JQL query")
 	}
 
-	fmt.Printf("Found %d issue(s) matching query\n", len(issueKeys))
+	fmt.Printf("Found %d issue(s) matching query\n", len(found))
 	fmt.Println()
 
-	// Fetch all issues and their dependencies
-	visited := make(map[string]bool)
-	issues := make([]*pb.Issue, 0)
-
-	for _, key := range issueKeys {
-		if err := c.fetchRecursive(key, visited, &issues); err != nil {
-			return nil, err
-		}
+	issues, err := c.withDependencies(found)
+	if err != nil {
+		return nil, err
 	}
 
 	return &pb.Export{Issues: issues}, nil
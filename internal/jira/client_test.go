@@ -967,27 +967,29 @@ func TestSearchIssuesWithPagination(t *testing.T) {
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestCount++
-		startAt := r.URL.Query().Get("startAt")
+		pageToken := r.URL.Query().Get("nextPageToken")
 
 		var response map[string]interface{}
-		if startAt == "0" || startAt == "" {
-			// First page: 2 issues, not the last
+		if pageToken == "" {
+			// First page: 2 issues and the token of the next page
 			response = map[string]interface{}{
 				"issues": []map[string]interface{}{
 					{"key": "PROJ-1"},
 					{"key": "PROJ-2"},
 				},
-				"total":  4,
-				"isLast": false,
+				"nextPageToken": "token-2",
+				"isLast":        false,
 			}
 		} else {
+			if pageToken != "token-2" {
+				t.Errorf("Expected nextPageToken 'token-2', got '%s'", pageToken)
+			}
 			// Second page: remaining 2 issues, last page
 			response = map[string]interface{}{
 				"issues": []map[string]interface{}{
 					{"key": "PROJ-3"},
 					{"key": "PROJ-4"},
 				},
-				"total":  4,
 				"isLast": true,
 			}
 		}
@@ -1128,37 +1130,10 @@ func TestSearchIssuesByLabelWithQuotes(t *testing.T) {
 }
 
 func TestFetchIssuesByLabel(t *testing.T) {
-	fetchedIssues := make(map[string]bool)
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.URL.Path {
-		case "/rest/api/3/search/jql":
-			// Return search results
-			response := map[string]interface{}{
-				"issues": []map[string]interface{}{
-					{"key": "PROJ-100"},
-					{"key": "PROJ-101"},
-				},
-				"total": 2,
-			}
-
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(response); err != nil {
-				t.Errorf("Failed to encode response: %v", err)
-			}
-		case "/rest/api/2/issue/PROJ-100", "/rest/api/2/issue/PROJ-101":
-			// Fetch individual issues
-			issueKey := r.URL.Path[len("/rest/api/2/issue/"):]
-			fetchedIssues[issueKey] = true
-
-			response := createMinimalIssue(issueKey, fmt.Sprintf("Issue %s", issueKey))
-
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(response); err != nil {
-				t.Errorf("Failed to encode response: %v", err)
-			}
-		}
-	}))
+	fake, server := newFakeJira(t, []map[string]interface{}{
+		createMinimalIssue("PROJ-100", "Issue PROJ-100"),
+		createMinimalIssue("PROJ-101", "Issue PROJ-101"),
+	}, "PROJ-100", "PROJ-101")
 	defer server.Close()
 
 	client := NewClient(server.URL, "user@example.com", "token123", "basic")
@@ -1176,76 +1151,23 @@ func TestFetchIssuesByLabel(t *testing.T) {
 		t.Errorf("Expected 2 issues, got %d", len(export.Issues))
 	}
 
-	// Verify both issues were fetched
-	if !fetchedIssues["PROJ-100"] {
+	// Verify both issues were fetched, from the search response alone
+	if fake.fetched["PROJ-100"] != 1 {
 		t.Error("Expected PROJ-100 to be fetched")
 	}
-	if !fetchedIssues["PROJ-101"] {
+	if fake.fetched["PROJ-101"] != 1 {
 		t.Error("Expected PROJ-101 to be fetched")
 	}
+	if fake.requests() != 1 {
+		t.Errorf("Expected a single search request, got %d", fake.requests())
+	}
 }
 
 func TestFetchIssuesByLabelWithDependencies(t *testing.T) {
-	fetchedIssues := make(map[string]bool)
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.URL.Path {
-		case "/rest/api/3/search/jql":
-			// Return search results
-			response := map[string]interface{}{
-				"issues": []map[string]interface{}{
-					{"key": "PROJ-100"},
-				},
-				"total": 1,
-			}
-
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(response); err != nil {
-				t.Errorf("Failed to encode response: %v", err)
-			}
-		default:
-			// Fetch individual issues
-			issueKey := r.URL.Path[len("/rest/api/2/issue/"):]
-			fetchedIssues[issueKey] = true
-
-			var response map[string]interface{}
-
-			switch issueKey {
-			case "PROJ-100":
-				response = map[string]interface{}{
-					"key": "PROJ-100",
-					"id":  "100",
-					"fields": map[string]interface{}{
-						"summary": "Main issue",
-						"issuetype": map[string]interface{}{
-							"name": "Story",
-						},
-						"status": map[string]interface{}{
-							"name": "Open",
-							"statusCategory": map[string]interface{}{
-								"key": "new",
-							},
-						},
-						"priority": map[string]interface{}{
-							"name": "Medium",
-						},
-						"created": "2024-01-01T10:00:00.000+0000",
-						"updated": "2024-01-01T10:00:00.000+0000",
-						"subtasks": []map[string]interface{}{
-							{"key": "PROJ-101"},
-						},
-					},
-				}
-			case "PROJ-101":
-				response = createMinimalIssue("PROJ-101", "Subtask")
-			}
-
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(response); err != nil {
-				t.Errorf("Failed to encode response: %v", err)
-			}
-		}
-	}))
+	fake, server := newFakeJira(t, []map[string]interface{}{
+		withSubtasks(createMinimalIssue("PROJ-100", "Main issue"), "PROJ-101"),
+		createMinimalIssue("PROJ-101", "Subtask"),
+	}, "PROJ-100")
 	defer server.Close()
 
 	client := NewClient(server.URL, "user@example.com", "token123", "basic")
@@ -1261,12 +1183,16 @@ func TestFetchIssuesByLabelWithDependencies(t *testing.T) {
 	}
 
 	// Verify both were fetched
-	if !fetchedIssues["PROJ-100"] {
+	if fake.fetched["PROJ-100"] != 1 {
 		t.Error("Expected PROJ-100 to be fetched")
 	}
-	if !fetchedIssues["PROJ-101"] {
+	if fake.fetched["PROJ-101"] != 1 {
 		t.Error("Expected PROJ-101 (subtask) to be fetched")
 	}
+
+	if fake.searches[1] != "key in (PROJ-101)" {
+		t.Errorf("Expected subtask to be fetched with 'key in (PROJ-101)', got '%s'", fake.searches[1])
+	}
 }
 
 func TestFetchIssuesByLabelNoResults(t *testing.T) {
@@ -1460,44 +1386,11 @@ func TestGetCurrentUserServerError(t *testing.T) {
 }
 
 func TestFetchIssuesByJQL(t *testing.T) {
-	fetchedIssues := make(map[string]bool)
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.URL.Path {
-		case "/rest/api/3/search/jql":
-			// Verify JQL query is passed correctly
-			jql := r.URL.Query().Get("jql")
-			if jql == "" {
-				t.Error("Expected JQL query parameter to be present")
-			}
-
-			// Return search results
-			response := map[string]interface{}{
-				"issues": []map[string]interface{}{
-					{"key": "PROJ-100"},
-					{"key": "PROJ-101"},
-					{"key": "PROJ-102"},
-				},
-				"total": 3,
-			}
-
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(response); err != nil {
-				t.Errorf("Failed to encode response: %v", err)
-			}
-		default:
-			// Fetch individual issues
-			issueKey := r.URL.Path[len("/rest/api/2/issue/"):]
-			fetchedIssues[issueKey] = true
-
-			response := createMinimalIssue(issueKey, fmt.Sprintf("Issue %s", issueKey))
-
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(response); err != nil {
-				t.Errorf("Failed to encode response: %v", err)
-			}
-		}
-	}))
+	fake, server := newFakeJira(t, []map[string]interface{}{
+		createMinimalIssue("PROJ-100", "Issue PROJ-100"),
+		createMinimalIssue("PROJ-101", "Issue PROJ-101"),
+		createMinimalIssue("PROJ-102", "Issue PROJ-102"),
+	}, "PROJ-100", "PROJ-101", "PROJ-102")
 	defer server.Close()
 
 	client := NewClient(server.URL, "user@example.com", "token123", "basic")
@@ -1515,88 +1408,38 @@ func TestFetchIssuesByJQL(t *testing.T) {
 		t.Errorf("Expected 3 issues, got %d", len(export.Issues))
 	}
 
+	// Verify JQL query is passed correctly
+	if fake.searches[0] != "project = PROJ AND status = Open" {
+		t.Errorf("Expected JQL to be passed through, got '%s'", fake.searches[0])
+	}
+
 	// Verify all issues were fetched
 	expectedKeys := []string{"PROJ-100", "PROJ-101", "PROJ-102"}
 	for _, key := range expectedKeys {
-		if !fetchedIssues[key] {
+		if fake.fetched[key] != 1 {
 			t.Errorf("Expected %s to be fetched", key)
 		}
 	}
 }
 
 func TestFetchIssuesByJQLWithDependencies(t *testing.T) {
-	fetchedIssues := make(map[string]bool)
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.URL.Path {
-		case "/rest/api/3/search/jql":
-			// Return search results
-			response := map[string]interface{}{
-				"issues": []map[string]interface{}{
-					{"key": "PROJ-100"},
-				},
-				"total": 1,
-			}
-
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(response); err != nil {
-				t.Errorf("Failed to encode response: %v", err)
-			}
-		default:
-			// Fetch individual issues
-			issueKey := r.URL.Path[len("/rest/api/2/issue/"):]
-			fetchedIssues[issueKey] = true
-
-			var response map[string]interface{}
-
-			switch issueKey {
-			case "PROJ-100":
-				response = map[string]interface{}{
-					"key": "PROJ-100",
-					"id":  "100",
-					"fields": map[string]interface{}{
-						"summary": "Main issue",
-						"issuetype": map[string]interface{}{
-							"name": "Story",
-						},
-						"status": map[string]interface{}{
-							"name": "Open",
-							"statusCategory": map[string]interface{}{
-								"key": "new",
-							},
-						},
-						"priority": map[string]interface{}{
-							"name": "Medium",
-						},
-						"created": "2024-01-01T10:00:00.000+0000",
-						"updated": "2024-01-01T10:00:00.000+0000",
-						"subtasks": []map[string]interface{}{
-							{"key": "PROJ-101"},
-						},
-						"issuelinks": []map[string]interface{}{
-							{
-								"type": map[string]interface{}{
-									"name": "Blocks",
-								},
-								"outwardIssue": map[string]interface{}{
-									"key": "PROJ-102",
-								},
-							},
-						},
-					},
-				}
-			case "PROJ-101":
-				response = createMinimalIssue("PROJ-101", "Subtask")
-			case "PROJ-102":
-				response = createMinimalIssue("PROJ-102", "Linked issue")
-			}
+	main := withSubtasks(createMinimalIssue("PROJ-100", "Main issue"), "PROJ-101")
+	main["fields"].(map[string]interface{})["issuelinks"] = []map[string]interface{}{
+		{
+			"type": map[string]interface{}{
+				"name": "Blocks",
+			},
+			"outwardIssue": map[string]interface{}{
+				"key": "PROJ-102",
+			},
+		},
+	}
 
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(response); err != nil {
-				t.Errorf("Failed to encode response: %v", err)
-			}
-		}
-	}))
+	fake, server := newFakeJira(t, []map[string]interface{}{
+		main,
+		createMinimalIssue("PROJ-101", "Subtask"),
+		createMinimalIssue("PROJ-102", "Linked issue"),
+	}, "PROJ-100")
 	defer server.Close()
 
 	client := NewClient(server.URL, "user@example.com", "token123", "basic")
@@ -1614,10 +1457,15 @@ func TestFetchIssuesByJQLWithDependencies(t *testing.T) {
 	// Verify all were fetched
 	expectedKeys := []string{"PROJ-100", "PROJ-101", "PROJ-102"}
 	for _, key := range expectedKeys {
-		if !fetchedIssues[key] {
+		if fake.fetched[key] != 1 {
 			t.Errorf("Expected %s to be fetched", key)
 		}
 	}
+
+	// The subtask and linked issue are fetched in one batch
+	if len(fake.searches) != 2 || fake.searches[1] != "key in (PROJ-101, PROJ-102)" {
+		t.Errorf("Expected one batched dependency search, got %v", fake.searches)
+	}
 }
 
 func TestFetchIssuesByJQLNoResults(t *testing.T) {
@@ -1874,100 +1722,25 @@ func TestFetchIssuesByJQLPaginationWarning(t *testing.T) {
 }
 
 func TestFetchIssuesByJQLWithCircularDependencies(t *testing.T) {
-	fetchCount := make(map[string]int)
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.URL.Path {
-		case "/rest/api/3/search/jql":
-			response := map[string]interface{}{
-				"issues": []map[string]interface{}{
-					{"key": "PROJ-1"},
+	blocks := func(key string) []map[string]interface{} {
+		return []map[string]interface{}{
+			{
+				"type": map[string]interface{}{
+					"name": "Blocks",
 				},
-				"total": 1,
-			}
+				"outwardIssue": map[string]interface{}{
+					"key": key,
+				},
+			},
+		}
+	}
 
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(response); err != nil {
-				t.Errorf("Failed to encode response: %v", err)
-			}
-		default:
-			issueKey := r.URL.Path[len("/rest/api/2/issue/"):]
-			fetchCount[issueKey]++
-
-			var response map[string]interface{}
-			switch issueKey {
-			case "PROJ-1":
-				response = map[string]interface{}{
-					"key": "PROJ-1",
-					"id":  "1",
-					"fields": map[string]interface{}{
-						"summary": "Issue 1",
-						"issuetype": map[string]interface{}{
-							"name": "Story",
-						},
-						"status": map[string]interface{}{
-							"name": "Open",
-							"statusCategory": map[string]interface{}{
-								"key": "new",
-							},
-						},
-						"priority": map[string]interface{}{
-							"name": "Medium",
-						},
-						"created": "2024-01-01T10:00:00.000+0000",
-						"updated": "2024-01-01T10:00:00.000+0000",
-						"issuelinks": []map[string]interface{}{
-							{
-								"type": map[string]interface{}{
-									"name": "Blocks",
-								},
-								"outwardIssue": map[string]interface{}{
-									"key": "PROJ-2",
-								},
-							},
-						},
-					},
-				}
-			case "PROJ-2":
-				response = map[string]interface{}{
-					"key": "PROJ-2",
-					"id":  "2",
-					"fields": map[string]interface{}{
-						"summary": "Issue 2",
-						"issuetype": map[string]interface{}{
-							"name": "Story",
-						},
-						"status": map[string]interface{}{
-							"name": "Open",
-							"statusCategory": map[string]interface{}{
-								"key": "new",
-							},
-						},
-						"priority": map[string]interface{}{
-							"name": "Medium",
-						},
-						"created": "2024-01-01T10:00:00.000+0000",
-						"updated": "2024-01-01T10:00:00.000+0000",
-						"issuelinks": []map[string]interface{}{
-							{
-								"type": map[string]interface{}{
-									"name": "Blocks",
-								},
-								"outwardIssue": map[string]interface{}{
-									"key": "PROJ-1", // Circular reference back to PROJ-1
-								},
-							},
-						},
-					},
-				}
-			}
+	issue1 := createMinimalIssue("PROJ-1", "Issue 1")
+	issue1["fields"].(map[string]interface{})["issuelinks"] = blocks("PROJ-2")
+	issue2 := createMinimalIssue("PROJ-2", "Issue 2")
+	issue2["fields"].(map[string]interface{})["issuelinks"] = blocks("PROJ-1") // Circular reference back to PROJ-1
 
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(response); err != nil {
-				t.Errorf("Failed to encode response: %v", err)
-			}
-		}
-	}))
+	fake, server := newFakeJira(t, []map[string]interface{}{issue1, issue2}, "PROJ-1")
 	defer server.Close()
 
 	client := NewClient(server.URL, "user@example.com", "token123", "basic")
@@ -1983,7 +1756,7 @@ func TestFetchIssuesByJQLWithCircularDependencies(t *testing.T) {
 	}
 
 	// Each issue should only be fetched once
-	for key, count := range fetchCount {
+	for key, count := range fake.fetched {
 		if count > 1 {
 			t.Errorf("Issue %s was fetched %d times (expected 1)", key, count)
 		}
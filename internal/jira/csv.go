@@ -0,0 +1,272 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "github.com/conallob/jira-beads-sync/gen/jira"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// linkColumn matches the issue link columns of a CSV export, e.g.
+// "Outward issue link (Blocks)"
+var linkColumn = regexp.MustCompile(`(?i)^(inward|outward) issue link \((.+)\)$`)
+
+// linkPhrases are the inward and outward descriptions of Jira's default link
+// types. CSV exports only name the link type.
+var linkPhrases = map[string][2]string{
+	"blocks":     {"is blocked by", "blocks"},
+	"cloners":    {"is cloned by", "clones"},
+	"duplicate":  {"is duplicated by", "duplicates"},
+	"relates":    {"relates to", "relates to"},
+	"dependency": {"is depended on by", "depends on"},
+	"depends":    {"is depended on by", "depends on"},
+}
+
+// csvDateLayouts are the date formats of CSV exports: the default
+// "dd/MMM/yy h:mm a" user format, and ISO formats for instances configured
+// with them
+var csvDateLayouts = []string{
+	"2/Jan/06 3:04 PM",
+	"2/Jan/06",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05.000-0700",
+	time.RFC3339,
+	"2006-01-02",
+}
+
+// csvHeader indexes the columns of a CSV export by lower-case name. Jira
+// repeats a column once per value for multi-valued fields such as Labels.
+type csvHeader map[string][]int
+
+// csvRow is one issue of a CSV export
+type csvRow struct {
+	header csvHeader
+	record []string
+}
+
+// values returns the non-empty values of all columns with any of the names
+func (r csvRow) values(names ...string) []string {
+	var values []string
+	for _, name := range names {
+		for _, i := range r.header[strings.ToLower(name)] {
+			if i < len(r.record) {
+				if value := strings.TrimSpace(r.record[i]); value != "" {
+					values = append(values, value)
+				}
+			}
+		}
+	}
+	return values
+}
+
+// value returns the first non-empty value of the columns with any of the names
+func (r csvRow) value(names ...string) string {
+	if values := r.values(names...); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// ParseCSV parses a Jira CSV export into protobuf. Repeated columns are
+// collapsed into lists, and issue link columns into links.
+func (a *Adapter) ParseCSV(data []byte) (*pb.Export, error) {
+	a.diagnostics = nil
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	names, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Jira CSV export: %w", err)
+	}
+	header := make(csvHeader)
+	for i, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		header[key] = append(header[key], i)
+	}
+	if _, ok := header["issue key"]; !ok {
+		return nil, fmt.Errorf("failed to parse Jira CSV export: no \"Issue key\" column")
+	}
+
+	export := &pb.Export{}
+	var rows []csvRow
+	for index := 0; ; index++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Jira CSV export: %w", err)
+		}
+		row := csvRow{header: header, record: record}
+		issue, problems := a.convertCSVRow(row, names)
+		diagnostics, skip, err := a.checkIssue(index, issue, problems, csvPaths)
+		if err != nil {
+			return nil, err
+		}
+		a.diagnostics = append(a.diagnostics, diagnostics...)
+		if skip {
+			continue
+		}
+		export.Issues = append(export.Issues, issue)
+		rows = append(rows, row)
+	}
+	if len(export.Issues) == 0 {
+		return nil, fmt.Errorf("validation failed: export contains no issues")
+	}
+
+	// Only subtasks have a "Parent id" in Server exports
+	subtaskParents := make([]bool, len(rows))
+	for i, row := range rows {
+		subtaskParents[i] = row.value("Parent id") != ""
+	}
+	resolveReferences(export, subtaskParents)
+
+	return export, nil
+}
+
+// convertCSVRow converts one row of a CSV export to protobuf, leaving out and
+// returning the columns that couldn't be parsed. Parents and links only carry
+// keys or IDs until resolveReferences runs.
+func (a *Adapter) convertCSVRow(row csvRow, names []string) (*pb.Issue, []*fieldError) {
+	issueType := row.value("Issue Type")
+	status := row.value("Status")
+
+	issue := &pb.Issue{
+		Id:  row.value("Issue id"),
+		Key: row.value("Issue key"),
+		Fields: &pb.Fields{
+			Summary:     row.value("Summary"),
+			Description: row.value("Description"),
+			IssueType: &pb.IssueType{
+				Name:    issueType,
+				Subtask: isSubtaskType(issueType),
+			},
+			Status: &pb.Status{
+				Name:           status,
+				StatusCategory: statusCategory(row.value("Status Category"), status),
+			},
+			Priority: &pb.Priority{Name: row.value("Priority")},
+			Labels:   row.values("Labels"),
+			Rank:     row.value("Custom field (Rank)"),
+		},
+	}
+
+	if name := row.value("Assignee"); name != "" {
+		issue.Fields.Assignee = &pb.User{DisplayName: name, AccountId: row.value("Assignee Id")}
+	}
+	if name := row.value("Reporter"); name != "" {
+		issue.Fields.Reporter = &pb.User{DisplayName: name, AccountId: row.value("Reporter Id")}
+	}
+	if name := row.value("Resolution"); name != "" {
+		issue.Fields.Resolution = &pb.Resolution{Name: name}
+	}
+
+	var problems []*fieldError
+	dates := []struct {
+		column   string
+		target   **timestamppb.Timestamp
+		location *time.Location
+	}{
+		{"Created", &issue.Fields.Created, a.location},
+		{"Updated", &issue.Fields.Updated, a.location},
+		// Due dates are plain dates, which are kept at midnight UTC
+		{"Due Date", &issue.Fields.DueDate, time.UTC},
+		{"Resolved", &issue.Fields.ResolutionDate, a.location},
+	}
+	for _, date := range dates {
+		value := row.value(date.column)
+		if value == "" {
+			continue
+		}
+		t, err := parseCSVDate(value, date.location)
+		if err != nil {
+			problems = append(problems, &fieldError{date.column, fmt.Errorf("invalid %s date: %w", date.column, err)})
+			continue
+		}
+		*date.target = timestamppb.New(t)
+	}
+
+	for _, name := range row.values("Fix Version/s", "Fix versions") {
+		issue.Fields.FixVersions = append(issue.Fields.FixVersions, &pb.Version{Name: name})
+	}
+	for _, name := range row.values("Affects Version/s", "Affects versions") {
+		issue.Fields.Versions = append(issue.Fields.Versions, &pb.Version{Name: name})
+	}
+	for _, name := range row.values("Component/s", "Components") {
+		issue.Fields.Components = append(issue.Fields.Components, &pb.Component{Name: name})
+	}
+
+	// Jira lists every sprint the issue has been in, the current one last
+	sprints := row.values("Sprint")
+	for i, name := range sprints {
+		if i == len(sprints)-1 {
+			issue.Fields.Sprint = &pb.Sprint{Name: name}
+		} else {
+			issue.Fields.ClosedSprints = append(issue.Fields.ClosedSprints, &pb.Sprint{Name: name})
+		}
+	}
+
+	for i, name := range names {
+		match := linkColumn.FindStringSubmatch(strings.TrimSpace(name))
+		if match == nil || i >= len(row.record) {
+			continue
+		}
+		for _, key := range strings.Split(row.record[i], ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				issue.Fields.IssueLinks = append(issue.Fields.IssueLinks, csvIssueLink(match[2], strings.EqualFold(match[1], "inward"), key))
+			}
+		}
+	}
+
+	// Cloud exports have a "Parent" column for every hierarchy level; Server
+	// exports a "Parent id" for subtasks and the "Epic Link" custom field
+	if parent := row.value("Parent", "Parent id", "Parent key"); parent != "" {
+		issue.Fields.Parent = &pb.Parent{Key: parent, Fields: &pb.LinkedFields{Summary: row.value("Parent summary")}}
+	} else if epic := row.value("Custom field (Epic Link)"); epic != "" {
+		issue.Fields.Parent = &pb.Parent{Key: epic, Fields: &pb.LinkedFields{IssueType: &pb.IssueType{Name: "Epic"}}}
+	}
+
+	return issue, problems
+}
+
+// csvIssueLink builds a link of the named type to another issue. An inward
+// link column holds the issues linking to this one, e.g. the blockers of an
+// "Inward issue link (Blocks)" column.
+func csvIssueLink(typeName string, inward bool, key string) *pb.IssueLink {
+	phrases, ok := linkPhrases[strings.ToLower(typeName)]
+	if !ok {
+		phrases = [2]string{strings.ToLower(typeName), strings.ToLower(typeName)}
+	}
+
+	link := &pb.IssueLink{
+		Type: &pb.IssueLinkType{Name: typeName, Inward: phrases[0], Outward: phrases[1]},
+	}
+	if inward {
+		link.InwardIssue = &pb.LinkedIssue{Key: key}
+	} else {
+		link.OutwardIssue = &pb.LinkedIssue{Key: key}
+	}
+	return link
+}
+
+// parseCSVDate parses a date in any of the formats CSV exports use. Dates
+// without a zone are taken to be in location, or UTC if it is nil.
+func parseCSVDate(value string, location *time.Location) (time.Time, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	for _, layout := range csvDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date %q", value)
+}
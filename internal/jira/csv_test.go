@@ -0,0 +1,223 @@
+package jira
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdapterParseCSVFile(t *testing.T) {
+	adapter := NewAdapter()
+	export, err := adapter.ParseFile("../../testdata/sample-jira-export.csv")
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	if len(export.Issues) != 4 {
+		t.Fatalf("Expected 4 issues, got %d", len(export.Issues))
+	}
+
+	issue := export.Issues[1]
+	if issue.Key != "PROJ-2" || issue.Id != "10002" {
+		t.Errorf("Expected PROJ-2 (10002), got %s (%s)", issue.Key, issue.Id)
+	}
+	if !issue.Fields.IssueType.Subtask {
+		t.Error("Expected Subtask issue type to be a subtask")
+	}
+	if issue.Fields.Status.StatusCategory.Key != "new" {
+		t.Errorf("Expected status category 'new', got %q", issue.Fields.Status.StatusCategory.Key)
+	}
+	if got := strings.Join(issue.Fields.Labels, ","); got != "api,backend" {
+		t.Errorf("Expected labels api,backend, got %s", got)
+	}
+	if len(issue.Fields.Components) != 1 || issue.Fields.Components[0].Name != "API" {
+		t.Errorf("Expected component API, got %v", issue.Fields.Components)
+	}
+	if len(issue.Fields.FixVersions) != 1 || issue.Fields.FixVersions[0].Name != "1.0" {
+		t.Errorf("Expected fix version 1.0, got %v", issue.Fields.FixVersions)
+	}
+
+	want := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	if got := issue.Fields.Created.AsTime(); !got.Equal(want) {
+		t.Errorf("Expected created %v, got %v", want, got)
+	}
+
+	// The Epic Link resolves to the epic in the export
+	parent := issue.Fields.Parent
+	if parent == nil || parent.Key != "PROJ-1" || parent.Id != "10001" {
+		t.Fatalf("Expected parent PROJ-1, got %v", parent)
+	}
+	if parent.Fields.IssueType.Name != "Epic" || parent.Fields.Summary != "Implement User Authentication" {
+		t.Errorf("Expected parent fields from the epic, got %v", parent.Fields)
+	}
+
+	// "Inward issue link (Blocks)" holds the blockers
+	if len(issue.Fields.IssueLinks) != 1 {
+		t.Fatalf("Expected 1 issue link, got %d", len(issue.Fields.IssueLinks))
+	}
+	link := issue.Fields.IssueLinks[0]
+	if link.Type.Name != "Blocks" || link.Type.Inward != "is blocked by" {
+		t.Errorf("Expected Blocks link type, got %v", link.Type)
+	}
+	if link.InwardIssue == nil || link.InwardIssue.Key != "PROJ-4" || link.InwardIssue.Fields.Summary != "Setup database schema" {
+		t.Errorf("Expected inward issue PROJ-4, got %v", link.InwardIssue)
+	}
+
+	// Multi-line descriptions survive quoting
+	if got := export.Issues[2].Fields.Description; !strings.Contains(got, "\n\nAccepts email") {
+		t.Errorf("Expected multi-line description, got %q", got)
+	}
+
+	done := export.Issues[3]
+	if done.Fields.Resolution == nil || done.Fields.Resolution.Name != "Done" || done.Fields.ResolutionDate == nil {
+		t.Errorf("Expected resolution Done with date, got %v %v", done.Fields.Resolution, done.Fields.ResolutionDate)
+	}
+	if done.Fields.Status.StatusCategory.Key != "done" {
+		t.Errorf("Expected status category 'done', got %q", done.Fields.Status.StatusCategory.Key)
+	}
+}
+
+func TestAdapterParseCSVSubtasks(t *testing.T) {
+	csv := "Summary,Issue key,Issue id,Parent id,Issue Type,Status,Status Category,Sprint,Sprint\n" +
+		"Parent story,PROJ-1,100,,Story,In Review,In Progress,Sprint 1,Sprint 2\n" +
+		"Child task,PROJ-2,101,100,Technical task,Open,To Do,,\n"
+
+	export, err := NewAdapter().ParseCSV([]byte(csv))
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+
+	story, child := export.Issues[0], export.Issues[1]
+	if story.Fields.Status.StatusCategory.Key != "indeterminate" {
+		t.Errorf("Expected status category from column, got %q", story.Fields.Status.StatusCategory.Key)
+	}
+	if story.Fields.Sprint == nil || story.Fields.Sprint.Name != "Sprint 2" {
+		t.Errorf("Expected current sprint 'Sprint 2', got %v", story.Fields.Sprint)
+	}
+	if len(story.Fields.ClosedSprints) != 1 || story.Fields.ClosedSprints[0].Name != "Sprint 1" {
+		t.Errorf("Expected closed sprint 'Sprint 1', got %v", story.Fields.ClosedSprints)
+	}
+
+	// A parent ID resolves to the key, and makes the child a subtask
+	if child.Fields.Parent == nil || child.Fields.Parent.Key != "PROJ-1" {
+		t.Fatalf("Expected parent PROJ-1, got %v", child.Fields.Parent)
+	}
+	if !child.Fields.IssueType.Subtask {
+		t.Error("Expected child with a parent id to be a subtask")
+	}
+	if len(story.Fields.Subtasks) != 1 || story.Fields.Subtasks[0].Key != "PROJ-2" {
+		t.Errorf("Expected subtask PROJ-2 on the parent, got %v", story.Fields.Subtasks)
+	}
+}
+
+func TestAdapterParseCSVLinks(t *testing.T) {
+	csv := "Summary,Issue key,Issue Type,Outward issue link (Blocks),Outward issue link (Blocks),Inward issue link (Cloners),Outward issue link (Problem/Incident)\n" +
+		"Test,PROJ-1,Task,PROJ-2,PROJ-3,OTHER-9,PROJ-4\n"
+
+	export, err := NewAdapter().ParseCSV([]byte(csv))
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+
+	links := export.Issues[0].Fields.IssueLinks
+	if len(links) != 4 {
+		t.Fatalf("Expected 4 links, got %d", len(links))
+	}
+
+	tests := []struct {
+		typeName string
+		outward  string
+		key      string
+	}{
+		{"Blocks", "blocks", "PROJ-2"},
+		{"Blocks", "blocks", "PROJ-3"},
+		{"Cloners", "clones", "OTHER-9"},
+		{"Problem/Incident", "problem/incident", "PROJ-4"},
+	}
+	for i, tt := range tests {
+		link := links[i]
+		if link.Type.Name != tt.typeName || link.Type.Outward != tt.outward {
+			t.Errorf("Link %d: expected type %s (%s), got %v", i, tt.typeName, tt.outward, link.Type)
+		}
+		linked := link.OutwardIssue
+		if tt.typeName == "Cloners" {
+			linked = link.InwardIssue
+		}
+		if linked == nil || linked.Key != tt.key {
+			t.Errorf("Link %d: expected issue %s, got %v", i, tt.key, linked)
+		}
+	}
+}
+
+func TestAdapterParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		csv      string
+		errorMsg string
+	}{
+		{"no issue key column", "Summary,Status\nTest,Open\n", "no \"Issue key\" column"},
+		{"no issues", "Summary,Issue key,Issue Type\n", "export contains no issues"},
+		{"missing summary", "Summary,Issue key,Issue Type\n,PROJ-1,Task\n", "has no summary"},
+		{"invalid date", "Summary,Issue key,Issue Type,Created\nTest,PROJ-1,Task,yesterday\n", "invalid Created date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAdapter().ParseCSV([]byte(tt.csv))
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestParseCSVDate(t *testing.T) {
+	newYork := time.FixedZone("EST", -5*60*60)
+
+	tests := []struct {
+		value    string
+		location *time.Location
+		want     time.Time
+	}{
+		{"24/Jan/24 9:15 AM", nil, time.Date(2024, 1, 24, 9, 15, 0, 0, time.UTC)},
+		{"4/Feb/24 12:30 PM", nil, time.Date(2024, 2, 4, 12, 30, 0, 0, time.UTC)},
+		{"05/Mar/24", nil, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-24 09:15", nil, time.Date(2024, 1, 24, 9, 15, 0, 0, time.UTC)},
+		{"2024-01-24", nil, time.Date(2024, 1, 24, 0, 0, 0, 0, time.UTC)},
+		// Dates without a zone are in the given location, those with one keep it
+		{"24/Jan/24 9:15 AM", newYork, time.Date(2024, 1, 24, 14, 15, 0, 0, time.UTC)},
+		{"2024-01-24T09:15:00.000+0100", newYork, time.Date(2024, 1, 24, 8, 15, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseCSVDate(tt.value, tt.location)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseCSVLocation(t *testing.T) {
+	csv := "Summary,Issue key,Issue Type,Created,Due Date\nTest,PROJ-1,Task,24/Jan/24 9:15 AM,05/Mar/24\n"
+
+	adapter := NewAdapter()
+	adapter.SetLocation(time.FixedZone("EST", -5*60*60))
+	export, err := adapter.ParseCSV([]byte(csv))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	fields := export.Issues[0].Fields
+	if want := time.Date(2024, 1, 24, 14, 15, 0, 0, time.UTC); !fields.Created.AsTime().Equal(want) {
+		t.Errorf("Expected created %v, got %v", want, fields.Created.AsTime())
+	}
+	// Due dates are plain dates and aren't shifted
+	if want := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC); !fields.DueDate.AsTime().Equal(want) {
+		t.Errorf("Expected due date %v, got %v", want, fields.DueDate.AsTime())
+	}
+}
@@ -0,0 +1,224 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	pb "github.com/conallob/jira-beads-sync/gen/jira"
+)
+
+// jsonRemoteLink represents an entry returned by /rest/api/2/issue/{key}/remotelink
+type jsonRemoteLink struct {
+	ID           int64  `json:"id"`
+	Relationship string `json:"relationship"`
+	Object       struct {
+		URL   string `json:"url"`
+		Title string `json:"title"`
+	} `json:"object"`
+	Application struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	} `json:"application"`
+}
+
+// jsonDevSummary represents the response of the dev-status summary endpoint
+type jsonDevSummary struct {
+	Summary map[string]struct {
+		ByInstanceType map[string]struct {
+			Count int    `json:"count"`
+			Name  string `json:"name"`
+		} `json:"byInstanceType"`
+	} `json:"summary"`
+}
+
+// jsonDevDetail represents the response of the dev-status detail endpoint
+type jsonDevDetail struct {
+	Detail []struct {
+		PullRequests []struct {
+			ID     string `json:"id"`
+			Name   string `json:"name"`
+			URL    string `json:"url"`
+			Status string `json:"status"`
+			Source struct {
+				Branch string `json:"branch"`
+			} `json:"source"`
+			Destination struct {
+				Branch string `json:"branch"`
+			} `json:"destination"`
+			RepositoryName string `json:"repositoryName"`
+			RepositoryURL  string `json:"repositoryUrl"`
+		} `json:"pullRequests"`
+		Branches []struct {
+			Name       string `json:"name"`
+			Repository struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"repository"`
+		} `json:"branches"`
+	} `json:"detail"`
+}
+
+// SetFetchDevelopment controls whether remote links and development
+// information (pull requests, branches) are fetched for every imported issue
+func (c *Client) SetFetchDevelopment(enabled bool) {
+	c.fetchDevelopment = enabled
+}
+
+// FetchRemoteLinks fetches the remote links of an issue
+func (c *Client) FetchRemoteLinks(issueKey string) ([]*pb.RemoteLink, error) {
+	apiURL := fmt.Sprintf("%s/rest/api/2/issue/%s/remotelink", c.baseURL, issueKey)
+
+	body, err := c.get(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch remote links of %s: %w", issueKey, err)
+	}
+
+	var jsonLinks []jsonRemoteLink
+	if err := json.Unmarshal(body, &jsonLinks); err != nil {
+		return nil, fmt.Errorf("failed to fetch remote links of %s: failed to parse response: %w", issueKey, err)
+	}
+	c.snapshot.recordRemoteLinks(issueKey, body)
+
+	return convertRemoteLinks(jsonLinks), nil
+}
+
+// convertRemoteLinks converts the remote links of an issue
+func convertRemoteLinks(jsonLinks []jsonRemoteLink) []*pb.RemoteLink {
+	links := make([]*pb.RemoteLink, 0, len(jsonLinks))
+	for _, link := range jsonLinks {
+		links = append(links, &pb.RemoteLink{
+			Id:              link.ID,
+			Url:             link.Object.URL,
+			Title:           link.Object.Title,
+			Relationship:    link.Relationship,
+			ApplicationType: link.Application.Type,
+			ApplicationName: link.Application.Name,
+		})
+	}
+	return links
+}
+
+// FetchDevelopment fetches the pull requests and repositories shown in an
+// issue's development panel. issueID is the numeric issue ID, not the key.
+func (c *Client) FetchDevelopment(issueID string) ([]*pb.PullRequest, []string, error) {
+	summaryURL := fmt.Sprintf("%s/rest/dev-status/latest/issue/summary?issueId=%s",
+		c.baseURL, url.QueryEscape(issueID))
+
+	var summary jsonDevSummary
+	if err := c.getJSON(summaryURL, &summary); err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch development summary of issue %s: %w", issueID, err)
+	}
+
+	details := make([]jsonDevDetail, 0)
+	bodies := make([]json.RawMessage, 0)
+	for _, dataType := range []string{"pullrequest", "branch"} {
+		for _, instanceType := range instanceTypes(summary, dataType) {
+			detailURL := fmt.Sprintf("%s/rest/dev-status/latest/issue/detail?issueId=%s&applicationType=%s&dataType=%s",
+				c.baseURL, url.QueryEscape(issueID), url.QueryEscape(instanceType), dataType)
+
+			body, err := c.get(detailURL)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to fetch %s details of issue %s: %w", dataType, issueID, err)
+			}
+			var detail jsonDevDetail
+			if err := json.Unmarshal(body, &detail); err != nil {
+				return nil, nil, fmt.Errorf("failed to fetch %s details of issue %s: failed to parse response: %w", dataType, issueID, err)
+			}
+			details = append(details, detail)
+			bodies = append(bodies, body)
+		}
+	}
+	c.snapshot.recordDevelopment(issueID, bodies)
+
+	pullRequests, repositories := convertDevelopment(details)
+	return pullRequests, repositories, nil
+}
+
+// convertDevelopment collects the pull requests of an issue's development
+// details, and the repositories of its pull requests and branches
+func convertDevelopment(details []jsonDevDetail) ([]*pb.PullRequest, []string) {
+	pullRequests := make([]*pb.PullRequest, 0)
+	repositories := make([]string, 0)
+	seenRepos := make(map[string]bool)
+	addRepository := func(repoURL string) {
+		if repoURL != "" && !seenRepos[repoURL] {
+			seenRepos[repoURL] = true
+			repositories = append(repositories, repoURL)
+		}
+	}
+
+	for _, detail := range details {
+		for _, d := range detail.Detail {
+			for _, pr := range d.PullRequests {
+				pullRequests = append(pullRequests, &pb.PullRequest{
+					Id:                pr.ID,
+					Name:              pr.Name,
+					Url:               pr.URL,
+					Status:            pr.Status,
+					RepositoryName:    pr.RepositoryName,
+					RepositoryUrl:     pr.RepositoryURL,
+					SourceBranch:      pr.Source.Branch,
+					DestinationBranch: pr.Destination.Branch,
+				})
+				addRepository(pr.RepositoryURL)
+			}
+			for _, branch := range d.Branches {
+				addRepository(branch.Repository.URL)
+			}
+		}
+	}
+
+	return pullRequests, repositories
+}
+
+// instanceTypes returns the application types (e.g. "GitHub") that have data
+// of the given type in a development summary, in a stable order
+func instanceTypes(summary jsonDevSummary, dataType string) []string {
+	types := make([]string, 0)
+	for instanceType, instance := range summary.Summary[dataType].ByInstanceType {
+		if instance.Count > 0 {
+			types = append(types, instanceType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// attachDevelopment populates an issue's remote links, pull requests and
+// repositories. Failures are reported as warnings, since the development
+// panel is unavailable on many Jira instances and shouldn't block an import.
+func (c *Client) attachDevelopment(issue *pb.Issue) {
+	if !c.fetchDevelopment {
+		return
+	}
+
+	links, err := c.FetchRemoteLinks(issue.Key)
+	if err != nil {
+		fmt.Printf("⚠ Warning: %v\n", err)
+	} else {
+		issue.RemoteLinks = links
+	}
+
+	if c.noDevStatus {
+		return
+	}
+	pullRequests, repositories, err := c.FetchDevelopment(issue.Id)
+	var status *statusError
+	if errors.As(err, &status) && status.StatusCode == http.StatusNotFound {
+		// Without a development integration there is no dev-status API;
+		// asking again for every other issue would only repeat the 404
+		c.noDevStatus = true
+		fmt.Println("⚠ Warning: Jira has no development information, skipping pull requests for the remaining issues")
+		return
+	}
+	if err != nil {
+		fmt.Printf("⚠ Warning: %v\n", err)
+		return
+	}
+	issue.PullRequests = pullRequests
+	issue.Repositories = repositories
+}
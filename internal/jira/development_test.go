@@ -0,0 +1,245 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchRemoteLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/PROJ-1/remotelink" {
+			t.Errorf("Expected path '/rest/api/2/issue/PROJ-1/remotelink', got '%s'", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{
+			"id": 10000,
+			"relationship": "mentioned in",
+			"object": {"url": "https://github.com/acme/api/pull/12", "title": "Add login"},
+			"application": {"type": "com.github", "name": "GitHub"}
+		}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	links, err := client.FetchRemoteLinks("PROJ-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(links) != 1 {
+		t.Fatalf("Expected 1 remote link, got %d", len(links))
+	}
+	link := links[0]
+	if link.Id != 10000 || link.Url != "https://github.com/acme/api/pull/12" || link.Title != "Add login" {
+		t.Errorf("Unexpected remote link: %v", link)
+	}
+	if link.Relationship != "mentioned in" || link.ApplicationName != "GitHub" {
+		t.Errorf("Unexpected remote link relationship/application: %v", link)
+	}
+}
+
+func TestFetchDevelopment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("issueId") != "10001" {
+			t.Errorf("Expected issueId 10001, got '%s'", r.URL.Query().Get("issueId"))
+		}
+
+		var response interface{}
+		switch r.URL.Path {
+		case "/rest/dev-status/latest/issue/summary":
+			response = map[string]interface{}{
+				"summary": map[string]interface{}{
+					"pullrequest": map[string]interface{}{
+						"byInstanceType": map[string]interface{}{
+							"GitHub": map[string]interface{}{"count": 1, "name": "GitHub"},
+						},
+					},
+					"branch": map[string]interface{}{
+						"byInstanceType": map[string]interface{}{
+							"GitHub": map[string]interface{}{"count": 2, "name": "GitHub"},
+							"GitLab": map[string]interface{}{"count": 0, "name": "GitLab"},
+						},
+					},
+				},
+			}
+		case "/rest/dev-status/latest/issue/detail":
+			if r.URL.Query().Get("applicationType") != "GitHub" {
+				t.Errorf("Expected applicationType GitHub, got '%s'", r.URL.Query().Get("applicationType"))
+			}
+			switch r.URL.Query().Get("dataType") {
+			case "pullrequest":
+				response = map[string]interface{}{
+					"detail": []map[string]interface{}{{
+						"pullRequests": []map[string]interface{}{{
+							"id":             "#12",
+							"name":           "Add login",
+							"url":            "https://github.com/acme/api/pull/12",
+							"status":         "MERGED",
+							"source":         map[string]interface{}{"branch": "PROJ-1-login"},
+							"destination":    map[string]interface{}{"branch": "main"},
+							"repositoryName": "acme/api",
+							"repositoryUrl":  "https://github.com/acme/api",
+						}},
+					}},
+				}
+			case "branch":
+				response = map[string]interface{}{
+					"detail": []map[string]interface{}{{
+						"branches": []map[string]interface{}{
+							{"name": "PROJ-1-login", "repository": map[string]interface{}{"url": "https://github.com/acme/api"}},
+							{"name": "PROJ-1-web", "repository": map[string]interface{}{"url": "https://github.com/acme/web"}},
+						},
+					}},
+				}
+			default:
+				t.Errorf("Unexpected dataType: %s", r.URL.Query().Get("dataType"))
+			}
+		default:
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	pullRequests, repositories, err := client.FetchDevelopment("10001")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(pullRequests) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(pullRequests))
+	}
+	pr := pullRequests[0]
+	if pr.Url != "https://github.com/acme/api/pull/12" || pr.Status != "MERGED" {
+		t.Errorf("Unexpected pull request: %v", pr)
+	}
+	if pr.SourceBranch != "PROJ-1-login" || pr.DestinationBranch != "main" {
+		t.Errorf("Unexpected pull request branches: %v", pr)
+	}
+
+	expectedRepos := []string{"https://github.com/acme/api", "https://github.com/acme/web"}
+	if len(repositories) != len(expectedRepos) {
+		t.Fatalf("Expected repositories %v, got %v", expectedRepos, repositories)
+	}
+	for i, repo := range expectedRepos {
+		if repositories[i] != repo {
+			t.Errorf("Expected repository %s at %d, got %s", repo, i, repositories[i])
+		}
+	}
+}
+
+func TestFetchIssueWithDependenciesAttachesDevelopment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/rest/api/2/issue/PROJ-1":
+			if err := json.NewEncoder(w).Encode(createMinimalIssue("PROJ-1", "Main")); err != nil {
+				t.Errorf("Failed to encode response: %v", err)
+			}
+		case "/rest/api/2/issue/PROJ-1/remotelink":
+			_, _ = w.Write([]byte(`[{"id": 1, "object": {"url": "https://gitlab.com/acme/group/api/-/merge_requests/3"}}]`))
+		default:
+			// Development panel unavailable, e.g. no DVCS integration installed
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	client.SetFetchDevelopment(true)
+
+	export, err := client.FetchIssueWithDependencies("PROJ-1")
+	if err != nil {
+		t.Fatalf("Expected development failures to be non-fatal, got: %v", err)
+	}
+
+	issue := export.Issues[0]
+	if len(issue.RemoteLinks) != 1 {
+		t.Fatalf("Expected 1 remote link, got %d", len(issue.RemoteLinks))
+	}
+	if len(issue.PullRequests) != 0 || len(issue.Repositories) != 0 {
+		t.Errorf("Expected no development information, got %v / %v", issue.PullRequests, issue.Repositories)
+	}
+}
+
+func TestDevStatusUnavailableAskedOnce(t *testing.T) {
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/rest/api/2/issue/PROJ-1":
+			// PROJ-1 is blocked by PROJ-2, so both are imported
+			issue := createMinimalIssue("PROJ-1", "Main")
+			issue["fields"].(map[string]interface{})["issuelinks"] = []map[string]interface{}{
+				{
+					"id":          "1",
+					"type":        map[string]interface{}{"name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+					"inwardIssue": map[string]interface{}{"key": "PROJ-2"},
+				},
+			}
+			if err := json.NewEncoder(w).Encode(issue); err != nil {
+				t.Errorf("Failed to encode response: %v", err)
+			}
+		case "/rest/api/2/issue/PROJ-2":
+			if err := json.NewEncoder(w).Encode(createMinimalIssue("PROJ-2", "Blocker")); err != nil {
+				t.Errorf("Failed to encode response: %v", err)
+			}
+		case "/rest/api/2/issue/PROJ-1/remotelink", "/rest/api/2/issue/PROJ-2/remotelink":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			// No development integration installed
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	client.SetFetchDevelopment(true)
+
+	export, err := client.FetchIssueWithDependencies("PROJ-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(export.Issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %d", len(export.Issues))
+	}
+
+	if got := requests["/rest/dev-status/latest/issue/summary"]; got != 1 {
+		t.Errorf("Expected the unavailable dev-status API to be asked once, got %d requests", got)
+	}
+	if requests["/rest/api/2/issue/PROJ-2/remotelink"] != 1 {
+		t.Errorf("Expected remote links still fetched for every issue, got %v", requests)
+	}
+}
+
+func TestFetchDevelopmentDisabledByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/PROJ-1" {
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(createMinimalIssue("PROJ-1", "Main")); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	if _, err := client.FetchIssueWithDependencies("PROJ-1"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+}
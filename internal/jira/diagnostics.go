@@ -0,0 +1,160 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	pb "github.com/conallob/jira-beads-sync/gen/jira"
+)
+
+// defaultIssueType is the issue type lenient parsing gives issues without one
+const defaultIssueType = "Task"
+
+// Diagnostic is a problem with an issue of an export that lenient parsing
+// skipped or repaired instead of failing the export
+type Diagnostic struct {
+	Source  string `json:"source,omitempty"` // export file, when known
+	Index   int    `json:"index"`            // position of the issue in the export
+	Key     string `json:"key,omitempty"`
+	Path    string `json:"path,omitempty"` // e.g. "fields.created", or the column of a CSV export
+	Problem string `json:"problem"`
+	Action  string `json:"action"` // what lenient parsing did about it
+}
+
+// String formats the diagnostic as a single line of a report
+func (d Diagnostic) String() string {
+	issue := d.Key
+	if issue == "" {
+		issue = fmt.Sprintf("issue at index %d", d.Index)
+	}
+	if d.Path != "" {
+		issue += " " + d.Path
+	}
+	if d.Source != "" {
+		issue = d.Source + ": " + issue
+	}
+	return fmt.Sprintf("%s: %s (%s)", issue, d.Problem, d.Action)
+}
+
+// fieldError is a field of an issue that couldn't be parsed. Strict parsing
+// fails with it; lenient parsing leaves the field out and reports it.
+type fieldError struct {
+	path string
+	err  error
+}
+
+func (e *fieldError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.err
+}
+
+// issuePaths locates the fields every issue needs in an export format
+type issuePaths struct {
+	key       string
+	summary   string
+	issueType string
+}
+
+var (
+	jsonPaths = issuePaths{key: "key", summary: "fields.summary", issueType: "fields.issuetype.name"}
+	csvPaths  = issuePaths{key: "Issue key", summary: "Summary", issueType: "Issue Type"}
+	xmlPaths  = issuePaths{key: "key", summary: "summary", issueType: "type"}
+)
+
+// SetLenient sets whether parsing skips or repairs bad issues instead of
+// failing. Lenient parsing leaves out fields that can't be parsed, gives an
+// issue without a summary its key as summary and one without an issue type
+// the type Task, and skips issues without a key or that can't be decoded.
+// Each problem is reported as a Diagnostic.
+func (a *Adapter) SetLenient(lenient bool) {
+	a.lenient = lenient
+}
+
+// Lenient returns whether parsing skips or repairs bad issues
+func (a *Adapter) Lenient() bool {
+	return a.lenient
+}
+
+// Diagnostics returns the problems lenient parsing skipped or repaired in
+// the export last parsed with Parse, ParseCSV, ParseXML or ParseAny.
+// IssueDecoder reports its own with IssueDecoder.Diagnostics.
+func (a *Adapter) Diagnostics() []Diagnostic {
+	return a.diagnostics
+}
+
+// checkIssue applies the parse mode to an issue and the fields of it that
+// couldn't be parsed. Strict parsing fails on the first problem or invalid
+// issue. Lenient parsing repairs what it can and returns what it did; skip
+// reports an issue to be left out of the export.
+func (a *Adapter) checkIssue(index int, issue *pb.Issue, problems []*fieldError, paths issuePaths) (diagnostics []Diagnostic, skip bool, err error) {
+	if !a.lenient {
+		if len(problems) > 0 {
+			return nil, false, fmt.Errorf("failed to convert issue %s: %w", issue.Key, problems[0])
+		}
+		if err := a.validateIssue(index, issue); err != nil {
+			return nil, false, fmt.Errorf("validation failed: %w", err)
+		}
+		return nil, false, nil
+	}
+
+	report := func(path, problem, action string) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Index:   index,
+			Key:     issue.Key,
+			Path:    path,
+			Problem: problem,
+			Action:  action,
+		})
+	}
+
+	if issue.Key == "" {
+		report(paths.key, "issue has no key", "skipped the issue")
+		return diagnostics, true, nil
+	}
+	for _, problem := range problems {
+		report(problem.path, problem.Error(), "left the field out")
+	}
+	if issue.Fields.Summary == "" {
+		issue.Fields.Summary = issue.Key
+		report(paths.summary, "issue has no summary", "used the key as summary")
+	}
+	if issue.Fields.IssueType.GetName() == "" {
+		issue.Fields.IssueType = &pb.IssueType{Name: defaultIssueType}
+		report(paths.issueType, "issue has no issue type", "used issue type "+defaultIssueType)
+	}
+
+	return diagnostics, false, nil
+}
+
+// undecodableIssue reports a JSON issue that lenient parsing skipped because
+// it couldn't be decoded, e.g. as a field has the wrong type
+func undecodableIssue(index int, data json.RawMessage, err error) Diagnostic {
+	// The key is still worth reporting if the rest of the issue is broken
+	var identity struct {
+		Key string `json:"key"`
+	}
+	_ = json.Unmarshal(data, &identity)
+
+	diagnostic := Diagnostic{
+		Index:   index,
+		Key:     identity.Key,
+		Problem: err.Error(),
+		Action:  "skipped the issue",
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		diagnostic.Path = typeErr.Field
+		// jsonFields decodes itself, so its errors are relative to "fields"
+		switch name, _, _ := strings.Cut(typeErr.Field, "."); name {
+		case "id", "key", "self", "fields":
+		default:
+			diagnostic.Path = "fields." + typeErr.Field
+		}
+	}
+	return diagnostic
+}
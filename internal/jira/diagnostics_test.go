@@ -0,0 +1,154 @@
+package jira
+
+import (
+	"strings"
+	"testing"
+)
+
+// lenientExport has one good issue and one of every problem lenient parsing handles
+const lenientExport = `{"issues": [
+	{"id": "1", "key": "PROJ-1", "fields": {"summary": "Good", "issuetype": {"name": "Task"}}},
+	{"id": "2", "key": "PROJ-2", "fields": {"summary": "Bad dates", "issuetype": {"name": "Task"},
+		"created": "yesterday", "duedate": "2024-13-45",
+		"sprint": {"id": 1, "name": "Sprint 1", "startDate": "soon"}}},
+	{"id": "3", "key": "PROJ-3", "fields": {"issuetype": {"name": "Task"}}},
+	{"id": "4", "key": "PROJ-4", "fields": {"summary": "No type"}},
+	{"id": "5", "fields": {"summary": "No key", "issuetype": {"name": "Task"}}},
+	{"id": "6", "key": "PROJ-6", "fields": {"summary": 42, "issuetype": {"name": "Task"}}}
+]}`
+
+func TestAdapterParseLenient(t *testing.T) {
+	adapter := NewAdapter()
+	if _, err := adapter.Parse([]byte(lenientExport)); err == nil {
+		t.Fatal("Expected strict parsing to fail")
+	}
+
+	adapter.SetLenient(true)
+	export, err := adapter.Parse([]byte(lenientExport))
+	if err != nil {
+		t.Fatalf("Expected lenient parsing to succeed, got: %v", err)
+	}
+
+	var keys []string
+	for _, issue := range export.Issues {
+		keys = append(keys, issue.Key)
+	}
+	if got := strings.Join(keys, ","); got != "PROJ-1,PROJ-2,PROJ-3,PROJ-4" {
+		t.Fatalf("Expected PROJ-1 to PROJ-4, got %s", got)
+	}
+
+	dates := export.Issues[1].Fields
+	if dates.Created != nil || dates.DueDate != nil || dates.Sprint != nil {
+		t.Errorf("Expected invalid dates and sprint to be left out, got %v %v %v", dates.Created, dates.DueDate, dates.Sprint)
+	}
+	if got := export.Issues[2].Fields.Summary; got != "PROJ-3" {
+		t.Errorf("Expected missing summary to be the key, got %q", got)
+	}
+	if got := export.Issues[3].Fields.IssueType.Name; got != "Task" {
+		t.Errorf("Expected missing issue type to be Task, got %q", got)
+	}
+
+	tests := []struct {
+		index   int
+		key     string
+		path    string
+		problem string
+		action  string
+	}{
+		{1, "PROJ-2", "fields.created", "invalid created", "left the field out"},
+		{1, "PROJ-2", "fields.duedate", "invalid duedate", "left the field out"},
+		{1, "PROJ-2", "fields.sprint", "failed to convert sprint", "left the field out"},
+		{2, "PROJ-3", "fields.summary", "no summary", "used the key as summary"},
+		{3, "PROJ-4", "fields.issuetype.name", "no issue type", "used issue type Task"},
+		{4, "", "key", "no key", "skipped the issue"},
+		{5, "PROJ-6", "fields.summary", "cannot unmarshal", "skipped the issue"},
+	}
+
+	diagnostics := adapter.Diagnostics()
+	if len(diagnostics) != len(tests) {
+		t.Fatalf("Expected %d diagnostics, got %d: %v", len(tests), len(diagnostics), diagnostics)
+	}
+	// Issues that can't be decoded are reported as they are read, ahead of
+	// the problems of converted issues
+	byPath := make(map[string]Diagnostic)
+	for _, diagnostic := range diagnostics {
+		byPath[diagnostic.Key+" "+diagnostic.Path] = diagnostic
+	}
+	for _, tt := range tests {
+		diagnostic, ok := byPath[tt.key+" "+tt.path]
+		if !ok {
+			t.Errorf("Expected a diagnostic for %s %s, got %v", tt.key, tt.path, diagnostics)
+			continue
+		}
+		if diagnostic.Index != tt.index || !strings.Contains(diagnostic.Problem, tt.problem) || diagnostic.Action != tt.action {
+			t.Errorf("Expected %d %q (%s) for %s %s, got %+v", tt.index, tt.problem, tt.action, tt.key, tt.path, diagnostic)
+		}
+	}
+}
+
+func TestAdapterParseLenientNoIssues(t *testing.T) {
+	adapter := NewAdapter()
+	adapter.SetLenient(true)
+
+	_, err := adapter.Parse([]byte(`{"issues": [{"id": "1", "fields": {"summary": "No key"}}]}`))
+	if err == nil || !strings.Contains(err.Error(), "export contains no issues") {
+		t.Errorf("Expected an export of skipped issues to fail, got: %v", err)
+	}
+}
+
+func TestAdapterParseCSVLenient(t *testing.T) {
+	csv := "Summary,Issue key,Issue Type,Created\n" +
+		"Good,PROJ-1,Task,2024-01-02\n" +
+		",PROJ-2,Task,yesterday\n" +
+		"No key,,Task,\n"
+
+	adapter := NewAdapter()
+	adapter.SetLenient(true)
+	export, err := adapter.ParseCSV([]byte(csv))
+	if err != nil {
+		t.Fatalf("Expected lenient parsing to succeed, got: %v", err)
+	}
+
+	if len(export.Issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %d", len(export.Issues))
+	}
+	if issue := export.Issues[1]; issue.Fields.Summary != "PROJ-2" || issue.Fields.Created != nil {
+		t.Errorf("Expected PROJ-2 repaired without a created date, got %q %v", issue.Fields.Summary, issue.Fields.Created)
+	}
+
+	var got []string
+	for _, diagnostic := range adapter.Diagnostics() {
+		got = append(got, diagnostic.String())
+	}
+	want := []string{
+		`PROJ-2 Created: invalid Created date: unrecognised date "yesterday" (left the field out)`,
+		"PROJ-2 Summary: issue has no summary (used the key as summary)",
+		"issue at index 2 Issue key: issue has no key (skipped the issue)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected diagnostics:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestAdapterParseXMLLenient(t *testing.T) {
+	xml := `<rss version="0.92"><channel>
+		<item><key id="1">PROJ-1</key><summary>Bad date</summary><type>Task</type><created>yesterday</created></item>
+	</channel></rss>`
+
+	adapter := NewAdapter()
+	if _, err := adapter.ParseXML([]byte(xml)); err == nil || !strings.Contains(err.Error(), "invalid created date") {
+		t.Errorf("Expected strict parsing to fail on the date, got: %v", err)
+	}
+
+	adapter.SetLenient(true)
+	export, err := adapter.ParseXML([]byte(xml))
+	if err != nil {
+		t.Fatalf("Expected lenient parsing to succeed, got: %v", err)
+	}
+	if export.Issues[0].Fields.Created != nil {
+		t.Errorf("Expected the invalid date to be left out, got %v", export.Issues[0].Fields.Created)
+	}
+	if diagnostics := adapter.Diagnostics(); len(diagnostics) != 1 || diagnostics[0].Path != "created" {
+		t.Errorf("Expected a diagnostic for created, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,461 @@
+package jira
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// FixtureBaseURL replaces the Jira base URL in recorded fixtures
+const FixtureBaseURL = "https://jira.example.com"
+
+// fixtureHeaders are the response headers kept in fixtures; the others may
+// identify the instance or the session
+var fixtureHeaders = []string{"Content-Type", "ETag", "Last-Modified"}
+
+// secretFields are JSON fields whose values are replaced in fixtures
+var secretFields = map[string]bool{
+	"access_token":  true,
+	"refresh_token": true,
+	"client_secret": true,
+	"token":         true,
+	"password":      true,
+	"secret":        true,
+}
+
+// userFields are JSON fields holding users, including those outside Jira's
+// user format such as the authors of pull requests
+var userFields = map[string]bool{
+	"assignee":     true,
+	"reporter":     true,
+	"creator":      true,
+	"author":       true,
+	"updateAuthor": true,
+	"user":         true,
+	"reviewers":    true,
+}
+
+// redactedQueryParams are the request query parameters whose values are
+// replaced by a hash in fixtures. JQL names projects, labels and whatever text
+// was searched for; replayKey hashes them the same way, so a replayed request
+// still matches its recording.
+var redactedQueryParams = []string{"jql"}
+
+// redactedValuePrefix marks a query parameter value that is already a hash
+const redactedValuePrefix = "redacted-"
+
+// atlassianSiteURL matches the Jira Cloud site URLs in response bodies, which
+// differ from the base URL of clients using OAuth 2.0
+var atlassianSiteURL = regexp.MustCompile(`https://[a-zA-Z0-9-]+\.atlassian\.net`)
+
+// interaction is a recorded request and its response, stored as one line of
+// a fixture file. Bodies that are JSON are stored as JSON, so fixtures can be
+// read and diffed; other bodies are stored as text.
+type interaction struct {
+	Method string            `json:"method"`
+	URL    string            `json:"url"` // path and query, relative to the base URL
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+	Text   string            `json:"text,omitempty"`
+}
+
+// Recorder is an HTTP transport that records the Jira requests of a client
+// and their responses into a fixture file, which a Replayer serves again
+// without Jira. Fixtures are redacted as they are written:
+//
+//   - request headers, and so credentials, are not recorded
+//   - the base URL and Jira Cloud site URLs become FixtureBaseURL
+//   - users are replaced by consistent pseudonyms ("User 1", user1@example.com)
+//   - token, secret and password fields are replaced
+//   - JQL queries in request URLs are replaced by a hash of the query
+//   - only the Content-Type, ETag and Last-Modified response headers are kept
+//
+// Summaries, descriptions and comments are recorded as they are, so review
+// a fixture before sharing it. Close the recorder when done to flush the
+// fixture to disk.
+type Recorder struct {
+	next    http.RoundTripper
+	baseURL string
+
+	mu    sync.Mutex
+	w     io.Writer
+	users map[string]int // pseudonym number by user identity
+}
+
+// NewRecorder creates a recorder writing interactions to w, one JSON line
+// each, as they happen
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w, users: make(map[string]int)}
+}
+
+// Close syncs the fixture to disk and closes it, if the recorder's writer is
+// a file or another io.Closer
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if file, ok := r.w.(interface{ Sync() error }); ok {
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync fixture: %w", err)
+		}
+	}
+	if closer, ok := r.w.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return fmt.Errorf("failed to close fixture: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetRecorder makes the client record its requests with r. It wraps the
+// client's transport, so call it after SetTransport. Responses served by the
+// cache set with SetCache aren't requested, and so aren't recorded.
+func (c *Client) SetRecorder(r *Recorder) {
+	r.next = c.httpClient.Transport
+	r.baseURL = c.baseURL
+	c.httpClient.Transport = r
+}
+
+// RoundTrip performs a request and records it. Requests outside the base
+// URL, such as OAuth 2.0 token refreshes, are not recorded.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := r.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	path, ok := fixturePath(r.baseURL, req.URL)
+	if !ok {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := r.record(req.Method, path, resp, body); err != nil {
+		return nil, fmt.Errorf("failed to record response: %w", err)
+	}
+	return resp, nil
+}
+
+// record writes a redacted interaction
+func (r *Recorder) record(method, path string, resp *http.Response, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recordedURL, err := redactQuery(r.redactURLs(path))
+	if err != nil {
+		return err
+	}
+	recorded := interaction{
+		Method: method,
+		URL:    recordedURL,
+		Status: resp.StatusCode,
+		Header: make(map[string]string),
+	}
+	for _, name := range fixtureHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			recorded.Header[name] = value
+		}
+	}
+
+	if json.Valid(body) && len(bytes.TrimSpace(body)) > 0 {
+		redacted, err := r.redactJSON(body)
+		if err != nil {
+			return err
+		}
+		recorded.Body = redacted
+	} else {
+		recorded.Text = r.redactURLs(string(body))
+	}
+
+	line, err := marshalFixtureJSON(recorded)
+	if err != nil {
+		return err
+	}
+	_, err = r.w.Write(append(line, '\n'))
+	return err
+}
+
+// redactURLs replaces the base URL and Jira Cloud site URLs in s
+func (r *Recorder) redactURLs(s string) string {
+	if r.baseURL != "" {
+		s = strings.ReplaceAll(s, r.baseURL, FixtureBaseURL)
+	}
+	return atlassianSiteURL.ReplaceAllString(s, FixtureBaseURL)
+}
+
+// redactQuery replaces the values of redactedQueryParams in a fixture URL
+func redactQuery(path string) (string, error) {
+	parsed, err := url.Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid request URL %s: %w", path, err)
+	}
+	query := parsed.Query()
+	if !redactQueryValues(query) {
+		return path, nil
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// redactQueryValues replaces the values of redactedQueryParams in query by
+// their hash, and reports whether there were any
+func redactQueryValues(query url.Values) bool {
+	redacted := false
+	for _, name := range redactedQueryParams {
+		for i, value := range query[name] {
+			if !strings.HasPrefix(value, redactedValuePrefix) {
+				sum := sha256.Sum256([]byte(value))
+				query[name][i] = redactedValuePrefix + hex.EncodeToString(sum[:12])
+			}
+			redacted = true
+		}
+	}
+	return redacted
+}
+
+// redactJSON redacts a JSON response body
+func (r *Recorder) redactJSON(body []byte) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return marshalFixtureJSON(r.redactValue(value, false))
+}
+
+// marshalFixtureJSON encodes v without escaping HTML characters, which keeps
+// the URLs in fixtures readable
+func marshalFixtureJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// redactValue redacts a decoded JSON value in place and returns it. user is
+// set for the values of userFields.
+func (r *Recorder) redactValue(value interface{}, user bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if user || isUser(v) {
+			r.pseudonymize(v)
+		}
+		for field, item := range v {
+			if secretFields[field] {
+				v[field] = "REDACTED"
+				continue
+			}
+			v[field] = r.redactValue(item, userFields[field])
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.redactValue(item, user)
+		}
+		return v
+	case string:
+		return r.redactURLs(v)
+	}
+	return value
+}
+
+// isUser reports whether a JSON object is a Jira user: Cloud users have an
+// accountId, Server users a name and key along with their display name
+func isUser(v map[string]interface{}) bool {
+	if _, ok := v["accountId"]; ok {
+		return true
+	}
+	if _, ok := v["emailAddress"]; ok {
+		return true
+	}
+	_, hasName := v["name"]
+	_, hasKey := v["key"]
+	_, hasDisplayName := v["displayName"]
+	return hasName && hasKey && hasDisplayName
+}
+
+// pseudonymize replaces the identifying fields of a user with a pseudonym,
+// the same one every time the user appears
+func (r *Recorder) pseudonymize(user map[string]interface{}) {
+	identity := ""
+	for _, field := range []string{"accountId", "key", "name", "emailAddress", "displayName"} {
+		if value, ok := user[field].(string); ok && value != "" {
+			identity = field + ":" + value
+			break
+		}
+	}
+	n, ok := r.users[identity]
+	if !ok {
+		n = len(r.users) + 1
+		r.users[identity] = n
+	}
+
+	pseudonyms := map[string]string{
+		"accountId":    fmt.Sprintf("account-%d", n),
+		"key":          fmt.Sprintf("user%d", n),
+		"name":         fmt.Sprintf("user%d", n),
+		"emailAddress": fmt.Sprintf("user%d@example.com", n),
+		"displayName":  fmt.Sprintf("User %d", n),
+	}
+	for field, pseudonym := range pseudonyms {
+		if _, ok := user[field]; ok {
+			user[field] = pseudonym
+		}
+	}
+	// The links of a user identify them too
+	if _, ok := user["self"]; ok {
+		user["self"] = fmt.Sprintf("%s/rest/api/2/user?username=user%d", FixtureBaseURL, n)
+	}
+	delete(user, "avatarUrls")
+}
+
+// Replayer is an HTTP transport serving the responses of a fixture file
+// written by a Recorder. Requests are matched by method, path and query,
+// whatever the order of the query parameters. A request made several times
+// gets its recorded responses in order, then the last one again; a request
+// that wasn't recorded fails.
+type Replayer struct {
+	baseURL string
+
+	mu        sync.Mutex
+	responses map[string][]interaction // by request
+}
+
+// LoadReplayer reads a fixture file for replaying
+func LoadReplayer(filename string) (*Replayer, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	replayer, err := NewReplayer(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture %s: %w", filename, err)
+	}
+	return replayer, nil
+}
+
+// NewReplayer reads the interactions of a fixture from r
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	replayer := &Replayer{responses: make(map[string][]interaction)}
+
+	// Search pages can make lines of many megabytes, so the interactions are
+	// decoded as a stream rather than scanned line by line
+	decoder := json.NewDecoder(r)
+	for n := 1; ; n++ {
+		var recorded interaction
+		if err := decoder.Decode(&recorded); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("interaction %d: %w", n, err)
+		}
+		key, err := replayKey(recorded.Method, recorded.URL)
+		if err != nil {
+			return nil, fmt.Errorf("interaction %d: %w", n, err)
+		}
+		replayer.responses[key] = append(replayer.responses[key], recorded)
+	}
+
+	return replayer, nil
+}
+
+// SetReplayer makes the client get its responses from r instead of Jira
+func (c *Client) SetReplayer(r *Replayer) {
+	r.baseURL = c.baseURL
+	c.httpClient.Transport = r
+}
+
+// RoundTrip serves the recorded response of a request
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	path, ok := fixturePath(r.baseURL, req.URL)
+	if !ok {
+		return nil, fmt.Errorf("no recorded response for %s %s: outside the base URL", req.Method, req.URL)
+	}
+	key, err := replayKey(req.Method, path)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	recorded, ok := r.responses[key]
+	if ok && len(recorded) > 1 {
+		r.responses[key] = recorded[1:]
+	}
+	r.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, path)
+	}
+	response := recorded[0]
+
+	header := make(http.Header)
+	for name, value := range response.Header {
+		header.Set(name, value)
+	}
+	body := []byte(response.Text)
+	if response.Body != nil {
+		body = response.Body
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.Status, http.StatusText(response.Status)),
+		StatusCode:    response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// fixturePath returns the path and query of a request URL relative to the
+// base URL, or false if the URL is outside it
+func fixturePath(baseURL string, requestURL *url.URL) (string, bool) {
+	full := requestURL.String()
+	if !strings.HasPrefix(full, baseURL+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(full, baseURL), true
+}
+
+// replayKey identifies a request by method, path and query, with the query
+// parameters in a canonical order. Redacted parameters are compared by hash,
+// so recorded fixtures match the request and hand-written ones can keep
+// their queries readable.
+func replayKey(method, path string) (string, error) {
+	parsed, err := url.Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid fixture URL %s: %w", path, err)
+	}
+	query := parsed.Query()
+	redactQueryValues(query)
+	return method + " " + parsed.Path + "?" + query.Encode(), nil
+}
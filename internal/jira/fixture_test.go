@@ -0,0 +1,214 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorderRedactsAndReplays(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/PROJ-1" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("<html>Not found</html>"))
+			return
+		}
+
+		alice := map[string]interface{}{
+			"self":         server.URL + "/rest/api/2/user?accountId=5b10a2844c20165700ede21g",
+			"accountId":    "5b10a2844c20165700ede21g",
+			"emailAddress": "alice@acme.com",
+			"displayName":  "Alice Smith",
+			"avatarUrls":   map[string]interface{}{"48x48": "https://avatar.example.com/alice.png"},
+		}
+		issue := createMinimalIssue("PROJ-1", "Fix login")
+		issue["self"] = "https://acme.atlassian.net/rest/api/2/issue/10001"
+		fields := issue["fields"].(map[string]interface{})
+		fields["assignee"] = alice
+		fields["reporter"] = alice
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Set-Cookie", "atlassian.xsrf.token=secret-cookie")
+		if err := json.NewEncoder(w).Encode(issue); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	var fixture bytes.Buffer
+	client := NewClient(server.URL, "alice@acme.com", "token123", "basic")
+	client.SetRecorder(NewRecorder(&fixture))
+
+	recorded, err := client.FetchIssue("PROJ-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if recorded.Fields.Assignee == nil || recorded.Fields.Assignee.DisplayName != "Alice Smith" {
+		t.Errorf("Expected the client to get the real response, got %v", recorded.Fields.Assignee)
+	}
+	if _, err := client.FetchIssue("PROJ-404"); err == nil {
+		t.Error("Expected an error for a missing issue")
+	}
+
+	for _, secret := range []string{"token123", "alice", "Alice", "5b10a2844c20165700ede21g", "acme", "secret-cookie", server.URL, "avatar"} {
+		if strings.Contains(fixture.String(), secret) {
+			t.Errorf("Expected %q redacted from the fixture:\n%s", secret, fixture.String())
+		}
+	}
+	if lines := strings.Count(fixture.String(), "\n"); lines != 2 {
+		t.Errorf("Expected 2 recorded interactions, got %d", lines)
+	}
+
+	replayer, err := NewReplayer(&fixture)
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	offline := NewClient(FixtureBaseURL, "", "", "basic")
+	offline.SetReplayer(replayer)
+
+	replayed, err := offline.FetchIssue("PROJ-1")
+	if err != nil {
+		t.Fatalf("Expected no error replaying, got: %v", err)
+	}
+	if replayed.Fields.Summary != "Fix login" {
+		t.Errorf("Expected summary 'Fix login', got %q", replayed.Fields.Summary)
+	}
+	assignee, reporter := replayed.Fields.Assignee, replayed.Fields.Reporter
+	if assignee == nil || assignee.DisplayName != "User 1" || assignee.EmailAddress != "user1@example.com" || assignee.AccountId != "account-1" {
+		t.Errorf("Expected the assignee pseudonymized as User 1, got %v", assignee)
+	}
+	if reporter == nil || reporter.AccountId != assignee.AccountId {
+		t.Errorf("Expected the same user to get the same pseudonym, got %v and %v", assignee, reporter)
+	}
+
+	if _, err := offline.FetchIssue("PROJ-404"); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("Expected the recorded 404 replayed, got %v", err)
+	}
+	if _, err := offline.FetchIssue("PROJ-2"); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("Expected an unrecorded request to fail, got %v", err)
+	}
+}
+
+func TestRecorderRedactsJQL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"issues": [], "isLast": true}`))
+	}))
+	defer server.Close()
+
+	fixtureFile := filepath.Join(t.TempDir(), "fixture.jsonl")
+	file, err := os.Create(fixtureFile)
+	if err != nil {
+		t.Fatalf("Failed to create fixture: %v", err)
+	}
+	recorder := NewRecorder(file)
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	client.SetRecorder(recorder)
+
+	searchURL := "/rest/api/3/search/jql?jql=" + url.QueryEscape(`labels = "acme-merger"`) + "&maxResults=50"
+	if _, err := client.get(server.URL + searchURL); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Failed to close recorder: %v", err)
+	}
+	if _, err := file.WriteString("more"); err == nil {
+		t.Error("Expected Close to close the fixture file")
+	}
+
+	data, err := os.ReadFile(fixtureFile)
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	if strings.Contains(string(data), "acme") || !strings.Contains(string(data), "jql=redacted-") {
+		t.Errorf("Expected the JQL query redacted from the fixture:\n%s", data)
+	}
+
+	// The request still matches the redacted recording when replayed
+	replayer, err := LoadReplayer(fixtureFile)
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	offline := NewClient(FixtureBaseURL, "", "", "basic")
+	offline.SetReplayer(replayer)
+	if _, err := offline.get(FixtureBaseURL + searchURL); err != nil {
+		t.Errorf("Expected the redacted search replayed, got: %v", err)
+	}
+}
+
+func TestReplayerMatching(t *testing.T) {
+	fixture := strings.Join([]string{
+		`{"method": "GET", "url": "/rest/api/2/search?jql=project+%3D+PROJ&startAt=0", "status": 200, "body": {"page": 1}}`,
+		`{"method": "GET", "url": "/rest/api/2/search?jql=project+%3D+PROJ&startAt=0", "status": 200, "body": {"page": 2}}`,
+		`{"method": "GET", "url": "/rest/api/2/serverInfo", "status": 500, "text": "Internal error"}`,
+	}, "\n")
+
+	replayer, err := NewReplayer(strings.NewReader(fixture))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	client := NewClient(FixtureBaseURL+"/", "", "", "basic")
+	client.SetReplayer(replayer)
+
+	// Query parameters match in any order, and repeated requests get the
+	// recorded responses in order, then the last one again
+	for _, want := range []string{`{"page": 1}`, `{"page": 2}`, `{"page": 2}`} {
+		body, err := client.get(FixtureBaseURL + "/rest/api/2/search?startAt=0&jql=project+%3D+PROJ")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if string(body) != want {
+			t.Errorf("Expected %s, got %s", want, body)
+		}
+	}
+
+	if _, err := client.get(FixtureBaseURL + "/rest/api/2/serverInfo"); err == nil || !strings.Contains(err.Error(), "Internal error") {
+		t.Errorf("Expected the recorded error replayed, got %v", err)
+	}
+
+	if _, err := NewReplayer(strings.NewReader(`{"method": "GET"`)); err == nil {
+		t.Error("Expected an error for a truncated fixture")
+	}
+}
+
+func TestReplayRecordedFixture(t *testing.T) {
+	replayer, err := LoadReplayer("../../testdata/fixtures/fetch-issue-with-dependencies.jsonl")
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	client := NewClient(FixtureBaseURL, "user1@example.com", "token", "basic")
+	client.SetReplayer(replayer)
+	client.SetFetchDevelopment(true)
+
+	export, err := client.FetchIssueWithDependencies("PROJ-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	issues := make(map[string]bool)
+	for _, issue := range export.Issues {
+		issues[issue.Key] = true
+	}
+	for _, key := range []string{"PROJ-1", "PROJ-2", "PROJ-3"} {
+		if !issues[key] {
+			t.Errorf("Expected %s in the export, got %v", key, issues)
+		}
+	}
+
+	story := export.Issues[0]
+	if story.Fields.Assignee == nil || story.Fields.Assignee.DisplayName != "User 1" {
+		t.Errorf("Expected the recorded assignee, got %v", story.Fields.Assignee)
+	}
+	if len(story.PullRequests) != 1 || story.PullRequests[0].Url != "https://github.com/acme/api/pull/12" {
+		t.Errorf("Expected the recorded pull request, got %v", story.PullRequests)
+	}
+}
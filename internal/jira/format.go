@@ -0,0 +1,168 @@
+package jira
+
+import (
+	"bytes"
+	"strings"
+
+	pb "github.com/conallob/jira-beads-sync/gen/jira"
+	"google.golang.org/protobuf/proto"
+)
+
+// Format is the file format of a Jira export
+type Format string
+
+const (
+	FormatJSON Format = "json" // REST API search results, {"issues": [...]}
+	FormatCSV  Format = "csv"  // "Export CSV" from the issue navigator
+	FormatXML  Format = "xml"  // "Export XML" (RSS) from the issue navigator
+)
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// DetectFormat guesses the format of a Jira export from its contents
+func DetectFormat(data []byte) Format {
+	data = bytes.TrimLeft(bytes.TrimPrefix(data, utf8BOM), " \t\r\n")
+	if len(data) == 0 {
+		return FormatJSON
+	}
+	switch data[0] {
+	case '{', '[':
+		return FormatJSON
+	case '<':
+		return FormatXML
+	}
+	return FormatCSV
+}
+
+// ParseAny parses a Jira export in any supported format into protobuf
+func (a *Adapter) ParseAny(data []byte) (*pb.Export, error) {
+	switch DetectFormat(data) {
+	case FormatCSV:
+		return a.ParseCSV(data)
+	case FormatXML:
+		return a.ParseXML(data)
+	}
+	return a.Parse(data)
+}
+
+// resolveReferences completes the references between issues of an export
+// that only names them, as CSV and XML exports do: parent IDs become keys,
+// and parents, linked issues and subtasks present in the export get their
+// summary, status and type. subtaskParents marks the issues whose parent
+// reference is known to be a subtask relation.
+func resolveReferences(export *pb.Export, subtaskParents []bool) {
+	byKey := make(map[string]*pb.Issue, len(export.Issues))
+	byID := make(map[string]*pb.Issue, len(export.Issues))
+	for _, issue := range export.Issues {
+		byKey[issue.Key] = issue
+		if issue.Id != "" {
+			byID[issue.Id] = issue
+		}
+	}
+	lookup := func(ref string) *pb.Issue {
+		if issue, ok := byKey[ref]; ok {
+			return issue
+		}
+		return byID[ref]
+	}
+
+	// Resolve parents first, since they decide which issues are subtasks
+	for i, issue := range export.Issues {
+		parent := issue.Fields.Parent
+		if parent == nil {
+			continue
+		}
+		if target := lookup(parent.Key); target != nil {
+			parent.Id = target.Id
+			parent.Key = target.Key
+			if subtaskParents[i] && !target.Fields.IssueType.Subtask && target.Fields.IssueType.Name != "Epic" {
+				issue.Fields.IssueType.Subtask = true
+			}
+		}
+	}
+
+	for _, issue := range export.Issues {
+		if parent := issue.Fields.Parent; parent != nil {
+			if target := lookup(parent.Key); target != nil {
+				parent.Fields = linkedFields(target)
+				if issue.Fields.IssueType.Subtask && !hasSubtask(target, issue.Key) {
+					target.Fields.Subtasks = append(target.Fields.Subtasks, &pb.Subtask{Key: issue.Key})
+				}
+			}
+		}
+
+		for _, link := range issue.Fields.IssueLinks {
+			for _, linked := range []*pb.LinkedIssue{link.InwardIssue, link.OutwardIssue} {
+				if linked == nil {
+					continue
+				}
+				if target := lookup(linked.Key); target != nil {
+					linked.Id = target.Id
+					linked.Fields = linkedFields(target)
+				}
+			}
+		}
+	}
+
+	// Subtasks are only complete once every parent has collected its own
+	for _, issue := range export.Issues {
+		for _, subtask := range issue.Fields.Subtasks {
+			if target := lookup(subtask.Key); target != nil {
+				subtask.Id = target.Id
+				subtask.Fields = linkedFields(target)
+			}
+		}
+	}
+}
+
+// hasSubtask reports whether an issue already lists a subtask
+func hasSubtask(issue *pb.Issue, key string) bool {
+	for _, subtask := range issue.Fields.Subtasks {
+		if subtask.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// linkedFields returns the summary fields of an issue that other issues refer to
+func linkedFields(issue *pb.Issue) *pb.LinkedFields {
+	return &pb.LinkedFields{
+		Summary:   issue.Fields.Summary,
+		Status:    proto.Clone(issue.Fields.Status).(*pb.Status),
+		IssueType: proto.Clone(issue.Fields.IssueType).(*pb.IssueType),
+	}
+}
+
+// isSubtaskType reports whether an issue type name is Jira's subtask type
+func isSubtaskType(name string) bool {
+	switch strings.ToLower(name) {
+	case "sub-task", "subtask":
+		return true
+	}
+	return false
+}
+
+// statusCategory maps a status category name to a status category. Older
+// exports don't name it, so the category is then guessed from the status.
+func statusCategory(category, status string) *pb.StatusCategory {
+	if category == "" {
+		switch strings.ToLower(status) {
+		case "done", "closed", "resolved", "complete", "completed", "cancelled", "won't do":
+			category = "Done"
+		case "in progress", "in review", "in development", "review", "testing":
+			category = "In Progress"
+		default:
+			category = "To Do"
+		}
+	}
+
+	switch strings.ToLower(category) {
+	case "done", "complete":
+		return &pb.StatusCategory{Key: "done", Name: category}
+	case "in progress":
+		return &pb.StatusCategory{Key: "indeterminate", Name: category}
+	default:
+		return &pb.StatusCategory{Key: "new", Name: category}
+	}
+}
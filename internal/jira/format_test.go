@@ -0,0 +1,57 @@
+package jira
+
+import "testing"
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Format
+	}{
+		{"JSON object", `{"issues": []}`, FormatJSON},
+		{"JSON with leading whitespace", "\n  {\"issues\": []}", FormatJSON},
+		{"JSON array", `[{"key": "PROJ-1"}]`, FormatJSON},
+		{"CSV", "Summary,Issue key\nTest,PROJ-1\n", FormatCSV},
+		{"CSV with byte order mark", "\xef\xbb\xbfSummary,Issue key\n", FormatCSV},
+		{"XML", "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<rss version=\"0.92\">", FormatXML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat([]byte(tt.data)); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAdapterParseAny(t *testing.T) {
+	for _, file := range []string{
+		"../../testdata/sample-jira-export.json",
+		"../../testdata/sample-jira-export.csv",
+		"../../testdata/sample-jira-export.xml",
+	} {
+		t.Run(file, func(t *testing.T) {
+			export, err := NewAdapter().ParseFile(file)
+			if err != nil {
+				t.Fatalf("Failed to parse file: %v", err)
+			}
+
+			// Every format holds the same issues and relationships
+			if len(export.Issues) != 4 {
+				t.Fatalf("Expected 4 issues, got %d", len(export.Issues))
+			}
+			epic, login := export.Issues[0], export.Issues[1]
+			if epic.Fields.IssueType.Name != "Epic" {
+				t.Errorf("Expected PROJ-1 to be an epic, got %s", epic.Fields.IssueType.Name)
+			}
+			if login.Fields.Parent == nil || login.Fields.Parent.Key != "PROJ-1" || login.Fields.Parent.Fields.IssueType.Name != "Epic" {
+				t.Errorf("Expected PROJ-2 to have epic parent PROJ-1, got %v", login.Fields.Parent)
+			}
+			if len(login.Fields.IssueLinks) != 1 || login.Fields.IssueLinks[0].Type.Inward != "is blocked by" ||
+				login.Fields.IssueLinks[0].InwardIssue.Key != "PROJ-4" {
+				t.Errorf("Expected PROJ-2 to be blocked by PROJ-4, got %v", login.Fields.IssueLinks)
+			}
+		})
+	}
+}
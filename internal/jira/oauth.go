@@ -0,0 +1,362 @@
+package jira
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Atlassian Cloud OAuth 2.0 (3LO) endpoints
+const (
+	DefaultOAuth2AuthURL      = "https://auth.atlassian.com/authorize"
+	DefaultOAuth2TokenURL     = "https://auth.atlassian.com/oauth/token"
+	DefaultOAuth2ResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+
+	// oauth2APIURL is the API base URL of a Cloud site accessed with OAuth 2.0
+	oauth2APIURL = "https://api.atlassian.com/ex/jira/%s"
+
+	// oauth2ExpiryMargin refreshes tokens slightly before they expire, so a
+	// token doesn't expire between setting the header and Jira checking it
+	oauth2ExpiryMargin = time.Minute
+)
+
+// DefaultOAuth2Scopes are the scopes requested when none are configured.
+// offline_access is needed to receive a refresh token.
+var DefaultOAuth2Scopes = []string{"read:jira-work", "read:jira-user", "offline_access"}
+
+// OAuth2Config identifies an OAuth 2.0 app and its authorization server
+type OAuth2Config struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string   // DefaultOAuth2AuthURL if empty
+	TokenURL     string   // DefaultOAuth2TokenURL if empty
+	ResourcesURL string   // DefaultOAuth2ResourcesURL if empty
+	Scopes       []string // DefaultOAuth2Scopes if empty
+	RedirectURL  string   // callback registered with the app, e.g. http://localhost:8085/callback
+}
+
+// withDefaults returns the config with empty endpoints and scopes defaulted
+func (o OAuth2Config) withDefaults() OAuth2Config {
+	if o.AuthURL == "" {
+		o.AuthURL = DefaultOAuth2AuthURL
+	}
+	if o.TokenURL == "" {
+		o.TokenURL = DefaultOAuth2TokenURL
+	}
+	if o.ResourcesURL == "" {
+		o.ResourcesURL = DefaultOAuth2ResourcesURL
+	}
+	if len(o.Scopes) == 0 {
+		o.Scopes = DefaultOAuth2Scopes
+	}
+	return o
+}
+
+// OAuth2Token is an access token and the refresh token used to renew it
+type OAuth2Token struct {
+	AccessToken  string
+	RefreshToken string
+	Expiry       time.Time // zero if the token doesn't expire
+}
+
+// expired reports whether the token has expired or is about to
+func (t *OAuth2Token) expired() bool {
+	return !t.Expiry.IsZero() && time.Now().Add(oauth2ExpiryMargin).After(t.Expiry)
+}
+
+// oauth2Session holds the OAuth 2.0 state of a client
+type oauth2Session struct {
+	mu        sync.Mutex
+	config    OAuth2Config
+	token     OAuth2Token
+	onRefresh func(*OAuth2Token)
+}
+
+// OAuth2APIURL returns the API base URL of the Cloud site with the given ID.
+// Requests authorized with OAuth 2.0 go through api.atlassian.com rather than
+// the site URL.
+func OAuth2APIURL(cloudID string) string {
+	return fmt.Sprintf(oauth2APIURL, cloudID)
+}
+
+// SetOAuth2 makes the client authenticate with OAuth 2.0 access tokens. An
+// expired access token is refreshed before the next request, and onRefresh
+// (if not nil) is called with the new token so it can be stored.
+func (c *Client) SetOAuth2(config OAuth2Config, token OAuth2Token, onRefresh func(*OAuth2Token)) {
+	c.authMethod = "oauth2"
+	c.oauth2 = &oauth2Session{
+		config:    config.withDefaults(),
+		token:     token,
+		onRefresh: onRefresh,
+	}
+}
+
+// oauth2AccessToken returns a valid access token, refreshing it if needed
+func (c *Client) oauth2AccessToken() (string, error) {
+	if c.oauth2 == nil {
+		return "", fmt.Errorf("OAuth 2.0 is not configured. Run 'jira-beads-sync configure'")
+	}
+
+	session := c.oauth2
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.token.AccessToken != "" && !session.token.expired() {
+		return session.token.AccessToken, nil
+	}
+	if session.token.RefreshToken == "" {
+		return "", fmt.Errorf("OAuth 2.0 access token expired and no refresh token is available. Run 'jira-beads-sync configure'")
+	}
+
+	token, err := requestToken(c.httpClient, session.config, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {session.token.RefreshToken},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh OAuth 2.0 token: %w", err)
+	}
+	// Refresh tokens may rotate; keep the old one if no new one is issued
+	if token.RefreshToken == "" {
+		token.RefreshToken = session.token.RefreshToken
+	}
+
+	session.token = *token
+	if session.onRefresh != nil {
+		session.onRefresh(token)
+	}
+
+	return token.AccessToken, nil
+}
+
+// requestToken posts a token request to the authorization server
+func requestToken(httpClient *http.Client, config OAuth2Config, params url.Values) (token *OAuth2Token, err error) {
+	params.Set("client_id", config.ClientID)
+	if config.ClientSecret != "" {
+		params.Set("client_secret", config.ClientSecret)
+	}
+
+	req, err := http.NewRequest("POST", config.TokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("authorization server returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResponse struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return nil, fmt.Errorf("authorization server returned no access token")
+	}
+
+	token = &OAuth2Token{
+		AccessToken:  tokenResponse.AccessToken,
+		RefreshToken: tokenResponse.RefreshToken,
+	}
+	if tokenResponse.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+// AuthorizeOAuth2 runs the authorization-code flow. It listens on the
+// config's localhost redirect URL, calls open with the URL the user must
+// visit to grant access, and exchanges the code delivered to the callback
+// for tokens. It gives up after timeout.
+func AuthorizeOAuth2(config OAuth2Config, open func(authURL string) error, timeout time.Duration) (*OAuth2Token, error) {
+	config = config.withDefaults()
+
+	redirect, err := url.Parse(config.RedirectURL)
+	if err != nil || redirect.Host == "" {
+		return nil, fmt.Errorf("invalid OAuth 2.0 redirect URL: %s", config.RedirectURL)
+	}
+	if host := redirect.Hostname(); host != "localhost" && host != "127.0.0.1" {
+		return nil, fmt.Errorf("OAuth 2.0 redirect URL must be on localhost, got: %s", config.RedirectURL)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:"+redirect.Port())
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for OAuth 2.0 callback: %w", err)
+	}
+	// With port 0 the system picks a free port, which the redirect URL must name
+	if redirect.Port() == "0" {
+		redirect.Host = fmt.Sprintf("%s:%d", redirect.Hostname(), listener.Addr().(*net.TCPAddr).Port)
+		config.RedirectURL = redirect.String()
+	}
+
+	state, err := randomState()
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	results := make(chan callbackResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var result callbackResult
+		switch {
+		case query.Get("state") != state:
+			result.err = fmt.Errorf("OAuth 2.0 callback state mismatch")
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("authorization denied: %s %s", query.Get("error"), query.Get("error_description"))
+		case query.Get("code") == "":
+			result.err = fmt.Errorf("OAuth 2.0 callback has no authorization code")
+		default:
+			result.code = query.Get("code")
+		}
+
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			_, _ = fmt.Fprintln(w, "jira-beads-sync is authorized. You can close this window.")
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+
+	authURL := authorizationURL(config, state)
+	if err := open(authURL); err != nil {
+		return nil, fmt.Errorf("failed to open authorization URL: %w", err)
+	}
+
+	var result callbackResult
+	select {
+	case result = <-results:
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("timed out waiting for OAuth 2.0 authorization")
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	token, err := requestToken(&http.Client{Timeout: 30 * time.Second}, config, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {result.code},
+		"redirect_uri": {config.RedirectURL},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	return token, nil
+}
+
+// authorizationURL returns the URL the user visits to grant access
+func authorizationURL(config OAuth2Config, state string) string {
+	params := url.Values{}
+	params.Set("client_id", config.ClientID)
+	params.Set("redirect_uri", config.RedirectURL)
+	params.Set("response_type", "code")
+	params.Set("scope", strings.Join(config.Scopes, " "))
+	params.Set("state", state)
+	if config.AuthURL == DefaultOAuth2AuthURL {
+		// Atlassian Cloud requires the API audience and an explicit consent prompt
+		params.Set("audience", "api.atlassian.com")
+		params.Set("prompt", "consent")
+	}
+
+	separator := "?"
+	if strings.Contains(config.AuthURL, "?") {
+		separator = "&"
+	}
+	return config.AuthURL + separator + params.Encode()
+}
+
+// randomState returns an unguessable state value for the authorization request
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate OAuth 2.0 state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// FindCloudID returns the cloud ID of the Jira Cloud site at siteURL among the
+// sites the token grants access to
+func FindCloudID(config OAuth2Config, token *OAuth2Token, siteURL string) (cloudID string, err error) {
+	config = config.withDefaults()
+
+	req, err := http.NewRequest("GET", config.ResourcesURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to list accessible sites: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to list accessible sites: status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var resources []struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return "", fmt.Errorf("failed to parse accessible sites: %w", err)
+	}
+
+	siteURL = strings.TrimSuffix(siteURL, "/")
+	for _, resource := range resources {
+		if strings.EqualFold(strings.TrimSuffix(resource.URL, "/"), siteURL) {
+			return resource.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("the OAuth 2.0 app has no access to %s", siteURL)
+}
@@ -0,0 +1,225 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAuthServer is a local stand-in for an OAuth 2.0 authorization server.
+// /authorize redirects straight back to the callback with a code, as if the
+// user had granted access; /token exchanges codes and refresh tokens.
+type fakeAuthServer struct {
+	t         *testing.T
+	grants    []string // grant_type of every token request
+	refreshed int      // number of refresh_token grants
+}
+
+func (f *fakeAuthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/authorize":
+		query := r.URL.Query()
+		if query.Get("client_id") != "client-1" {
+			f.t.Errorf("Expected client_id 'client-1', got '%s'", query.Get("client_id"))
+		}
+		if query.Get("response_type") != "code" {
+			f.t.Errorf("Expected response_type 'code', got '%s'", query.Get("response_type"))
+		}
+		redirect := query.Get("redirect_uri") + "?code=auth-code&state=" + url.QueryEscape(query.Get("state"))
+		http.Redirect(w, r, redirect, http.StatusFound)
+	case "/token":
+		if err := r.ParseForm(); err != nil {
+			f.t.Fatalf("Failed to parse token request: %v", err)
+		}
+		if r.PostForm.Get("client_id") != "client-1" || r.PostForm.Get("client_secret") != "secret-1" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+
+		grant := r.PostForm.Get("grant_type")
+		f.grants = append(f.grants, grant)
+
+		var response map[string]interface{}
+		switch {
+		case grant == "authorization_code" && r.PostForm.Get("code") == "auth-code":
+			response = map[string]interface{}{"access_token": "access-1", "refresh_token": "refresh-1", "expires_in": 3600}
+		case grant == "refresh_token" && r.PostForm.Get("refresh_token") == "refresh-1":
+			f.refreshed++
+			response = map[string]interface{}{"access_token": "access-2", "refresh_token": "refresh-2", "expires_in": 3600}
+		default:
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			f.t.Errorf("Failed to encode response: %v", err)
+		}
+	case "/accessible-resources":
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		resources := []map[string]interface{}{
+			{"id": "cloud-other", "url": "https://other.atlassian.net"},
+			{"id": "cloud-1", "url": "https://example.atlassian.net"},
+		}
+		if err := json.NewEncoder(w).Encode(resources); err != nil {
+			f.t.Errorf("Failed to encode response: %v", err)
+		}
+	default:
+		f.t.Errorf("Unexpected request: %s", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakeAuthServer(t *testing.T) (*fakeAuthServer, *httptest.Server, OAuth2Config) {
+	fake := &fakeAuthServer{t: t}
+	server := httptest.NewServer(fake)
+	config := OAuth2Config{
+		ClientID:     "client-1",
+		ClientSecret: "secret-1",
+		AuthURL:      server.URL + "/authorize",
+		TokenURL:     server.URL + "/token",
+		ResourcesURL: server.URL + "/accessible-resources",
+		RedirectURL:  "http://localhost:0/callback",
+	}
+	return fake, server, config
+}
+
+func TestAuthorizeOAuth2(t *testing.T) {
+	fake, server, config := newFakeAuthServer(t)
+	defer server.Close()
+
+	// Stand in for the browser: follow the authorization URL to the callback
+	browse := func(authURL string) error {
+		resp, err := http.Get(authURL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	token, err := AuthorizeOAuth2(config, browse, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" {
+		t.Errorf("Expected tokens access-1/refresh-1, got %s/%s", token.AccessToken, token.RefreshToken)
+	}
+	if token.Expiry.Before(time.Now().Add(59 * time.Minute)) {
+		t.Errorf("Expected expiry in about an hour, got %v", token.Expiry)
+	}
+	if strings.Join(fake.grants, ",") != "authorization_code" {
+		t.Errorf("Expected one authorization_code grant, got %v", fake.grants)
+	}
+
+	cloudID, err := FindCloudID(config, token, "https://example.atlassian.net/")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cloudID != "cloud-1" {
+		t.Errorf("Expected cloud ID 'cloud-1', got '%s'", cloudID)
+	}
+}
+
+func TestAuthorizeOAuth2StateMismatch(t *testing.T) {
+	_, server, config := newFakeAuthServer(t)
+	defer server.Close()
+
+	// Deliver a code with a forged state to the callback
+	browse := func(authURL string) error {
+		parsed, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		resp, err := http.Get(parsed.Query().Get("redirect_uri") + "?code=auth-code&state=forged")
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	_, err := AuthorizeOAuth2(config, browse, 5*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "state mismatch") {
+		t.Errorf("Expected state mismatch error, got: %v", err)
+	}
+}
+
+func TestAuthorizeOAuth2RejectsRemoteRedirect(t *testing.T) {
+	config := OAuth2Config{ClientID: "client-1", RedirectURL: "https://example.com/callback"}
+
+	_, err := AuthorizeOAuth2(config, func(string) error { return nil }, time.Second)
+	if err == nil || !strings.Contains(err.Error(), "must be on localhost") {
+		t.Errorf("Expected localhost error, got: %v", err)
+	}
+}
+
+func TestOAuth2TokenRefresh(t *testing.T) {
+	fake, authServer, config := newFakeAuthServer(t)
+	defer authServer.Close()
+
+	var authHeaders []string
+	jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"accountId":"abc","displayName":"Test User","active":true}`))
+	}))
+	defer jiraServer.Close()
+
+	var stored *OAuth2Token
+	client := NewClient(jiraServer.URL, "", "", "")
+	client.SetOAuth2(config, OAuth2Token{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		Expiry:       time.Now().Add(-time.Minute),
+	}, func(token *OAuth2Token) {
+		stored = token
+	})
+
+	// The expired token is refreshed once, then reused
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetCurrentUser(); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	if fake.refreshed != 1 {
+		t.Errorf("Expected 1 refresh, got %d", fake.refreshed)
+	}
+	for _, header := range authHeaders {
+		if header != "Bearer access-2" {
+			t.Errorf("Expected refreshed token in Authorization header, got '%s'", header)
+		}
+	}
+	if stored == nil || stored.AccessToken != "access-2" || stored.RefreshToken != "refresh-2" {
+		t.Errorf("Expected refreshed tokens to be stored, got %+v", stored)
+	}
+}
+
+func TestOAuth2RefreshFailure(t *testing.T) {
+	_, authServer, config := newFakeAuthServer(t)
+	defer authServer.Close()
+
+	jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no Jira request without a valid token")
+	}))
+	defer jiraServer.Close()
+
+	client := NewClient(jiraServer.URL, "", "", "")
+	client.SetOAuth2(config, OAuth2Token{
+		AccessToken:  "access-1",
+		RefreshToken: "revoked",
+		Expiry:       time.Now().Add(-time.Minute),
+	}, nil)
+
+	_, err := client.FetchIssue("PROJ-1")
+	if err == nil || !strings.Contains(err.Error(), "failed to refresh OAuth 2.0 token") {
+		t.Errorf("Expected refresh error, got: %v", err)
+	}
+}
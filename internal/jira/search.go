@@ -0,0 +1,262 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	pb "github.com/conallob/jira-beads-sync/gen/jira"
+)
+
+const (
+	// searchPageSize is the number of issues requested per search page
+	searchPageSize = 100
+	// keyBatchSize is the number of keys per "key in (...)" query, which
+	// keeps the JQL well below URL length limits
+	keyBatchSize = 50
+)
+
+// maxSearchPages caps the number of pages a single search or Agile listing may
+// request, so a misbehaving endpoint can't keep a sync paging forever
+var maxSearchPages = 1000
+
+// defaultSearchFields are the issue fields requested from search, covering
+// everything the adapter converts
+var defaultSearchFields = []string{
+	"summary", "description", "issuetype", "status", "priority",
+	"assignee", "reporter", "created", "updated", "labels",
+	"issuelinks", "parent", "subtasks", "fixVersions", "versions",
+	"components", "duedate", "resolution", "resolutiondate",
+}
+
+// SetSearchFields sets the issue fields requested when fetching issues through
+// search. The rank field is always requested when set.
+func (c *Client) SetSearchFields(fields []string) {
+	c.searchFields = fields
+}
+
+// fieldList returns the comma-separated list of fields to request from search
+func (c *Client) fieldList() string {
+	fields := c.searchFields
+	if len(fields) == 0 {
+		fields = defaultSearchFields
+	}
+
+	if rankField := c.adapter.RankField(); rankField != "" {
+		hasRank := false
+		for _, field := range fields {
+			if field == rankField {
+				hasRank = true
+				break
+			}
+		}
+		if !hasRank {
+			fields = append(append([]string{}, fields...), rankField)
+		}
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// searchPages runs a JQL search requesting the given fields and calls handle
+// with the issues of every result page
+func (c *Client) searchPages(jql, fields string, handle func([]json.RawMessage) error) error {
+	if c.Deployment() == DeploymentServer {
+		return c.searchPagesServer(jql, fields, handle)
+	}
+	return c.searchPagesCloud(jql, fields, handle)
+}
+
+// searchPagesCloud pages through /rest/api/3/search/jql, which is
+// token-based: each page returns the token of the next one. The endpoint
+// doesn't report a reliable total, so paging ends on isLast or a missing
+// token, and a token seen before is reported rather than followed.
+func (c *Client) searchPagesCloud(jql, fields string, handle func([]json.RawMessage) error) error {
+	params := url.Values{}
+	params.Set("jql", jql)
+	params.Set("fields", fields)
+	params.Set("maxResults", fmt.Sprintf("%d", searchPageSize))
+	seenTokens := make(map[string]bool)
+
+	for page := 1; ; page++ {
+		if page > maxSearchPages {
+			return fmt.Errorf("search exceeded %d pages, stopping", maxSearchPages)
+		}
+		apiURL := fmt.Sprintf("%s/rest/api/3/search/jql?%s", c.baseURL, params.Encode())
+
+		var searchResult struct {
+			Issues        []json.RawMessage `json:"issues"`
+			NextPageToken string            `json:"nextPageToken"`
+			IsLast        bool              `json:"isLast"`
+		}
+		if err := c.getPageJSON(apiURL, &searchResult); err != nil {
+			return fmt.Errorf("failed to search issues: %w", err)
+		}
+
+		if err := handle(searchResult.Issues); err != nil {
+			return err
+		}
+
+		// Stop on the last page, or when there is no token to continue from
+		if searchResult.IsLast || searchResult.NextPageToken == "" || len(searchResult.Issues) == 0 {
+			break
+		}
+		if seenTokens[searchResult.NextPageToken] {
+			return fmt.Errorf("search returned page token %q twice, stopping to avoid a loop", searchResult.NextPageToken)
+		}
+		seenTokens[searchResult.NextPageToken] = true
+		params.Set("nextPageToken", searchResult.NextPageToken)
+	}
+
+	return nil
+}
+
+// searchPagesServer pages through /rest/api/2/search on Jira Server and
+// Data Center using startAt/total
+func (c *Client) searchPagesServer(jql, fields string, handle func([]json.RawMessage) error) error {
+	params := url.Values{}
+	params.Set("jql", jql)
+	params.Set("fields", fields)
+	params.Set("maxResults", fmt.Sprintf("%d", searchPageSize))
+	startAt := 0
+
+	for page := 1; ; page++ {
+		if page > maxSearchPages {
+			return fmt.Errorf("search exceeded %d pages, stopping", maxSearchPages)
+		}
+		params.Set("startAt", fmt.Sprintf("%d", startAt))
+		apiURL := fmt.Sprintf("%s/rest/api/2/search?%s", c.baseURL, params.Encode())
+
+		var searchResult struct {
+			Issues []json.RawMessage `json:"issues"`
+			Total  int               `json:"total"`
+		}
+		if err := c.getPageJSON(apiURL, &searchResult); err != nil {
+			return fmt.Errorf("failed to search issues: %w", err)
+		}
+
+		if err := handle(searchResult.Issues); err != nil {
+			return err
+		}
+
+		startAt += len(searchResult.Issues)
+		if len(searchResult.Issues) == 0 || startAt >= searchResult.Total {
+			break
+		}
+	}
+
+	return nil
+}
+
+// searchFullIssues runs a JQL search and converts the full issue payloads
+// returned with each page, avoiding a request per issue
+func (c *Client) searchFullIssues(jql string) ([]*pb.Issue, error) {
+	issues := make([]*pb.Issue, 0)
+
+	err := c.searchPages(jql, c.fieldList(), func(page []json.RawMessage) error {
+		for _, data := range page {
+			issue, err := c.convertAPIIssue(data)
+			if err != nil {
+				return err
+			}
+			issues = append(issues, issue)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return issues, nil
+}
+
+// fetchIssuesByKey fetches issues in batches of "key in (...)" searches.
+// Keys a batch doesn't return (e.g. issues moved to another project) are
+// fetched individually.
+func (c *Client) fetchIssuesByKey(keys []string) ([]*pb.Issue, error) {
+	issues := make([]*pb.Issue, 0, len(keys))
+
+	for start := 0; start < len(keys); start += keyBatchSize {
+		end := start + keyBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch := keys[start:end]
+
+		found, err := c.searchFullIssues(fmt.Sprintf("key in (%s)", strings.Join(batch, ", ")))
+		if err != nil {
+			return nil, err
+		}
+
+		returned := make(map[string]bool)
+		for _, issue := range found {
+			returned[issue.Key] = true
+		}
+		issues = append(issues, found...)
+
+		for _, key := range batch {
+			if returned[key] {
+				continue
+			}
+			issue, err := c.FetchIssue(key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch %s: %w", key, err)
+			}
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues, nil
+}
+
+// withDependencies returns the given issues followed by all of their
+// dependencies. Dependencies outside the list are fetched level by level,
+// in batches, rather than one request per issue.
+func (c *Client) withDependencies(fetched []*pb.Issue) ([]*pb.Issue, error) {
+	seen := make(map[string]bool)
+	for _, issue := range fetched {
+		seen[issue.Key] = true
+		c.attachDevelopment(issue)
+	}
+
+	issues := append([]*pb.Issue{}, fetched...)
+	pending := fetched
+	for len(pending) > 0 {
+		keys := make([]string, 0)
+		for _, issue := range pending {
+			for _, key := range relatedKeys(issue) {
+				if !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+		}
+		if len(keys) == 0 {
+			break
+		}
+
+		fmt.Printf("Fetching %d related issue(s)...\n", len(keys))
+		related, err := c.fetchIssuesByKey(keys)
+		if err != nil {
+			return nil, err
+		}
+
+		pending = make([]*pb.Issue, 0, len(related))
+		added := make(map[string]bool)
+		for _, issue := range related {
+			// A moved issue is returned under its new key, which may already be known
+			if added[issue.Key] || (seen[issue.Key] && !slices.Contains(keys, issue.Key)) {
+				continue
+			}
+			added[issue.Key] = true
+			seen[issue.Key] = true
+			c.attachDevelopment(issue)
+			issues = append(issues, issue)
+			pending = append(pending, issue)
+		}
+	}
+
+	return issues, nil
+}
@@ -0,0 +1,429 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeJira is a test Jira server holding a fixed set of issues. Searches for
+// "key in (...)" return the named issues; any other JQL returns the issues
+// listed in results. Requests are counted per issue and per search.
+type fakeJira struct {
+	t              *testing.T
+	issues         map[string]map[string]interface{}
+	results        []string
+	deploymentType string // reported by /rest/api/2/serverInfo
+
+	searches []string       // JQL of every search request
+	paths    []string       // path of every search request
+	fields   []string       // fields requested by the last search
+	fetched  map[string]int // number of times each issue was returned
+	gets     int            // number of single-issue GET requests
+}
+
+// newFakeJira starts a fake Jira server; the caller must close it
+func newFakeJira(t *testing.T, issues []map[string]interface{}, results ...string) (*fakeJira, *httptest.Server) {
+	fake := &fakeJira{
+		t:              t,
+		issues:         make(map[string]map[string]interface{}),
+		results:        results,
+		deploymentType: "Cloud",
+		fetched:        make(map[string]int),
+	}
+	for _, issue := range issues {
+		fake.issues[issue["key"].(string)] = issue
+	}
+	return fake, httptest.NewServer(fake)
+}
+
+// requests returns the total number of API requests served
+func (f *fakeJira) requests() int {
+	return len(f.searches) + f.gets
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var response interface{}
+
+	switch {
+	case r.URL.Path == "/rest/api/2/serverInfo":
+		response = map[string]interface{}{"deploymentType": f.deploymentType, "version": "9.12.0"}
+	case r.URL.Path == "/rest/api/3/search/jql", r.URL.Path == "/rest/api/2/search":
+		response = f.search(r)
+	case strings.HasPrefix(r.URL.Path, "/rest/api/2/issue/"):
+		f.gets++
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		issue, ok := f.issues[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessages":["Issue does not exist"]}`))
+			return
+		}
+		f.fetched[key]++
+		response = issue
+	default:
+		f.t.Errorf("Unexpected request path: %s", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		f.t.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// search serves one page of a JQL search, paging by nextPageToken on the
+// Cloud endpoint and by startAt/total on the Server endpoint
+func (f *fakeJira) search(r *http.Request) interface{} {
+	query := r.URL.Query()
+	jql := query.Get("jql")
+	f.searches = append(f.searches, jql)
+	f.paths = append(f.paths, r.URL.Path)
+	f.fields = strings.Split(query.Get("fields"), ",")
+
+	keys := f.results
+	if strings.HasPrefix(jql, "key in (") {
+		keys = nil
+		for _, key := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(jql, "key in ("), ")"), ",") {
+			if _, ok := f.issues[strings.TrimSpace(key)]; ok {
+				keys = append(keys, strings.TrimSpace(key))
+			}
+		}
+	}
+
+	startAt, _ := strconv.Atoi(query.Get("startAt"))
+	if token := query.Get("nextPageToken"); token != "" {
+		startAt, _ = strconv.Atoi(strings.TrimPrefix(token, "page-"))
+	}
+	maxResults, _ := strconv.Atoi(query.Get("maxResults"))
+	end := startAt + maxResults
+	if maxResults == 0 || end > len(keys) {
+		end = len(keys)
+	}
+
+	page := make([]map[string]interface{}, 0)
+	for _, key := range keys[startAt:end] {
+		f.fetched[key]++
+		page = append(page, f.issues[key])
+	}
+
+	if r.URL.Path == "/rest/api/2/search" {
+		return map[string]interface{}{
+			"issues":     page,
+			"startAt":    startAt,
+			"maxResults": maxResults,
+			"total":      len(keys),
+		}
+	}
+
+	response := map[string]interface{}{
+		"issues": page,
+		"isLast": end == len(keys),
+	}
+	if end < len(keys) {
+		response["nextPageToken"] = fmt.Sprintf("page-%d", end)
+	}
+	return response
+}
+
+// withSubtasks returns a copy of an issue with the given subtask keys
+func withSubtasks(issue map[string]interface{}, keys ...string) map[string]interface{} {
+	subtasks := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		subtasks = append(subtasks, map[string]interface{}{"key": key})
+	}
+	issue["fields"].(map[string]interface{})["subtasks"] = subtasks
+	return issue
+}
+
+func TestFetchIssuesByLabelBulk(t *testing.T) {
+	// 500 labelled issues, each with a subtask outside the label
+	var issues []map[string]interface{}
+	var labelled []string
+	for i := 1; i <= 500; i++ {
+		key := fmt.Sprintf("PROJ-%d", i)
+		subtaskKey := fmt.Sprintf("SUB-%d", i)
+		issues = append(issues,
+			withSubtasks(createMinimalIssue(key, "Labelled"), subtaskKey),
+			createMinimalIssue(subtaskKey, "Subtask"),
+		)
+		labelled = append(labelled, key)
+	}
+
+	fake, server := newFakeJira(t, issues, labelled...)
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	export, err := client.FetchIssuesByLabel("big-release")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(export.Issues) != 1000 {
+		t.Errorf("Expected 1000 issues, got %d", len(export.Issues))
+	}
+
+	// 5 search pages + 10 batches of 50 subtasks, instead of 1000 GETs
+	if fake.requests() != 15 {
+		t.Errorf("Expected 15 requests, got %d", fake.requests())
+	}
+	if fake.gets != 0 {
+		t.Errorf("Expected no single-issue requests, got %d", fake.gets)
+	}
+	for key, count := range fake.fetched {
+		if count != 1 {
+			t.Errorf("Expected %s to be fetched once, got %d", key, count)
+		}
+	}
+}
+
+func TestFetchIssuesByJQLSearchFields(t *testing.T) {
+	fake, server := newFakeJira(t, []map[string]interface{}{createMinimalIssue("PROJ-1", "Only")}, "PROJ-1")
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	if _, err := client.FetchIssuesByJQL("project = PROJ"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.Join(fake.fields, ",") != strings.Join(defaultSearchFields, ",") {
+		t.Errorf("Expected default search fields, got %v", fake.fields)
+	}
+
+	client.SetSearchFields([]string{"summary", "issuetype", "status"})
+	client.SetRankField("customfield_10019")
+	if _, err := client.FetchIssuesByJQL("project = PROJ"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.Join(fake.fields, ",") != "summary,issuetype,status,customfield_10019" {
+		t.Errorf("Expected configured fields plus rank field, got %v", fake.fields)
+	}
+}
+
+func TestFetchIssuesByKeyFallsBackToSingleFetch(t *testing.T) {
+	// PROJ-2 was moved to OTHER-7: the batch search doesn't return it under
+	// its old key, so it is fetched individually
+	moved := createMinimalIssue("OTHER-7", "Moved issue")
+	fake, server := newFakeJira(t, []map[string]interface{}{
+		withSubtasks(createMinimalIssue("PROJ-1", "Parent"), "PROJ-2", "PROJ-3"),
+		createMinimalIssue("PROJ-3", "Subtask"),
+	}, "PROJ-1")
+	fake.issues["PROJ-2"] = moved
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	export, err := client.FetchIssuesByJQL("project = PROJ")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	keys := make([]string, 0)
+	for _, issue := range export.Issues {
+		keys = append(keys, issue.Key)
+	}
+	if strings.Join(keys, ",") != "PROJ-1,OTHER-7,PROJ-3" {
+		t.Errorf("Expected PROJ-1,OTHER-7,PROJ-3, got %v", keys)
+	}
+	if fake.gets != 1 {
+		t.Errorf("Expected 1 single-issue request, got %d", fake.gets)
+	}
+}
+
+func TestFetchIssuesByJQLOnServer(t *testing.T) {
+	var issues []map[string]interface{}
+	var results []string
+	for i := 1; i <= 250; i++ {
+		key := fmt.Sprintf("PROJ-%d", i)
+		issues = append(issues, createMinimalIssue(key, "Issue"))
+		results = append(results, key)
+	}
+
+	fake, server := newFakeJira(t, issues, results...)
+	fake.deploymentType = "DataCenter"
+	defer server.Close()
+
+	var paths []string
+	wrapped := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fake.ServeHTTP(w, r)
+	}))
+	defer wrapped.Close()
+
+	client := NewClient(wrapped.URL, "user@example.com", "token123", "basic")
+	client.SetDeployment(DeploymentAuto)
+
+	export, err := client.FetchIssuesByJQL("project = PROJ")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(export.Issues) != 250 {
+		t.Errorf("Expected 250 issues, got %d", len(export.Issues))
+	}
+	if client.Deployment() != DeploymentServer {
+		t.Errorf("Expected detected deployment %q, got %q", DeploymentServer, client.Deployment())
+	}
+
+	expected := []string{"/rest/api/2/serverInfo", "/rest/api/2/search", "/rest/api/2/search", "/rest/api/2/search"}
+	if strings.Join(paths, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected requests %v, got %v", expected, paths)
+	}
+}
+
+func TestSetDeployment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cloud", DeploymentCloud},
+		{"server", DeploymentServer},
+		{"datacenter", DeploymentServer},
+		{"DataCenter", DeploymentServer},
+		{"", DeploymentCloud},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			client := NewClient("https://jira.example.com", "user", "token", "basic")
+			client.SetDeployment(tt.input)
+			if got := client.Deployment(); got != tt.expected {
+				t.Errorf("SetDeployment(%q) gave deployment %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDeploymentDetectionFallsBackToCloud(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	client.SetDeployment(DeploymentAuto)
+
+	if got := client.Deployment(); got != DeploymentCloud {
+		t.Errorf("Expected fallback to %q, got %q", DeploymentCloud, got)
+	}
+}
+
+func TestSearchIssuesPagingStyles(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment string
+		path       string
+	}{
+		{"cloud nextPageToken", DeploymentCloud, "/rest/api/3/search/jql"},
+		{"server startAt", DeploymentServer, "/rest/api/2/search"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var issues []map[string]interface{}
+			var results []string
+			for i := 1; i <= 345; i++ {
+				key := fmt.Sprintf("PROJ-%d", i)
+				issues = append(issues, createMinimalIssue(key, "Issue"))
+				results = append(results, key)
+			}
+
+			fake, server := newFakeJira(t, issues, results...)
+			defer server.Close()
+
+			client := NewClient(server.URL, "user@example.com", "token123", "basic")
+			client.SetDeployment(tt.deployment)
+
+			keys, err := client.SearchIssues("project = PROJ")
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if len(keys) != 345 {
+				t.Errorf("Expected 345 keys, got %d", len(keys))
+			}
+			if keys[0] != "PROJ-1" || keys[344] != "PROJ-345" {
+				t.Errorf("Expected keys PROJ-1..PROJ-345, got %s..%s", keys[0], keys[len(keys)-1])
+			}
+			if len(fake.searches) != 4 {
+				t.Errorf("Expected 4 search pages, got %d", len(fake.searches))
+			}
+			for _, path := range fake.paths {
+				if path != tt.path {
+					t.Errorf("Expected searches on %s, got %s", tt.path, path)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchIssuesRepeatedPageToken(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		// Every page points back at the same token
+		response := map[string]interface{}{
+			"issues":        []map[string]interface{}{{"key": fmt.Sprintf("PROJ-%d", requestCount)}},
+			"nextPageToken": "same-token",
+			"isLast":        false,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	_, err := client.SearchIssues("project = PROJ")
+	if err == nil {
+		t.Fatal("Expected an error for a repeated page token")
+	}
+	if !strings.Contains(err.Error(), "twice") {
+		t.Errorf("Expected loop error, got: %v", err)
+	}
+	if requestCount != 2 {
+		t.Errorf("Expected 2 requests before detecting the loop, got %d", requestCount)
+	}
+}
+
+func TestSearchIssuesPageCap(t *testing.T) {
+	defer func(pages int) { maxSearchPages = pages }(maxSearchPages)
+	maxSearchPages = 3
+
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		// A fresh token every page, never the last page
+		response := map[string]interface{}{
+			"issues":        []map[string]interface{}{{"key": fmt.Sprintf("PROJ-%d", requestCount)}},
+			"nextPageToken": fmt.Sprintf("token-%d", requestCount),
+			"isLast":        false,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+
+	_, err := client.SearchIssues("project = PROJ")
+	if err == nil {
+		t.Fatal("Expected an error when exceeding the page cap")
+	}
+	if !strings.Contains(err.Error(), "exceeded 3 pages") {
+		t.Errorf("Expected page cap error, got: %v", err)
+	}
+	if requestCount != 3 {
+		t.Errorf("Expected 3 requests, got %d", requestCount)
+	}
+}
@@ -0,0 +1,79 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Deployment types. Jira Cloud and Jira Server/Data Center expose different
+// search endpoints and pagination.
+const (
+	// DeploymentAuto detects the deployment type from /rest/api/2/serverInfo
+	DeploymentAuto = "auto"
+	// DeploymentCloud uses /rest/api/3/search/jql with nextPageToken pagination
+	DeploymentCloud = "cloud"
+	// DeploymentServer covers Jira Server and Data Center, using
+	// /rest/api/2/search with startAt/total pagination
+	DeploymentServer = "server"
+)
+
+// ServerInfo represents the response of /rest/api/2/serverInfo
+type ServerInfo struct {
+	BaseURL        string `json:"baseUrl"`
+	Version        string `json:"version"`
+	VersionNumbers []int  `json:"versionNumbers"`
+	DeploymentType string `json:"deploymentType"` // "Cloud", "Server" or "DataCenter"
+	BuildNumber    int    `json:"buildNumber"`
+	ServerTitle    string `json:"serverTitle"`
+}
+
+// Deployment returns the deployment type ("cloud" or "server") reported by Jira
+func (s *ServerInfo) Deployment() string {
+	if strings.EqualFold(s.DeploymentType, "Cloud") {
+		return DeploymentCloud
+	}
+	return DeploymentServer
+}
+
+// GetServerInfo fetches information about the Jira instance
+func (c *Client) GetServerInfo() (*ServerInfo, error) {
+	apiURL := fmt.Sprintf("%s/rest/api/2/serverInfo", c.baseURL)
+
+	var info ServerInfo
+	if err := c.getJSON(apiURL, &info); err != nil {
+		return nil, fmt.Errorf("failed to fetch server info: %w", err)
+	}
+
+	return &info, nil
+}
+
+// SetDeployment sets the deployment type: "cloud", "server" ("datacenter" is
+// accepted as an alias) or "auto" to detect it on first use
+func (c *Client) SetDeployment(deployment string) {
+	switch strings.ToLower(deployment) {
+	case "datacenter", "data-center", DeploymentServer:
+		c.deployment = DeploymentServer
+	case DeploymentAuto:
+		c.deployment = DeploymentAuto
+	default:
+		c.deployment = DeploymentCloud
+	}
+}
+
+// Deployment returns the deployment type, detecting it if it is set to "auto".
+// If detection fails the client falls back to Jira Cloud.
+func (c *Client) Deployment() string {
+	if c.deployment != DeploymentAuto {
+		return c.deployment
+	}
+
+	info, err := c.GetServerInfo()
+	if err != nil {
+		fmt.Printf("⚠ Warning: could not detect Jira deployment type, assuming Cloud: %v\n", err)
+		c.deployment = DeploymentCloud
+		return c.deployment
+	}
+
+	c.deployment = info.Deployment()
+	return c.deployment
+}
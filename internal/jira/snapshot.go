@@ -0,0 +1,259 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	pb "github.com/conallob/jira-beads-sync/gen/jira"
+)
+
+// SnapshotDir is the directory inside .beads where the raw Jira data of the
+// last fetch is kept
+const SnapshotDir = ".jira-cache"
+
+// Files of a snapshot directory
+const (
+	snapshotFile            = "snapshot.json"
+	snapshotIssuesFile      = "issues.json"
+	snapshotSprintFile      = "sprint.json"
+	snapshotDevelopmentFile = "development.json"
+)
+
+// Snapshot holds the raw Jira responses behind a fetch, so the fetch can be
+// converted again, e.g. with a different mapping, without access to Jira.
+// A Client records the responses it converts into the snapshot set with
+// SetSnapshot; Save writes them, and LoadSnapshot reads them back.
+//
+// The issues are saved as a Jira JSON export, which Pipeline and convert
+// read like any other. Data fetched separately from the issues, such as
+// development information, is restored with Restore.
+type Snapshot struct {
+	Command   string    `json:"command"` // fetch command that took the snapshot, e.g. "fetch-board"
+	BaseURL   string    `json:"baseUrl"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	RankField string    `json:"rankField,omitempty"`
+	// SprintIssues are the issues Jira listed in the fetched sprint
+	SprintIssues []string `json:"sprintIssues,omitempty"`
+
+	dir         string                     // set by LoadSnapshot
+	issues      map[string]json.RawMessage // by key
+	sprint      json.RawMessage
+	sprintID    int64
+	development snapshotDevelopment
+}
+
+// snapshotDevelopment holds the responses of the endpoints fetched per
+// issue for development information
+type snapshotDevelopment struct {
+	RemoteLinks map[string]json.RawMessage   `json:"remoteLinks,omitempty"` // by issue key
+	Details     map[string][]json.RawMessage `json:"details,omitempty"`     // dev-status details by issue ID
+}
+
+// NewSnapshot creates an empty snapshot for a fetch command
+func NewSnapshot(command string) *Snapshot {
+	return &Snapshot{
+		Command:   command,
+		FetchedAt: time.Now().UTC(),
+		issues:    make(map[string]json.RawMessage),
+		development: snapshotDevelopment{
+			RemoteLinks: make(map[string]json.RawMessage),
+			Details:     make(map[string][]json.RawMessage),
+		},
+	}
+}
+
+// SetSnapshot makes the client record the raw responses it converts into s
+func (c *Client) SetSnapshot(s *Snapshot) {
+	c.snapshot = s
+	s.BaseURL = c.baseURL
+	s.RankField = c.adapter.RankField()
+}
+
+// The record methods do nothing on a nil snapshot, so the client can call
+// them whether or not a snapshot is being taken
+
+func (s *Snapshot) recordIssue(key string, data json.RawMessage) {
+	if s != nil {
+		s.issues[key] = data
+	}
+}
+
+func (s *Snapshot) recordSprint(id int64, data json.RawMessage) {
+	if s != nil {
+		s.sprint = data
+		s.sprintID = id
+	}
+}
+
+func (s *Snapshot) recordSprintIssue(key string) {
+	if s != nil && !slices.Contains(s.SprintIssues, key) {
+		s.SprintIssues = append(s.SprintIssues, key)
+	}
+}
+
+func (s *Snapshot) recordRankField(fieldID string) {
+	if s != nil {
+		s.RankField = fieldID
+	}
+}
+
+func (s *Snapshot) recordRemoteLinks(key string, data json.RawMessage) {
+	if s != nil {
+		s.development.RemoteLinks[key] = data
+	}
+}
+
+func (s *Snapshot) recordDevelopment(issueID string, details []json.RawMessage) {
+	if s != nil {
+		s.development.Details[issueID] = details
+	}
+}
+
+// Save writes the snapshot of a fetch to dir, replacing any snapshot there.
+// The issues are written in the order of the export. The directory is
+// ignored by git, as the raw data isn't meant to be committed.
+func (s *Snapshot) Save(dir string, export *pb.Export) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove old snapshot: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*\n"), 0644); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	// One issue per line keeps the file readable and diffable
+	var issues bytes.Buffer
+	issues.WriteString("{\"issues\": [\n")
+	for i, issue := range export.Issues {
+		data, ok := s.issues[issue.Key]
+		if !ok {
+			return fmt.Errorf("no JSON recorded for issue %s", issue.Key)
+		}
+		if i > 0 {
+			issues.WriteString(",\n")
+		}
+		if err := json.Compact(&issues, data); err != nil {
+			return fmt.Errorf("failed to encode issue %s: %w", issue.Key, err)
+		}
+	}
+	issues.WriteString("\n]}\n")
+	if err := os.WriteFile(filepath.Join(dir, snapshotIssuesFile), issues.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot issues: %w", err)
+	}
+
+	if export.Sprint != nil && s.sprint != nil && s.sprintID == export.Sprint.Id {
+		if err := os.WriteFile(filepath.Join(dir, snapshotSprintFile), s.sprint, 0644); err != nil {
+			return fmt.Errorf("failed to write snapshot sprint: %w", err)
+		}
+	}
+
+	if len(s.development.RemoteLinks) > 0 || len(s.development.Details) > 0 {
+		if err := writeSnapshotJSON(filepath.Join(dir, snapshotDevelopmentFile), s.development); err != nil {
+			return fmt.Errorf("failed to write snapshot development information: %w", err)
+		}
+	}
+
+	if err := writeSnapshotJSON(filepath.Join(dir, snapshotFile), s); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// writeSnapshotJSON writes v as indented JSON
+func writeSnapshotJSON(filename string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// LoadSnapshot reads a snapshot saved to dir by Save. The error wraps
+// fs.ErrNotExist if there is no snapshot in dir.
+func LoadSnapshot(dir string) (*Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(dir, snapshotFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	s := NewSnapshot("")
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	s.dir = dir
+
+	sprint, err := os.ReadFile(filepath.Join(dir, snapshotSprintFile))
+	if err == nil {
+		s.sprint = sprint
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read snapshot sprint: %w", err)
+	}
+
+	development, err := os.ReadFile(filepath.Join(dir, snapshotDevelopmentFile))
+	if err == nil {
+		if err := json.Unmarshal(development, &s.development); err != nil {
+			return nil, fmt.Errorf("failed to parse snapshot development information: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read snapshot development information: %w", err)
+	}
+
+	return s, nil
+}
+
+// IssuesFile returns the path of the Jira JSON export holding the issues of
+// a loaded snapshot
+func (s *Snapshot) IssuesFile() string {
+	return filepath.Join(s.dir, snapshotIssuesFile)
+}
+
+// Sprint returns the sprint the snapshot was fetched from, or nil if it
+// wasn't fetched from a sprint
+func (s *Snapshot) Sprint() (*pb.Sprint, error) {
+	if s.sprint == nil {
+		return nil, nil
+	}
+
+	var sprint jsonSprint
+	if err := json.Unmarshal(s.sprint, &sprint); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot sprint: %w", err)
+	}
+	return NewAdapter().convertSprint(&sprint)
+}
+
+// Restore adds to an issue read from the snapshot what the fetch added to it
+// besides its JSON: its remote links and development information, and the
+// sprint it was fetched from
+func (s *Snapshot) Restore(issue *pb.Issue, sprint *pb.Sprint) error {
+	if data, ok := s.development.RemoteLinks[issue.Key]; ok {
+		var links []jsonRemoteLink
+		if err := json.Unmarshal(data, &links); err != nil {
+			return fmt.Errorf("failed to parse remote links: %w", err)
+		}
+		issue.RemoteLinks = convertRemoteLinks(links)
+	}
+
+	if bodies, ok := s.development.Details[issue.Id]; ok {
+		details := make([]jsonDevDetail, len(bodies))
+		for i, body := range bodies {
+			if err := json.Unmarshal(body, &details[i]); err != nil {
+				return fmt.Errorf("failed to parse development details: %w", err)
+			}
+		}
+		issue.PullRequests, issue.Repositories = convertDevelopment(details)
+	}
+
+	if sprint != nil && slices.Contains(s.SprintIssues, issue.Key) {
+		assignSprint(issue, sprint)
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/conallob/jira-beads-sync/gen/jira"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSnapshotSaveAndRestore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+
+		switch r.URL.Path {
+		case "/rest/agile/1.0/sprint/42":
+			response = sprintResponse(42, "Sprint 42", "active")
+		case "/rest/agile/1.0/sprint/42/issue":
+			// PROJ-1 carries its sprint, PROJ-2 does not and is blocked by PROJ-3
+			proj1 := createMinimalIssue("PROJ-1", "In sprint")
+			proj1["fields"].(map[string]interface{})["sprint"] = sprintResponse(42, "Sprint 42", "active")
+			proj1["fields"].(map[string]interface{})["customfield_10019"] = "0|i0001:"
+
+			proj2 := createMinimalIssue("PROJ-2", "Also in sprint")
+			proj2["fields"].(map[string]interface{})["issuelinks"] = []map[string]interface{}{
+				{
+					"id":          "1",
+					"type":        map[string]interface{}{"name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+					"inwardIssue": map[string]interface{}{"key": "PROJ-3"},
+				},
+			}
+
+			response = map[string]interface{}{
+				"total":  2,
+				"issues": []map[string]interface{}{proj1, proj2},
+			}
+		case "/rest/api/3/search/jql":
+			response = map[string]interface{}{
+				"issues": []map[string]interface{}{createMinimalIssue("PROJ-3", "Blocker outside the sprint")},
+				"isLast": true,
+			}
+		case "/rest/api/2/issue/PROJ-1/remotelink":
+			response = []map[string]interface{}{
+				{"id": 1, "object": map[string]interface{}{"url": "https://example.com/doc", "title": "Design"}},
+			}
+		case "/rest/api/2/issue/PROJ-2/remotelink", "/rest/api/2/issue/PROJ-3/remotelink":
+			response = []interface{}{}
+		case "/rest/dev-status/latest/issue/summary":
+			count := 0
+			if r.URL.Query().Get("issueId") == "PROJ-2-id" {
+				count = 1
+			}
+			response = map[string]interface{}{
+				"summary": map[string]interface{}{
+					"pullrequest": map[string]interface{}{
+						"byInstanceType": map[string]interface{}{
+							"GitHub": map[string]interface{}{"count": count, "name": "GitHub"},
+						},
+					},
+				},
+			}
+		case "/rest/dev-status/latest/issue/detail":
+			response = map[string]interface{}{
+				"detail": []map[string]interface{}{{
+					"pullRequests": []map[string]interface{}{{
+						"id":            "#12",
+						"url":           "https://github.com/acme/api/pull/12",
+						"status":        "OPEN",
+						"repositoryUrl": "https://github.com/acme/api",
+					}},
+				}},
+			}
+		default:
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	client.SetFetchDevelopment(true)
+	snapshot := NewSnapshot("fetch-sprint")
+	client.SetSnapshot(snapshot)
+	client.SetRankField("customfield_10019")
+
+	export, err := client.FetchSprint(42)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), SnapshotDir)
+	if err := snapshot.Save(dir, export); err != nil {
+		t.Fatalf("Failed to save snapshot: %v", err)
+	}
+
+	loaded, err := LoadSnapshot(dir)
+	if err != nil {
+		t.Fatalf("Failed to load snapshot: %v", err)
+	}
+	if loaded.Command != "fetch-sprint" || loaded.BaseURL != server.URL || loaded.RankField != "customfield_10019" {
+		t.Errorf("Unexpected snapshot manifest: %+v", loaded)
+	}
+
+	sprint, err := loaded.Sprint()
+	if err != nil {
+		t.Fatalf("Failed to read snapshot sprint: %v", err)
+	}
+	if !proto.Equal(sprint, export.Sprint) {
+		t.Errorf("Expected sprint %v, got %v", export.Sprint, sprint)
+	}
+
+	// The saved issues are a Jira export of their own, which converts back
+	// to the fetched issues once restored
+	data, err := os.ReadFile(loaded.IssuesFile())
+	if err != nil {
+		t.Fatalf("Failed to read snapshot issues: %v", err)
+	}
+	adapter := NewAdapter()
+	adapter.SetRankField(loaded.RankField)
+	restored, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse snapshot issues: %v", err)
+	}
+	if len(restored.Issues) != len(export.Issues) {
+		t.Fatalf("Expected %d issues, got %d", len(export.Issues), len(restored.Issues))
+	}
+	for i, issue := range restored.Issues {
+		if err := loaded.Restore(issue, sprint); err != nil {
+			t.Fatalf("Failed to restore %s: %v", issue.Key, err)
+		}
+		if !proto.Equal(issue, export.Issues[i]) {
+			t.Errorf("Expected restored issue\n%v\ngot\n%v", export.Issues[i], issue)
+		}
+	}
+	if got := restored.Issues[1].PullRequests; len(got) != 1 || got[0].Url != "https://github.com/acme/api/pull/12" {
+		t.Errorf("Expected the pull request of PROJ-2 restored, got %v", got)
+	}
+}
+
+func TestSnapshotSaveReplacesOldSnapshot(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), SnapshotDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, snapshotSprintFile), []byte(`{"id": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := NewSnapshot("quickstart")
+	snapshot.recordIssue("PROJ-1", json.RawMessage(`{"key": "PROJ-1"}`))
+	if err := snapshot.Save(dir, &pb.Export{Issues: []*pb.Issue{{Key: "PROJ-1"}}}); err != nil {
+		t.Fatalf("Failed to save snapshot: %v", err)
+	}
+
+	loaded, err := LoadSnapshot(dir)
+	if err != nil {
+		t.Fatalf("Failed to load snapshot: %v", err)
+	}
+	if sprint, err := loaded.Sprint(); sprint != nil || err != nil {
+		t.Errorf("Expected the old sprint to be removed, got %v (%v)", sprint, err)
+	}
+
+	if _, err := LoadSnapshot(t.TempDir()); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected a missing snapshot to be reported as not existing, got %v", err)
+	}
+}
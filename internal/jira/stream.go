@@ -0,0 +1,304 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	pb "github.com/conallob/jira-beads-sync/gen/jira"
+)
+
+// IssueDecoder reads the issues of a Jira JSON export one at a time, so
+// exports of any size can be converted without holding them in memory.
+//
+// Besides exports shaped like {"issues": [...]}, it reads the responses of the
+// REST API saved as they are: search results with paging fields, a single
+// issue from /issue/{key}, an array of issues, and any number of these
+// concatenated, e.g. search pages saved one per line as JSONL.
+type IssueDecoder struct {
+	adapter     *Adapter
+	decoder     *json.Decoder
+	state       decoderState
+	pending     []indexedJSONIssue         // issues decoded ahead of Next
+	fields      map[string]json.RawMessage // top-level fields of the current object
+	page        bool                       // the current object has an "issues" array
+	read        int                        // issues read, including skipped ones
+	count       int                        // issues returned
+	diagnostics []Diagnostic
+}
+
+// indexedJSONIssue is a decoded issue with its position in the export
+type indexedJSONIssue struct {
+	index int
+	issue jsonIssue
+}
+
+// decoderState is the position of an IssueDecoder in the export
+type decoderState int
+
+const (
+	stateTop    decoderState = iota // between top-level values
+	stateObject                     // in the fields of a top-level object
+	statePage                       // in the "issues" array of a search page
+	stateArray                      // in a top-level array of issues
+	stateDone
+)
+
+// NewIssueDecoder creates a decoder reading a Jira JSON export from r
+func (a *Adapter) NewIssueDecoder(r io.Reader) *IssueDecoder {
+	return &IssueDecoder{
+		adapter: a,
+		decoder: json.NewDecoder(r),
+	}
+}
+
+// Next returns the next issue of the export, or io.EOF after the last one.
+// In lenient mode, issues that are skipped are not returned.
+func (d *IssueDecoder) Next() (*pb.Issue, error) {
+	for {
+		next, err := d.nextJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		issue, problems := d.adapter.convertJSONIssue(&next.issue)
+		diagnostics, skip, err := d.adapter.checkIssue(next.index, issue, problems, jsonPaths)
+		if err != nil {
+			return nil, err
+		}
+		d.diagnostics = append(d.diagnostics, diagnostics...)
+		if skip {
+			continue
+		}
+
+		d.count++
+		return issue, nil
+	}
+}
+
+// Diagnostics returns the problems lenient parsing skipped or repaired in
+// the issues read so far
+func (d *IssueDecoder) Diagnostics() []Diagnostic {
+	return d.diagnostics
+}
+
+// nextJSON returns the next issue as decoded from JSON
+func (d *IssueDecoder) nextJSON() (*indexedJSONIssue, error) {
+	for {
+		if len(d.pending) > 0 {
+			next := d.pending[0]
+			d.pending = d.pending[1:]
+			return &next, nil
+		}
+
+		switch d.state {
+		case stateDone:
+			return nil, io.EOF
+
+		case stateTop:
+			if err := d.startValue(); err != nil {
+				return nil, err
+			}
+
+		case statePage, stateArray:
+			if !d.decoder.More() {
+				if err := d.expectDelim(']'); err != nil {
+					return nil, err
+				}
+				if d.state == statePage {
+					d.state = stateObject
+				} else {
+					d.state = stateTop
+				}
+				continue
+			}
+			if err := d.decodeElement(); err != nil {
+				return nil, err
+			}
+
+		case stateObject:
+			if err := d.readField(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// startValue starts reading the next top-level value, or finishes the export
+func (d *IssueDecoder) startValue() error {
+	token, err := d.decoder.Token()
+	if err == io.EOF {
+		d.state = stateDone
+		if d.count == 0 {
+			return fmt.Errorf("validation failed: export contains no issues")
+		}
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse Jira export: %w", err)
+	}
+
+	switch token {
+	case json.Delim('{'):
+		d.state = stateObject
+		d.fields = make(map[string]json.RawMessage)
+		d.page = false
+	case json.Delim('['):
+		d.state = stateArray
+	default:
+		return fmt.Errorf("failed to parse Jira export: expected an object or array, got %v", token)
+	}
+	return nil
+}
+
+// readField reads the next field of a top-level object. The "issues" array
+// of a search page is streamed; other fields are kept until the end of the
+// object shows whether it was a single issue.
+func (d *IssueDecoder) readField() error {
+	if !d.decoder.More() {
+		if err := d.expectDelim('}'); err != nil {
+			return err
+		}
+		d.state = stateTop
+		return d.endObject()
+	}
+
+	token, err := d.decoder.Token()
+	if err != nil {
+		return fmt.Errorf("failed to parse Jira export: %w", err)
+	}
+	name, _ := token.(string)
+
+	if name == "issues" && !d.page {
+		if err := d.expectDelim('['); err != nil {
+			return err
+		}
+		d.page = true
+		d.state = statePage
+		return nil
+	}
+
+	var value json.RawMessage
+	if err := d.decoder.Decode(&value); err != nil {
+		return fmt.Errorf("failed to parse Jira export: %w", err)
+	}
+	if !d.page {
+		d.fields[name] = value
+	}
+	return nil
+}
+
+// endObject handles the end of a top-level object that wasn't a search page
+func (d *IssueDecoder) endObject() error {
+	fields := d.fields
+	d.fields = nil
+	if d.page {
+		return nil
+	}
+
+	if isIssue(fields) {
+		// A single issue, as returned by /issue/{key}
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return fmt.Errorf("failed to parse Jira export: %w", err)
+		}
+		return d.queue(data)
+	}
+
+	return unrecognisedExport(fields)
+}
+
+// decodeElement decodes the next element of an issues array. Elements of a
+// top-level array may themselves be search pages.
+func (d *IssueDecoder) decodeElement() error {
+	var element json.RawMessage
+	if err := d.decoder.Decode(&element); err != nil {
+		return fmt.Errorf("failed to parse issue at index %d: %w", d.read, err)
+	}
+	if d.state == statePage {
+		return d.decodeIssue(element)
+	}
+	return d.queue(element)
+}
+
+// queue decodes a single issue or search page held in memory
+func (d *IssueDecoder) queue(data json.RawMessage) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Not an object, so decoding it as an issue fails
+		return d.decodeIssue(data)
+	}
+
+	if _, ok := fields["issues"]; ok {
+		var page struct {
+			Issues []json.RawMessage `json:"issues"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("failed to parse Jira export: %w", err)
+		}
+		for _, issue := range page.Issues {
+			if err := d.decodeIssue(issue); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !isIssue(fields) {
+		return unrecognisedExport(fields)
+	}
+
+	return d.decodeIssue(data)
+}
+
+// decodeIssue decodes an issue of the export. In lenient mode an issue that
+// can't be decoded is skipped and reported rather than failing the export.
+func (d *IssueDecoder) decodeIssue(data json.RawMessage) error {
+	index := d.read
+	d.read++
+
+	var issue jsonIssue
+	if err := json.Unmarshal(data, &issue); err != nil {
+		if !d.adapter.lenient {
+			return fmt.Errorf("failed to parse issue at index %d: %w", index, err)
+		}
+		d.diagnostics = append(d.diagnostics, undecodableIssue(index, data, err))
+		return nil
+	}
+
+	d.pending = append(d.pending, indexedJSONIssue{index: index, issue: issue})
+	return nil
+}
+
+// isIssue reports whether the fields of a JSON object are those of an issue;
+// an issue missing its key still has its fields
+func isIssue(fields map[string]json.RawMessage) bool {
+	_, hasKey := fields["key"]
+	_, hasFields := fields["fields"]
+	return hasKey || hasFields
+}
+
+// unrecognisedExport explains a JSON object that is neither an issue nor a
+// page of issues, such as a saved error response
+func unrecognisedExport(fields map[string]json.RawMessage) error {
+	var messages []string
+	if raw, ok := fields["errorMessages"]; ok {
+		_ = json.Unmarshal(raw, &messages)
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("failed to parse Jira export: it is a Jira error response: %s", strings.Join(messages, "; "))
+	}
+	return fmt.Errorf("failed to parse Jira export: expected an issue or an object with \"issues\"")
+}
+
+// expectDelim reads the next token, which must be the delimiter
+func (d *IssueDecoder) expectDelim(delim json.Delim) error {
+	token, err := d.decoder.Token()
+	if err != nil {
+		return fmt.Errorf("failed to parse Jira export: %w", err)
+	}
+	if token != delim {
+		return fmt.Errorf("failed to parse Jira export: expected %q, got %v", delim, token)
+	}
+	return nil
+}
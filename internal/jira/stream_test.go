@@ -0,0 +1,188 @@
+package jira
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	pb "github.com/conallob/jira-beads-sync/gen/jira"
+	"google.golang.org/protobuf/proto"
+)
+
+// decodeAll reads every issue from a decoder
+func decodeAll(decoder *IssueDecoder) ([]*pb.Issue, error) {
+	var issues []*pb.Issue
+	for {
+		issue, err := decoder.Next()
+		if err == io.EOF {
+			return issues, nil
+		}
+		if err != nil {
+			return issues, err
+		}
+		issues = append(issues, issue)
+	}
+}
+
+func TestIssueDecoderMatchesParse(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/sample-jira-export.json")
+	if err != nil {
+		t.Fatalf("Failed to read sample: %v", err)
+	}
+
+	adapter := NewAdapter()
+	export, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse sample: %v", err)
+	}
+
+	issues, err := decodeAll(adapter.NewIssueDecoder(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("Failed to decode sample: %v", err)
+	}
+
+	if len(issues) != len(export.Issues) {
+		t.Fatalf("Expected %d issues, got %d", len(export.Issues), len(issues))
+	}
+	for i := range issues {
+		if !proto.Equal(issues[i], export.Issues[i]) {
+			t.Errorf("Issue %d differs from Parse:\n%v\n%v", i, issues[i], export.Issues[i])
+		}
+	}
+
+	// Reading past the end keeps returning io.EOF
+	decoder := adapter.NewIssueDecoder(bytes.NewReader(data))
+	_, _ = decodeAll(decoder)
+	if _, err := decoder.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF after the last issue, got: %v", err)
+	}
+}
+
+func TestIssueDecoderSkipsOtherFields(t *testing.T) {
+	data := `{"startAt": 0, "names": {"summary": "Summary"}, "issues": [
+		{"id": "1", "key": "PROJ-1", "fields": {"summary": "First", "issuetype": {"name": "Task"}}},
+		{"id": "2", "key": "PROJ-2", "fields": {"summary": "Second", "issuetype": {"name": "Task"}}}
+	], "total": 2, "warningMessages": ["a", "b"]}`
+
+	issues, err := decodeAll(NewAdapter().NewIssueDecoder(strings.NewReader(data)))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Key != "PROJ-1" || issues[1].Fields.Summary != "Second" {
+		t.Errorf("Unexpected issues: %v", issues)
+	}
+}
+
+func TestIssueDecoderErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		errorMsg string
+	}{
+		{"not an object", `"PROJ-1"`, "expected an object or array"},
+		{"array of numbers", `[1, 2]`, "failed to parse issue at index 0"},
+		{"no issues field", `{"total": 0}`, `expected an issue or an object with "issues"`},
+		{"error response", `{"errorMessages": ["Field 'foo' does not exist"], "errors": {}}`, "Jira error response: Field 'foo' does not exist"},
+		{"empty issues", `{"issues": []}`, "export contains no issues"},
+		{"empty search page", `{"startAt": 0, "maxResults": 50, "total": 0, "issues": []}`, "export contains no issues"},
+		{"empty input", ``, "export contains no issues"},
+		{"issues not an array", `{"issues": {}}`, "expected"},
+		{"invalid issue", `{"issues": [{"key": 5}]}`, "failed to parse issue at index 0"},
+		{"missing summary", `{"issues": [{"key": "PROJ-1", "fields": {"issuetype": {"name": "Task"}}}]}`, "has no summary"},
+		{"truncated", `{"issues": [{"key": "PROJ-1", "fields": {"summary": "Test", "issuetype": {"name": "Task"}}}`, "unexpected end of JSON input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeAll(NewAdapter().NewIssueDecoder(strings.NewReader(tt.data)))
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+// issueStream generates a large Jira export on the fly, so it is never held
+// in memory as a whole
+func issueStream(count int) io.Reader {
+	reader, writer := io.Pipe()
+	go func() {
+		_, _ = fmt.Fprint(writer, `{"issues": [`)
+		for i := 1; i <= count; i++ {
+			if i > 1 {
+				_, _ = fmt.Fprint(writer, ",")
+			}
+			_, _ = fmt.Fprintf(writer, `{"id": "%d", "key": "PROJ-%d", "fields": {"summary": "Issue %d", "description": "%s", "issuetype": {"name": "Task"}}}`,
+				i, i, i, strings.Repeat("x", 1000))
+		}
+		_, _ = fmt.Fprint(writer, `]}`)
+		_ = writer.Close()
+	}()
+	return reader
+}
+
+func TestIssueDecoderLargeExport(t *testing.T) {
+	const count = 20000
+
+	decoder := NewAdapter().NewIssueDecoder(issueStream(count))
+	n := 0
+	for {
+		issue, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to decode issue %d: %v", n, err)
+		}
+		n++
+		if want := fmt.Sprintf("PROJ-%d", n); issue.Key != want {
+			t.Fatalf("Expected %s, got %s", want, issue.Key)
+		}
+	}
+
+	if n != count {
+		t.Errorf("Expected %d issues, got %d", count, n)
+	}
+}
+
+func TestIssueDecoderShapes(t *testing.T) {
+	issue := func(key string) string {
+		return fmt.Sprintf(`{"id": "1", "key": %q, "self": "https://jira.example.com/rest/api/2/issue/1", "fields": {"summary": "Issue %s", "issuetype": {"name": "Task"}}}`, key, key)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"export", `{"issues": [` + issue("PROJ-1") + `,` + issue("PROJ-2") + `]}`, []string{"PROJ-1", "PROJ-2"}},
+		{"search response", `{"expand": "schema,names", "startAt": 50, "maxResults": 50, "total": 52, "issues": [` +
+			issue("PROJ-51") + `,` + issue("PROJ-52") + `]}`, []string{"PROJ-51", "PROJ-52"}},
+		{"single issue", `{"expand": "renderedFields", ` + issue("PROJ-7")[1:], []string{"PROJ-7"}},
+		{"array of issues", `[` + issue("PROJ-1") + `,` + issue("PROJ-2") + `]`, []string{"PROJ-1", "PROJ-2"}},
+		{"array of pages", `[{"issues": [` + issue("PROJ-1") + `]}, {"issues": [` + issue("PROJ-2") + `]}]`, []string{"PROJ-1", "PROJ-2"}},
+		{"JSONL pages", `{"startAt": 0, "total": 3, "issues": [` + issue("PROJ-1") + `,` + issue("PROJ-2") + `]}` + "\n" +
+			`{"startAt": 2, "total": 3, "issues": [` + issue("PROJ-3") + `]}` + "\n", []string{"PROJ-1", "PROJ-2", "PROJ-3"}},
+		{"JSONL issues", issue("PROJ-1") + "\n" + issue("PROJ-2") + "\n", []string{"PROJ-1", "PROJ-2"}},
+		{"mixed", issue("PROJ-1") + `{"issues": [` + issue("PROJ-2") + `]}[` + issue("PROJ-3") + `]`, []string{"PROJ-1", "PROJ-2", "PROJ-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := decodeAll(NewAdapter().NewIssueDecoder(strings.NewReader(tt.data)))
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			var keys []string
+			for _, issue := range issues {
+				keys = append(keys, issue.Key)
+			}
+			if strings.Join(keys, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected issues %v, got %v", tt.want, keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package jira
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// TransportConfig configures how the client connects to Jira: custom CA
+// bundles, client certificates (mutual TLS) and proxies
+type TransportConfig struct {
+	CAFile             string // PEM bundle trusted in addition to the system roots
+	ClientCert         string // PEM client certificate for mutual TLS
+	ClientKey          string // PEM private key of ClientCert
+	ProxyURL           string // proxy for all requests; HTTPS_PROXY/HTTP_PROXY/NO_PROXY are used if empty
+	InsecureSkipVerify bool   // skip server certificate verification; for testing only
+}
+
+// SetTransport configures the client's TLS and proxy settings
+func (c *Client) SetTransport(config TransportConfig) error {
+	transport, err := newTransport(config)
+	if err != nil {
+		return err
+	}
+	c.httpClient.Transport = transport
+	return nil
+}
+
+// newTransport builds an HTTP transport from the default one with the given
+// TLS and proxy settings
+func newTransport(config TransportConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL: %s", config.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in CA file %s", config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.ClientCert != "" || config.ClientKey != "" {
+		if config.ClientCert == "" || config.ClientKey == "" {
+			return nil, fmt.Errorf("client certificate and client key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// DiagnoseTLSError explains a TLS handshake failure and how to fix it in the
+// configuration. It returns "" if err isn't a TLS error.
+func DiagnoseTLSError(err error) string {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCert x509.CertificateInvalidError
+	var recordHeader tls.RecordHeaderError
+
+	switch {
+	case errors.As(err, &unknownAuthority):
+		subject := ""
+		if unknownAuthority.Cert != nil {
+			subject = fmt.Sprintf(" (issued by %q)", unknownAuthority.Cert.Issuer.String())
+		}
+		return fmt.Sprintf("The server certificate is signed by an unknown authority%s. "+
+			"Set ca_file to your organisation's CA bundle.", subject)
+	case errors.As(err, &hostnameErr):
+		return fmt.Sprintf("The server certificate is not valid for %s. "+
+			"Check base_url uses the host name the certificate was issued for.", hostnameErr.Host)
+	case errors.As(err, &invalidCert):
+		return fmt.Sprintf("The server certificate is invalid: %v. "+
+			"Check the certificate hasn't expired and the system clock is correct.", invalidCert)
+	case errors.As(err, &recordHeader):
+		return "The server did not answer with TLS. " +
+			"Check base_url uses the right scheme and port, and proxy_url if you use a proxy."
+	}
+
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "tls: certificate required"),
+		strings.Contains(message, "tls: bad certificate"):
+		return "The server requires a client certificate. Set client_cert and client_key."
+	case strings.Contains(message, "tls: unknown certificate authority"):
+		return "The server rejected the client certificate. Check client_cert is signed by a CA the server trusts."
+	case strings.Contains(message, "tls: handshake failure"),
+		strings.Contains(message, "tls: protocol version not supported"):
+		return "The TLS handshake failed. The server may require a client certificate or an unsupported TLS version."
+	}
+
+	return ""
+}
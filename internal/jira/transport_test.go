@@ -0,0 +1,199 @@
+package jira
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const currentUserResponse = `{"accountId":"abc","displayName":"Test User","active":true}`
+
+func currentUserHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(currentUserResponse))
+}
+
+// writeServerCA writes the certificate of a TLS test server to a PEM file
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+	return path
+}
+
+// writeClientCert writes a self-signed client certificate and its key to PEM
+// files, returning the certificate for the server to trust
+func writeClientCert(t *testing.T) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "jira-beads-sync test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+
+	return cert, certFile, keyFile
+}
+
+func TestTransportCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(currentUserHandler))
+	defer server.Close()
+
+	// Without the CA the handshake fails with a diagnosis
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	_, err := client.GetCurrentUser()
+	if err == nil {
+		t.Fatal("Expected an error for an untrusted server certificate")
+	}
+	if diagnosis := DiagnoseTLSError(err); !strings.Contains(diagnosis, "ca_file") {
+		t.Errorf("Expected diagnosis suggesting ca_file, got: %q", diagnosis)
+	}
+
+	// With the CA the request succeeds
+	if err := client.SetTransport(TransportConfig{CAFile: writeServerCA(t, server)}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := client.GetCurrentUser(); err != nil {
+		t.Errorf("Expected no error with CA file, got: %v", err)
+	}
+}
+
+func TestTransportInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(currentUserHandler))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	if err := client.SetTransport(TransportConfig{InsecureSkipVerify: true}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := client.GetCurrentUser(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestTransportClientCertificate(t *testing.T) {
+	clientCert, certFile, keyFile := writeClientCert(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(currentUserHandler))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := writeServerCA(t, server)
+
+	// Without a client certificate the server rejects the handshake
+	client := NewClient(server.URL, "user@example.com", "token123", "basic")
+	if err := client.SetTransport(TransportConfig{CAFile: caFile}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	_, err := client.GetCurrentUser()
+	if err == nil {
+		t.Fatal("Expected an error without a client certificate")
+	}
+	if diagnosis := DiagnoseTLSError(err); !strings.Contains(diagnosis, "client_cert") {
+		t.Errorf("Expected diagnosis suggesting client_cert, got: %q (error: %v)", diagnosis, err)
+	}
+
+	if err := client.SetTransport(TransportConfig{CAFile: caFile, ClientCert: certFile, ClientKey: keyFile}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := client.GetCurrentUser(); err != nil {
+		t.Errorf("Expected no error with client certificate, got: %v", err)
+	}
+}
+
+func TestTransportProxyURL(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute URL of the target
+		proxied = append(proxied, r.URL.String())
+		currentUserHandler(w, r)
+	}))
+	defer proxy.Close()
+
+	client := NewClient("http://jira.internal.example", "user@example.com", "token123", "basic")
+	if err := client.SetTransport(TransportConfig{ProxyURL: proxy.URL}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := client.GetCurrentUser(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(proxied) != 1 || proxied[0] != "http://jira.internal.example/rest/api/2/myself" {
+		t.Errorf("Expected request through proxy, got %v", proxied)
+	}
+}
+
+func TestTransportConfigErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   TransportConfig
+		errorMsg string
+	}{
+		{"missing CA file", TransportConfig{CAFile: "/nonexistent/ca.pem"}, "failed to read CA file"},
+		{"cert without key", TransportConfig{ClientCert: "client.pem"}, "must be set together"},
+		{"invalid proxy", TransportConfig{ProxyURL: "not a url"}, "invalid proxy URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("https://jira.example.com", "user", "token", "basic")
+			err := client.SetTransport(tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestDiagnoseTLSErrorNonTLS(t *testing.T) {
+	if diagnosis := DiagnoseTLSError(os.ErrNotExist); diagnosis != "" {
+		t.Errorf("Expected no diagnosis for a non-TLS error, got: %q", diagnosis)
+	}
+}
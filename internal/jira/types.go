@@ -2,9 +2,53 @@ package jira
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
+// timestampLayouts are the timestamp formats found in Jira exports and API
+// responses. Fractional seconds of any precision are accepted after the
+// seconds of every layout.
+var timestampLayouts = []string{
+	"2006-01-02T15:04:05-0700", // Jira, e.g. "2024-01-01T10:00:00.000+0000"
+	time.RFC3339,               // "2024-01-01T10:00:00Z" or "...+00:00"
+	"2006-01-02T15:04:05-07",
+	"2006-01-02",
+}
+
+// ParseTimestamp parses a timestamp in any of the formats Jira uses: its own
+// "2006-01-02T15:04:05.000-0700", RFC 3339 with "Z" or "+00:00", either
+// without milliseconds, or a plain date, which is taken as midnight UTC.
+// Timestamps without a UTC offset are rejected, since their time is
+// ambiguous. The time keeps the offset it was given with.
+func ParseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timestampLayouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		// Offsets of a day or more can't be formatted as RFC 3339 again
+		if _, offset := t.Zone(); offset <= -24*60*60 || offset >= 24*60*60 {
+			return time.Time{}, fmt.Errorf("timestamp %q has an invalid UTC offset", value)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognised timestamp %q", value)
+}
+
+// ParseDate parses a date such as a due date, which may also be given as a
+// timestamp. The result is midnight UTC of the date as written, so it isn't
+// moved to another day by the offset of a timestamp.
+func ParseDate(value string) (time.Time, error) {
+	t, err := ParseTimestamp(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
 // JiraTime is a custom time type that handles Jira's timestamp format
 type JiraTime struct {
 	time.Time
@@ -22,7 +66,7 @@ func (jt *JiraTime) UnmarshalJSON(b []byte) error {
 	}
 
 	// Jira uses format like "2024-01-01T10:00:00.000+0000"
-	t, err := time.Parse("2006-01-02T15:04:05.000-0700", s)
+	t, err := ParseTimestamp(s)
 	if err != nil {
 		return err
 	}
@@ -31,6 +75,32 @@ func (jt *JiraTime) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// JiraDate is a custom time type that handles Jira's date-only format
+type JiraDate struct {
+	time.Time
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for Jira dates
+func (jd *JiraDate) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	if s == "" {
+		return nil
+	}
+
+	// Jira uses format like "2024-03-01" for due dates
+	t, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+
+	jd.Time = t
+	return nil
+}
+
 // Export represents a Jira export file containing multiple issues
 type Export struct {
 	Issues []Issue `json:"issues"`
@@ -60,6 +130,13 @@ type Fields struct {
 	Parent      *Parent     `json:"parent,omitempty"`
 	Epic        *Epic       `json:"epic,omitempty"`
 	Subtasks    []Subtask   `json:"subtasks"`
+	FixVersions []Version   `json:"fixVersions"`
+	Versions    []Version   `json:"versions"` // "Affects versions"
+	Components  []Component `json:"components"`
+
+	DueDate        JiraDate    `json:"duedate"`
+	Resolution     *Resolution `json:"resolution,omitempty"` // nil while unresolved
+	ResolutionDate JiraTime    `json:"resolutiondate"`
 }
 
 // IssueType represents the type of a Jira issue
@@ -81,6 +158,13 @@ type StatusCategory struct {
 	Name string `json:"name"`
 }
 
+// Resolution represents how a Jira issue was resolved (e.g., "Done", "Won't Do")
+type Resolution struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
 // Priority represents the priority of a Jira issue
 type Priority struct {
 	Name string `json:"name"`
@@ -149,3 +233,18 @@ type Subtask struct {
 	Self   string       `json:"self"`
 	Fields LinkedFields `json:"fields"`
 }
+
+// Version represents a project version (release) referenced by an issue
+type Version struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Released    bool   `json:"released"`
+	Archived    bool   `json:"archived"`
+	ReleaseDate string `json:"releaseDate,omitempty"` // e.g., "2024-03-01"
+}
+
+// Component represents a project component referenced by an issue
+type Component struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
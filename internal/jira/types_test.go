@@ -224,3 +224,146 @@ func TestJiraTimeEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestJiraDateUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantTime time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "valid Jira date",
+			input:    `"2024-03-01"`,
+			wantTime: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "empty string",
+			input: `""`,
+		},
+		{
+			name:  "null value",
+			input: `null`,
+		},
+		{
+			name:    "invalid format",
+			input:   `"01/03/2024"`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var jd JiraDate
+			err := json.Unmarshal([]byte(tt.input), &jd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !jd.Equal(tt.wantTime) {
+				t.Errorf("UnmarshalJSON() = %v, want %v", jd.Time, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-01-01T10:00:00.000+0000", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"2024-01-01T10:00:00+0000", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"2024-01-01T10:00:00.123456-0500", time.Date(2024, 1, 1, 15, 0, 0, 123456000, time.UTC)},
+		{"2024-01-01T10:00:00Z", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"2024-01-01T10:00:00.5Z", time.Date(2024, 1, 1, 10, 0, 0, 500000000, time.UTC)},
+		{"2024-01-01T10:00:00+00:00", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"2024-01-08T09:00:00.000+10:00", time.Date(2024, 1, 7, 23, 0, 0, 0, time.UTC)},
+		{"2024-01-01T10:00:00+02", time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)},
+		{" 2024-03-01 ", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTimestamp(tt.value)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "yesterday", "2024-01-01T10:00:00", "01/03/2024", "2024-13-01", "2024-01-01T10:00:00+9900"} {
+		if _, err := ParseTimestamp(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	// The date is kept as written, even where the offset puts it on
+	// another day in UTC
+	got, err := ParseDate("2024-03-01T00:30:00.000+1000")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestJiraTimeUnmarshalJSONFormats(t *testing.T) {
+	want := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	for _, input := range []string{`"2024-01-01T10:00:00Z"`, `"2024-01-01T10:00:00+00:00"`, `"2024-01-01T10:00:00+0000"`} {
+		var jt JiraTime
+		if err := json.Unmarshal([]byte(input), &jt); err != nil {
+			t.Fatalf("Failed to unmarshal %s: %v", input, err)
+		}
+		if !jt.Equal(want) {
+			t.Errorf("%s = %v, want %v", input, jt.Time, want)
+		}
+	}
+}
+
+func FuzzParseTimestamp(f *testing.F) {
+	for _, seed := range []string{
+		"2024-01-01T10:00:00.000+0000",
+		"2024-06-15T14:30:45.123-0700",
+		"2024-01-01T10:00:00Z",
+		"2024-01-01T10:00:00.123456789+05:30",
+		"2024-01-01T10:00:00+02",
+		"2024-03-01",
+		"2024-02-30",
+		"2024-01-01T24:00:00Z",
+		"2024-01-01T10:00:00+9959",
+		"0000-01-01T00:00:00-2359",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		got, err := ParseTimestamp(value)
+		if err != nil {
+			return
+		}
+
+		// Anything accepted survives being written as RFC 3339 and read back
+		again, err := ParseTimestamp(got.Format(time.RFC3339Nano))
+		if err != nil {
+			t.Fatalf("ParseTimestamp(%q) = %v, which doesn't parse again: %v", value, got, err)
+		}
+		if !again.Equal(got) {
+			t.Fatalf("ParseTimestamp(%q) = %v, but read back as %v", value, got, again)
+		}
+
+		date, err := ParseDate(value)
+		if err != nil {
+			t.Fatalf("ParseDate(%q) failed where ParseTimestamp succeeded: %v", value, err)
+		}
+		if date.Year() != got.Year() || date.YearDay() != got.YearDay() || date.Location() != time.UTC {
+			t.Fatalf("ParseDate(%q) = %v, want the date of %v", value, date, got)
+		}
+	})
+}
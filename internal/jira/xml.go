@@ -0,0 +1,377 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "github.com/conallob/jira-beads-sync/gen/jira"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Custom field types that XML exports only carry as custom fields
+const (
+	epicLinkFieldKey = "com.pyxis.greenhopper.jira:gh-epic-link"
+	sprintFieldKey   = "com.pyxis.greenhopper.jira:gh-sprint"
+	rankFieldKey     = "com.pyxis.greenhopper.jira:gh-lexo-rank"
+)
+
+// xmlDateLayout is the RFC 1123 style date of XML exports, with an unpadded
+// day, e.g. "Mon, 1 Jan 2024 10:00:00 +0000"
+const xmlDateLayout = "Mon, 2 Jan 2006 15:04:05 -0700"
+
+var (
+	htmlBreak = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</li>|</h[1-6]>|</tr>`)
+	htmlTag   = regexp.MustCompile(`<[^>]*>`)
+	blankRuns = regexp.MustCompile(`\n{3,}`)
+)
+
+// ParseXML parses a Jira XML (RSS) export into protobuf, including issue
+// links, subtasks, parents, comments and custom fields
+func (a *Adapter) ParseXML(data []byte) (*pb.Export, error) {
+	a.diagnostics = nil
+	var rss xmlRSS
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&rss); err != nil {
+		return nil, fmt.Errorf("failed to parse Jira XML export: %w", err)
+	}
+
+	export := &pb.Export{}
+	var subtaskParents []bool
+
+	for i := range rss.Items {
+		issue, problems := a.convertXMLItem(&rss.Items[i])
+		diagnostics, skip, err := a.checkIssue(i, issue, problems, xmlPaths)
+		if err != nil {
+			return nil, err
+		}
+		a.diagnostics = append(a.diagnostics, diagnostics...)
+		if skip {
+			continue
+		}
+		export.Issues = append(export.Issues, issue)
+		// Server exports only give subtasks a <parent>
+		subtaskParents = append(subtaskParents, rss.Items[i].Parent != nil)
+	}
+	if len(export.Issues) == 0 {
+		return nil, fmt.Errorf("validation failed: export contains no issues")
+	}
+
+	resolveReferences(export, subtaskParents)
+
+	return export, nil
+}
+
+// convertXMLItem converts an XML export item to protobuf, leaving out and
+// returning the elements that couldn't be parsed
+func (a *Adapter) convertXMLItem(item *xmlItem) (*pb.Issue, []*fieldError) {
+	status := strings.TrimSpace(item.Status.Value)
+	category := statusCategory("", status)
+	if item.StatusCategory != nil && item.StatusCategory.Key != "" {
+		category = &pb.StatusCategory{Key: item.StatusCategory.Key}
+	}
+
+	issue := &pb.Issue{
+		Id:   item.Key.ID,
+		Key:  strings.TrimSpace(item.Key.Value),
+		Self: strings.TrimSpace(item.Link),
+		Fields: &pb.Fields{
+			Summary:     strings.TrimSpace(item.Summary),
+			Description: htmlText(item.Description),
+			IssueType: &pb.IssueType{
+				Name:    strings.TrimSpace(item.Type.Value),
+				Subtask: isSubtaskType(strings.TrimSpace(item.Type.Value)),
+			},
+			Status: &pb.Status{
+				Name:           status,
+				StatusCategory: category,
+			},
+			Priority: &pb.Priority{
+				Name: strings.TrimSpace(item.Priority.Value),
+				Id:   item.Priority.ID,
+			},
+			Labels: item.Labels,
+		},
+	}
+
+	issue.Fields.Assignee = item.Assignee.user()
+	issue.Fields.Reporter = item.Reporter.user()
+
+	// Unresolved issues have the pseudo resolution -1
+	if item.Resolution.ID != "" && item.Resolution.ID != "-1" {
+		issue.Fields.Resolution = &pb.Resolution{
+			Id:   item.Resolution.ID,
+			Name: strings.TrimSpace(item.Resolution.Value),
+		}
+	}
+
+	var problems []*fieldError
+	dates := []struct {
+		element string
+		value   string
+		parse   func(string) (time.Time, error)
+		target  **timestamppb.Timestamp
+	}{
+		{"created", item.Created, parseXMLDate, &issue.Fields.Created},
+		{"updated", item.Updated, parseXMLDate, &issue.Fields.Updated},
+		{"due", item.Due, parseXMLDueDate, &issue.Fields.DueDate},
+		{"resolved", item.Resolved, parseXMLDate, &issue.Fields.ResolutionDate},
+	}
+	for _, date := range dates {
+		if strings.TrimSpace(date.value) == "" {
+			continue
+		}
+		t, err := date.parse(date.value)
+		if err != nil {
+			problems = append(problems, &fieldError{date.element, fmt.Errorf("invalid %s date: %w", date.element, err)})
+			continue
+		}
+		*date.target = timestamppb.New(t)
+	}
+
+	for _, version := range item.FixVersions {
+		issue.Fields.FixVersions = append(issue.Fields.FixVersions, &pb.Version{Name: strings.TrimSpace(version)})
+	}
+	for _, version := range item.Versions {
+		issue.Fields.Versions = append(issue.Fields.Versions, &pb.Version{Name: strings.TrimSpace(version)})
+	}
+	for _, component := range item.Components {
+		issue.Fields.Components = append(issue.Fields.Components, &pb.Component{Name: strings.TrimSpace(component)})
+	}
+
+	for i, comment := range item.Comments {
+		pbComment := &pb.Comment{
+			Id:   comment.ID,
+			Body: htmlText(comment.Body),
+		}
+		if comment.Author != "" {
+			pbComment.Author = &pb.User{AccountId: comment.Author}
+		}
+		if comment.Created != "" {
+			if t, err := parseXMLDate(comment.Created); err != nil {
+				path := fmt.Sprintf("comments/comment[%d]/@created", i+1)
+				problems = append(problems, &fieldError{path, fmt.Errorf("invalid date of comment %s: %w", comment.ID, err)})
+			} else {
+				pbComment.Created = timestamppb.New(t)
+			}
+		}
+		issue.Fields.Comments = append(issue.Fields.Comments, pbComment)
+	}
+
+	for _, linkType := range item.IssueLinkTypes {
+		pbType := &pb.IssueLinkType{
+			Name:    strings.TrimSpace(linkType.Name),
+			Inward:  linkType.InwardLinks.Description,
+			Outward: linkType.OutwardLinks.Description,
+		}
+		for _, link := range linkType.OutwardLinks.Links {
+			issue.Fields.IssueLinks = append(issue.Fields.IssueLinks, &pb.IssueLink{
+				Type:         pbType,
+				OutwardIssue: &pb.LinkedIssue{Id: link.IssueKey.ID, Key: strings.TrimSpace(link.IssueKey.Value)},
+			})
+		}
+		for _, link := range linkType.InwardLinks.Links {
+			issue.Fields.IssueLinks = append(issue.Fields.IssueLinks, &pb.IssueLink{
+				Type:        pbType,
+				InwardIssue: &pb.LinkedIssue{Id: link.IssueKey.ID, Key: strings.TrimSpace(link.IssueKey.Value)},
+			})
+		}
+	}
+
+	for _, subtask := range item.Subtasks {
+		issue.Fields.Subtasks = append(issue.Fields.Subtasks, &pb.Subtask{
+			Id:  subtask.ID,
+			Key: strings.TrimSpace(subtask.Value),
+		})
+	}
+
+	if item.Parent != nil {
+		issue.Fields.Parent = &pb.Parent{
+			Id:     item.Parent.ID,
+			Key:    strings.TrimSpace(item.Parent.Value),
+			Fields: &pb.LinkedFields{},
+		}
+	}
+
+	for _, field := range item.CustomFields {
+		pbField := &pb.CustomField{
+			Id:   field.ID,
+			Key:  field.Key,
+			Name: strings.TrimSpace(field.Name),
+		}
+		for _, value := range field.Values {
+			pbField.Values = append(pbField.Values, strings.TrimSpace(value.Value))
+		}
+		issue.Fields.CustomFields = append(issue.Fields.CustomFields, pbField)
+
+		if len(pbField.Values) == 0 {
+			continue
+		}
+		switch {
+		case field.Key == epicLinkFieldKey && issue.Fields.Parent == nil:
+			issue.Fields.Parent = &pb.Parent{
+				Key:    pbField.Values[0],
+				Fields: &pb.LinkedFields{IssueType: &pb.IssueType{Name: "Epic"}},
+			}
+		case field.Key == sprintFieldKey:
+			convertXMLSprints(issue.Fields, field.Values)
+		case field.Key == rankFieldKey || (a.rankField != "" && field.ID == a.rankField):
+			issue.Fields.Rank = pbField.Values[0]
+		}
+	}
+
+	return issue, problems
+}
+
+// convertXMLSprints sets the current and closed sprints of an issue. Values
+// carry the sprint state when the export has it; otherwise the last sprint
+// is taken as the current one.
+func convertXMLSprints(fields *pb.Fields, values []xmlCustomFieldValue) {
+	for i, value := range values {
+		sprint := &pb.Sprint{
+			Name:  strings.TrimSpace(value.Value),
+			State: strings.ToLower(value.State),
+		}
+		current := sprint.State == "active" || sprint.State == "future"
+		if sprint.State == "" {
+			current = i == len(values)-1
+		}
+		if current {
+			fields.Sprint = sprint
+		} else {
+			fields.ClosedSprints = append(fields.ClosedSprints, sprint)
+		}
+	}
+}
+
+// htmlText converts the HTML of XML export descriptions and comments to
+// plain text, keeping paragraph and line breaks
+func htmlText(s string) string {
+	s = htmlBreak.ReplaceAllString(s, "$0\n")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	s = blankRuns.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(s)
+}
+
+// parseXMLDate parses a date of an XML export
+func parseXMLDate(value string) (time.Time, error) {
+	t, err := time.Parse(xmlDateLayout, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognised date %q", value)
+	}
+	return t, nil
+}
+
+// parseXMLDueDate parses the due date of an XML export. Jira writes it as
+// midnight in the exporter's timezone, so the calendar date is kept as a
+// plain date at midnight UTC, as ParseDate does for JSON.
+func parseXMLDueDate(value string) (time.Time, error) {
+	t, err := parseXMLDate(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// XML types for unmarshaling (kept internal)
+type xmlRSS struct {
+	Items []xmlItem `xml:"channel>item"`
+}
+
+type xmlItem struct {
+	Link           string             `xml:"link"`
+	Key            xmlValue           `xml:"key"`
+	Summary        string             `xml:"summary"`
+	Description    string             `xml:"description"`
+	Type           xmlValue           `xml:"type"`
+	Parent         *xmlValue          `xml:"parent"`
+	Priority       xmlValue           `xml:"priority"`
+	Status         xmlValue           `xml:"status"`
+	StatusCategory *xmlStatusCategory `xml:"statusCategory"`
+	Resolution     xmlValue           `xml:"resolution"`
+	Assignee       xmlUser            `xml:"assignee"`
+	Reporter       xmlUser            `xml:"reporter"`
+	Labels         []string           `xml:"labels>label"`
+	Created        string             `xml:"created"`
+	Updated        string             `xml:"updated"`
+	Resolved       string             `xml:"resolved"`
+	Due            string             `xml:"due"`
+	Versions       []string           `xml:"version"`
+	FixVersions    []string           `xml:"fixVersion"`
+	Components     []string           `xml:"component"`
+	Comments       []xmlComment       `xml:"comments>comment"`
+	IssueLinkTypes []xmlIssueLinkType `xml:"issuelinks>issuelinktype"`
+	Subtasks       []xmlValue         `xml:"subtasks>subtask"`
+	CustomFields   []xmlCustomField   `xml:"customfields>customfield"`
+}
+
+// xmlValue is an element whose text is a name or key and whose id attribute
+// is the Jira ID, e.g. <key id="10001">PROJ-1</key>
+type xmlValue struct {
+	ID    string `xml:"id,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlStatusCategory struct {
+	Key string `xml:"key,attr"`
+}
+
+type xmlUser struct {
+	AccountID string `xml:"accountid,attr"`
+	Username  string `xml:"username,attr"`
+	Name      string `xml:",chardata"`
+}
+
+// user converts an XML user to protobuf; unassigned users have the ID -1
+func (u xmlUser) user() *pb.User {
+	id := u.AccountID
+	if id == "" {
+		id = u.Username
+	}
+	name := strings.TrimSpace(u.Name)
+	if id == "-1" || (id == "" && name == "") {
+		return nil
+	}
+	return &pb.User{AccountId: id, DisplayName: name}
+}
+
+type xmlComment struct {
+	ID      string `xml:"id,attr"`
+	Author  string `xml:"author,attr"`
+	Created string `xml:"created,attr"`
+	Body    string `xml:",chardata"`
+}
+
+type xmlIssueLinkType struct {
+	Name         string       `xml:"name"`
+	OutwardLinks xmlLinkGroup `xml:"outwardlinks"`
+	InwardLinks  xmlLinkGroup `xml:"inwardlinks"`
+}
+
+type xmlLinkGroup struct {
+	Description string `xml:"description,attr"`
+	Links       []struct {
+		IssueKey xmlValue `xml:"issuekey"`
+	} `xml:"issuelink"`
+}
+
+type xmlCustomField struct {
+	ID     string                `xml:"id,attr"`
+	Key    string                `xml:"key,attr"`
+	Name   string                `xml:"customfieldname"`
+	Values []xmlCustomFieldValue `xml:"customfieldvalues>customfieldvalue"`
+}
+
+type xmlCustomFieldValue struct {
+	State string `xml:"state,attr"`
+	Value string `xml:",chardata"`
+}
@@ -0,0 +1,195 @@
+package jira
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdapterParseXMLFile(t *testing.T) {
+	adapter := NewAdapter()
+	export, err := adapter.ParseFile("../../testdata/sample-jira-export.xml")
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	if len(export.Issues) != 4 {
+		t.Fatalf("Expected 4 issues, got %d", len(export.Issues))
+	}
+
+	epic := export.Issues[0]
+	if epic.Fields.Description != "Add authentication system with login and signup" {
+		t.Errorf("Expected description as plain text, got %q", epic.Fields.Description)
+	}
+	if epic.Fields.Resolution != nil {
+		t.Errorf("Expected no resolution for an unresolved issue, got %v", epic.Fields.Resolution)
+	}
+	if len(epic.Fields.Subtasks) != 2 || epic.Fields.Subtasks[0].Fields.Summary != "Create login API endpoint" {
+		t.Errorf("Expected subtasks with resolved fields, got %v", epic.Fields.Subtasks)
+	}
+
+	issue := export.Issues[1]
+	if issue.Id != "10002" || issue.Self != "https://jira.example.com/browse/PROJ-2" {
+		t.Errorf("Expected id 10002 and link, got %s %s", issue.Id, issue.Self)
+	}
+	if !issue.Fields.IssueType.Subtask {
+		t.Error("Expected Subtask issue type to be a subtask")
+	}
+	if issue.Fields.Status.StatusCategory.Key != "new" || issue.Fields.Priority.Id != "2" {
+		t.Errorf("Expected status category 'new' and priority 2, got %v %v", issue.Fields.Status, issue.Fields.Priority)
+	}
+	if issue.Fields.Assignee == nil || issue.Fields.Assignee.AccountId != "jdoe" || issue.Fields.Assignee.DisplayName != "John Doe" {
+		t.Errorf("Expected assignee jdoe, got %v", issue.Fields.Assignee)
+	}
+	if got := strings.Join(issue.Fields.Labels, ","); got != "api,backend" {
+		t.Errorf("Expected labels api,backend, got %s", got)
+	}
+	if len(issue.Fields.FixVersions) != 1 || len(issue.Fields.Components) != 1 {
+		t.Errorf("Expected a fix version and a component, got %v %v", issue.Fields.FixVersions, issue.Fields.Components)
+	}
+
+	want := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	if got := issue.Fields.Created.AsTime(); !got.Equal(want) {
+		t.Errorf("Expected created %v, got %v", want, got)
+	}
+	if issue.Fields.DueDate == nil {
+		t.Error("Expected a due date")
+	}
+
+	if len(issue.Fields.Comments) != 1 {
+		t.Fatalf("Expected 1 comment, got %d", len(issue.Fields.Comments))
+	}
+	comment := issue.Fields.Comments[0]
+	if comment.Id != "20001" || comment.Author.AccountId != "jsmith" || comment.Body != "Waiting on the users table.\nSee PROJ-4." {
+		t.Errorf("Unexpected comment: %v", comment)
+	}
+
+	if issue.Fields.Sprint == nil || issue.Fields.Sprint.Name != "Sprint 2" || issue.Fields.Sprint.State != "active" {
+		t.Errorf("Expected active sprint 'Sprint 2', got %v", issue.Fields.Sprint)
+	}
+	if len(issue.Fields.ClosedSprints) != 1 || issue.Fields.ClosedSprints[0].Name != "Sprint 1" {
+		t.Errorf("Expected closed sprint 'Sprint 1', got %v", issue.Fields.ClosedSprints)
+	}
+	if issue.Fields.Rank != "0|i0000f:" {
+		t.Errorf("Expected rank from the Rank custom field, got %q", issue.Fields.Rank)
+	}
+	if len(issue.Fields.CustomFields) != 2 || issue.Fields.CustomFields[0].Name != "Sprint" ||
+		len(issue.Fields.CustomFields[0].Values) != 2 {
+		t.Errorf("Expected the custom fields to be kept, got %v", issue.Fields.CustomFields)
+	}
+
+	if export.Issues[2].Fields.Assignee != nil {
+		t.Errorf("Expected unassigned issue, got %v", export.Issues[2].Fields.Assignee)
+	}
+
+	done := export.Issues[3]
+	if done.Fields.Resolution == nil || done.Fields.Resolution.Name != "Done" || done.Fields.ResolutionDate == nil {
+		t.Errorf("Expected resolution Done with date, got %v %v", done.Fields.Resolution, done.Fields.ResolutionDate)
+	}
+	link := done.Fields.IssueLinks[0]
+	if link.Type.Outward != "blocks" || link.OutwardIssue.Key != "PROJ-2" || link.OutwardIssue.Fields.Summary != "Create login API endpoint" {
+		t.Errorf("Expected PROJ-4 to block PROJ-2, got %v", link)
+	}
+}
+
+func TestAdapterParseXMLEpicLink(t *testing.T) {
+	xml := `<rss version="0.92"><channel>
+<item>
+  <key id="1">PROJ-1</key><summary>Epic</summary><type>Epic</type><status>Open</status>
+</item>
+<item>
+  <key id="2">PROJ-2</key><summary>Story</summary><type>Story</type><status>In Progress</status>
+  <customfields>
+    <customfield id="customfield_10014" key="com.pyxis.greenhopper.jira:gh-epic-link">
+      <customfieldname>Epic Link</customfieldname>
+      <customfieldvalues><customfieldvalue>PROJ-1</customfieldvalue></customfieldvalues>
+    </customfield>
+  </customfields>
+</item>
+</channel></rss>`
+
+	export, err := NewAdapter().ParseXML([]byte(xml))
+	if err != nil {
+		t.Fatalf("Failed to parse XML: %v", err)
+	}
+
+	story := export.Issues[1]
+	if story.Fields.Parent == nil || story.Fields.Parent.Key != "PROJ-1" || story.Fields.Parent.Id != "1" {
+		t.Fatalf("Expected the Epic Link to become the parent, got %v", story.Fields.Parent)
+	}
+	if story.Fields.IssueType.Subtask {
+		t.Error("Expected a story in an epic not to be a subtask")
+	}
+	// Without a statusCategory element the category is guessed from the status
+	if story.Fields.Status.StatusCategory.Key != "indeterminate" {
+		t.Errorf("Expected status category 'indeterminate', got %q", story.Fields.Status.StatusCategory.Key)
+	}
+}
+
+func TestAdapterParseXMLDueDateOffset(t *testing.T) {
+	xml := `<rss version="0.92"><channel>
+<item>
+  <key id="1">PROJ-1</key><summary>Task</summary><type>Task</type><status>Open</status>
+  <created>Fri, 1 Mar 2024 09:30:00 +1100</created>
+  <due>Fri, 1 Mar 2024 00:00:00 +1100</due>
+</item>
+</channel></rss>`
+
+	export, err := NewAdapter().ParseXML([]byte(xml))
+	if err != nil {
+		t.Fatalf("Failed to parse XML: %v", err)
+	}
+
+	fields := export.Issues[0].Fields
+	// The due date keeps its calendar day rather than moving to the day before in UTC
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC); !fields.DueDate.AsTime().Equal(want) {
+		t.Errorf("Expected due date %v, got %v", want, fields.DueDate.AsTime())
+	}
+	// Timestamps are instants and are converted
+	if want := time.Date(2024, 2, 29, 22, 30, 0, 0, time.UTC); !fields.Created.AsTime().Equal(want) {
+		t.Errorf("Expected created %v, got %v", want, fields.Created.AsTime())
+	}
+}
+
+func TestAdapterParseXMLErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		xml      string
+		errorMsg string
+	}{
+		{"malformed", `<rss><channel><item>`, "failed to parse Jira XML export"},
+		{"no issues", `<rss><channel></channel></rss>`, "export contains no issues"},
+		{"invalid date", `<rss><channel><item><key>PROJ-1</key><summary>Test</summary><type>Task</type>` +
+			`<created>yesterday</created></item></channel></rss>`, "invalid created date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAdapter().ParseXML([]byte(tt.xml))
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestHTMLText(t *testing.T) {
+	tests := []struct {
+		html string
+		want string
+	}{
+		{"<p>Hello &amp; welcome</p>", "Hello & welcome"},
+		{"<p>First</p>\n<p>Second</p>", "First\n\nSecond"},
+		{"line one<br/>line two", "line one\nline two"},
+		{"<ul><li>a</li><li>b</li></ul>", "a\nb"},
+		{"plain text", "plain text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.html, func(t *testing.T) {
+			if got := htmlText(tt.html); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}